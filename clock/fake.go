@@ -0,0 +1,159 @@
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Fake is a Clock that only moves when it is advanced. Its timers go off
+// in order of when they are due, and only during Advance: the functions of
+// AfterFunc run on the goroutine calling Advance, so everything due has
+// happened by the time it returns. Advance(0) fires the timers already due.
+type Fake struct {
+	mu      sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	pending []*fakeTimer
+}
+
+// NewFake returns a Fake clock stopped at now.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.changed = sync.NewCond(&f.mu)
+	return f
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	return f.NewTimer(d).C()
+}
+
+func (f *Fake) AfterFunc(d time.Duration, fn func()) Timer {
+	return f.add(d, &fakeTimer{clock: f, fn: fn})
+}
+
+// Sleep blocks until the clock is advanced by d, it returns straight away
+// when d isn't positive.
+func (f *Fake) Sleep(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	<-f.After(d)
+}
+
+func (f *Fake) NewTimer(d time.Duration) Timer {
+	return f.add(d, &fakeTimer{clock: f, c: make(chan time.Time, 1)})
+}
+
+func (f *Fake) add(d time.Duration, t *fakeTimer) *fakeTimer {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.schedule(t, d)
+	return t
+}
+
+func (f *Fake) schedule(t *fakeTimer, d time.Duration) {
+	t.due = f.now.Add(d)
+	f.pending = append(f.pending, t)
+	f.changed.Broadcast()
+}
+
+// unschedule reports whether t was pending.
+func (f *Fake) unschedule(t *fakeTimer) bool {
+	for i, p := range f.pending {
+		if p == t {
+			f.pending = append(f.pending[:i], f.pending[i+1:]...)
+			f.changed.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+// Advance moves the clock on by d, firing every timer due by then.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	end := f.now.Add(d)
+	for {
+		next := f.next(end)
+		if next == nil {
+			break
+		}
+		if next.due.After(f.now) {
+			f.now = next.due
+		}
+		now := f.now
+		f.mu.Unlock()
+		next.fire(now)
+		f.mu.Lock()
+	}
+	if end.After(f.now) {
+		f.now = end
+	}
+	f.mu.Unlock()
+}
+
+// next removes and returns the first timer due by end, nil if there is none.
+func (f *Fake) next(end time.Time) *fakeTimer {
+	var first *fakeTimer
+	for _, t := range f.pending {
+		if !t.due.After(end) && (first == nil || t.due.Before(first.due)) {
+			first = t
+		}
+	}
+	if first != nil {
+		f.unschedule(first)
+	}
+	return first
+}
+
+// BlockUntil waits until at least n timers are pending, for tests to know
+// a goroutine is waiting on the clock before advancing it.
+func (f *Fake) BlockUntil(n int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for len(f.pending) < n {
+		f.changed.Wait()
+	}
+}
+
+type fakeTimer struct {
+	clock *Fake
+	due   time.Time
+	c     chan time.Time
+	fn    func()
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.unschedule(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	pending := t.clock.unschedule(t)
+	t.clock.schedule(t, d)
+	return pending
+}
+
+func (t *fakeTimer) fire(now time.Time) {
+	if t.fn != nil {
+		t.fn()
+		return
+	}
+	select {
+	case t.c <- now:
+	default:
+	}
+}
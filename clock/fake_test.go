@@ -0,0 +1,153 @@
+package clock
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC)
+
+func TestFake(t *testing.T) {
+	t.Run("only moves when advanced", func(t *testing.T) {
+		clock := NewFake(start)
+		assertTime(t, clock.Now(), start)
+
+		clock.Advance(time.Minute)
+		assertTime(t, clock.Now(), start.Add(time.Minute))
+	})
+
+	t.Run("fires timers in the order they are due at the time they are due", func(t *testing.T) {
+		clock := NewFake(start)
+		var fired []string
+		var firedAt []time.Time
+		record := func(name string) func() {
+			return func() {
+				fired = append(fired, name)
+				firedAt = append(firedAt, clock.Now())
+			}
+		}
+
+		clock.AfterFunc(3*time.Second, record("third"))
+		clock.AfterFunc(time.Second, record("first"))
+		clock.AfterFunc(2*time.Second, record("second"))
+		clock.AfterFunc(time.Minute, record("later"))
+
+		clock.Advance(5 * time.Second)
+
+		if want := []string{"first", "second", "third"}; !reflect.DeepEqual(fired, want) {
+			t.Errorf("got %v want %v", fired, want)
+		}
+		want := []time.Time{start.Add(time.Second), start.Add(2 * time.Second), start.Add(3 * time.Second)}
+		if !reflect.DeepEqual(firedAt, want) {
+			t.Errorf("got fired at %v want %v", firedAt, want)
+		}
+		assertTime(t, clock.Now(), start.Add(5*time.Second))
+	})
+
+	t.Run("fires timers scheduled by timers that are due", func(t *testing.T) {
+		clock := NewFake(start)
+		ticks := 0
+		var tick func()
+		tick = func() {
+			ticks++
+			clock.AfterFunc(time.Second, tick)
+		}
+		clock.AfterFunc(time.Second, tick)
+
+		clock.Advance(10 * time.Second)
+
+		if ticks != 10 {
+			t.Errorf("got %d ticks want 10", ticks)
+		}
+	})
+
+	t.Run("fires timers already due on Advance(0)", func(t *testing.T) {
+		clock := NewFake(start)
+		fired := false
+		clock.AfterFunc(0, func() { fired = true })
+
+		if fired {
+			t.Fatal("fired before the clock was advanced")
+		}
+		clock.Advance(0)
+		if !fired {
+			t.Error("did not fire once the clock was advanced")
+		}
+	})
+
+	t.Run("stopped timers don't fire", func(t *testing.T) {
+		clock := NewFake(start)
+		timer := clock.AfterFunc(time.Second, func() { t.Error("stopped timer fired") })
+
+		if !timer.Stop() {
+			t.Error("expected Stop to report the timer was pending")
+		}
+		if timer.Stop() {
+			t.Error("expected Stop to report the timer was already stopped")
+		}
+		clock.Advance(time.Minute)
+	})
+
+	t.Run("reset timers are due again from now", func(t *testing.T) {
+		clock := NewFake(start)
+		timer := clock.NewTimer(time.Second)
+		clock.Advance(500 * time.Millisecond)
+		timer.Reset(time.Second)
+
+		clock.Advance(900 * time.Millisecond)
+		assertNotReceived(t, timer.C())
+		clock.Advance(100 * time.Millisecond)
+		assertReceived(t, timer.C(), start.Add(1500*time.Millisecond))
+	})
+
+	t.Run("After sends the time it went off", func(t *testing.T) {
+		clock := NewFake(start)
+		after := clock.After(time.Hour)
+
+		clock.Advance(59 * time.Minute)
+		assertNotReceived(t, after)
+		clock.Advance(2 * time.Minute)
+		assertReceived(t, after, start.Add(time.Hour))
+	})
+
+	t.Run("Sleep blocks until the clock is advanced", func(t *testing.T) {
+		clock := NewFake(start)
+		woke := make(chan time.Time)
+		go func() {
+			clock.Sleep(time.Second)
+			woke <- clock.Now()
+		}()
+
+		clock.BlockUntil(1)
+		assertNotReceived(t, woke)
+		clock.Advance(time.Second)
+		assertReceived(t, woke, start.Add(time.Second))
+	})
+}
+
+func assertTime(t testing.TB, got, want time.Time) {
+	t.Helper()
+	if !got.Equal(want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func assertReceived(t testing.TB, c <-chan time.Time, want time.Time) {
+	t.Helper()
+	select {
+	case got := <-c:
+		assertTime(t, got, want)
+	case <-time.After(time.Second):
+		t.Error("timed out waiting for the clock")
+	}
+}
+
+func assertNotReceived(t testing.TB, c <-chan time.Time) {
+	t.Helper()
+	select {
+	case got := <-c:
+		t.Errorf("got %v before the clock was advanced far enough", got)
+	default:
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"io"
 	"os"
 	"time"
+
+	"learn-go-with-tests/clock"
 )
 
 const finalWorld = "Go!"
@@ -24,10 +26,17 @@ func (s *SpySleeper) Sleep() {
 	s.Calls++
 }
 
-type DefaultSleeper struct{}
+// DefaultSleeper sleeps for a second on Clock, the real clock when it is nil.
+type DefaultSleeper struct {
+	Clock clock.Clock
+}
 
 func (d *DefaultSleeper) Sleep() {
-	time.Sleep(1 * time.Second)
+	c := d.Clock
+	if c == nil {
+		c = clock.Real{}
+	}
+	c.Sleep(1 * time.Second)
 }
 
 type CountdownOperationsSpy struct {
@@ -71,6 +80,6 @@ func Countdown(out io.Writer, sleeper Sleeper) {
 }
 
 func main() {
-	sleeper := &ConfigurableSleeper{1 * time.Second, time.Sleep}
+	sleeper := &ConfigurableSleeper{1 * time.Second, clock.Real{}.Sleep}
 	Countdown(os.Stdout, sleeper)
 }
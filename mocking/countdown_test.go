@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"testing"
 	"time"
+
+	"learn-go-with-tests/clock"
 )
 
 func TestCountdown(t *testing.T) {
@@ -54,3 +56,27 @@ func TestConfigurableSleeper(t *testing.T) {
 		t.Errorf("should have slept for %v but slept for %v", sleepTime, spyTime.durationSlept)
 	}
 }
+
+func TestDefaultSleeper(t *testing.T) {
+	start := time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	done := make(chan struct{})
+	go func() {
+		Countdown(&bytes.Buffer{}, &DefaultSleeper{Clock: fake})
+		close(done)
+	}()
+
+	for i := 0; i <= countdownStart; i++ {
+		fake.BlockUntil(1)
+		fake.Advance(1 * time.Second)
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("countdown didn't finish once the clock was advanced")
+	}
+	if slept := fake.Now().Sub(start); slept != 4*time.Second {
+		t.Errorf("should have slept for %v but slept for %v", 4*time.Second, slept)
+	}
+}
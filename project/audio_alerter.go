@@ -0,0 +1,178 @@
+package poker
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"learn-go-with-tests/clock"
+)
+
+// Tones of the audio alerts, in hertz.
+const (
+	chimeLow  = 660
+	chimeHigh = 880
+	beepTone  = 1000
+)
+
+// Lengths of the parts of the beep code an amount is played in.
+const (
+	shortBeep  = 120 * time.Millisecond
+	longBeep   = 480 * time.Millisecond
+	beepGap    = 120 * time.Millisecond
+	digitGap   = 480 * time.Millisecond
+	chimeBeep  = 150 * time.Millisecond
+	chimeAfter = 600 * time.Millisecond
+)
+
+// AudioAlerter is a BlindAlerter that also plays every alert and warning as
+// a WAV, for tables where nobody is watching the screen. The text of the
+// alert still goes to where the game sends it, first, and the WAV is played
+// in the background so an audio player that is slow to read doesn't hold up
+// the announcement.
+//
+// An alert is a rising chime and a warning a falling one, followed by the
+// blind amount in beep code: each digit is as many short beeps, and a zero
+// is one long beep.
+type AudioAlerter struct {
+	alerter *ClockAlerter
+	open    func() (io.WriteCloser, error)
+
+	mu      sync.Mutex
+	playing sync.WaitGroup
+}
+
+// NewAudioAlerter returns an AudioAlerter scheduling on c that writes each
+// alert to out as a complete WAV.
+func NewAudioAlerter(c clock.Clock, out io.Writer) *AudioAlerter {
+	return &AudioAlerter{
+		alerter: NewAlerter(c),
+		open: func() (io.WriteCloser, error) {
+			return nopWriteCloser{out}, nil
+		},
+	}
+}
+
+// NewAudioFileAlerter returns an AudioAlerter scheduling on c that writes
+// each alert to the file at path, replacing the last one. path can be a
+// named pipe read by an audio player.
+func NewAudioFileAlerter(c clock.Clock, path string) *AudioAlerter {
+	return &AudioAlerter{
+		alerter: NewAlerter(c),
+		open: func() (io.WriteCloser, error) {
+			return os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+		},
+	}
+}
+
+func (a *AudioAlerter) ScheduleAlertAt(ctx context.Context, duration time.Duration, amount int, to io.Writer) {
+	a.alerter.ScheduleAlertAt(ctx, duration, amount, audioDestination{to, a})
+}
+
+func (a *AudioAlerter) ScheduleWarningAt(ctx context.Context, duration time.Duration, amount int, in time.Duration, to io.Writer) {
+	a.alerter.ScheduleWarningAt(ctx, duration, amount, in, audioDestination{to, a})
+}
+
+// playInBackground plays tones without waiting for them to be played,
+// logging any problem playing them.
+func (a *AudioAlerter) playInBackground(tones []tone) {
+	a.playing.Add(1)
+	go func() {
+		defer a.playing.Done()
+		if err := a.play(tones); err != nil {
+			log.Printf("problem playing audio alert, %v\n", err)
+		}
+	}()
+}
+
+// wait blocks until the alerts being played have been.
+func (a *AudioAlerter) wait() {
+	a.playing.Wait()
+}
+
+// play writes tones as one WAV, one alert at a time.
+func (a *AudioAlerter) play(tones []tone) error {
+	var wav bytes.Buffer
+	if err := writeWAV(&wav, audioSampleRate, renderTones(tones)); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	out, err := a.open()
+	if err != nil {
+		return err
+	}
+	if _, err := out.Write(wav.Bytes()); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// audioDestination passes the alerts sent to it on to, then plays them.
+type audioDestination struct {
+	to    io.Writer
+	audio *AudioAlerter
+}
+
+func (d audioDestination) Write(p []byte) (int, error) {
+	return d.to.Write(p)
+}
+
+func (d audioDestination) AnnounceBlind(amount int) error {
+	err := announceBlind(d.to, amount)
+	d.audio.playInBackground(blindAlertTones(amount))
+	return err
+}
+
+func (d audioDestination) AnnounceWarning(amount int, in time.Duration) error {
+	err := announceWarning(d.to, amount, in)
+	d.audio.playInBackground(warningTones(amount))
+	return err
+}
+
+func blindAlertTones(amount int) []tone {
+	chime := []tone{{chimeLow, chimeBeep}, {chimeHigh, chimeBeep}, {0, chimeAfter}}
+	return append(chime, beepCode(amount)...)
+}
+
+func warningTones(amount int) []tone {
+	chime := []tone{{chimeHigh, chimeBeep}, {chimeLow, chimeBeep}, {0, chimeAfter}}
+	return append(chime, beepCode(amount)...)
+}
+
+// beepCode plays each digit of amount as that many short beeps, or one long beep for a zero.
+func beepCode(amount int) []tone {
+	var tones []tone
+	for i, digit := range strconv.Itoa(amount) {
+		if i > 0 {
+			tones = append(tones, tone{0, digitGap})
+		}
+		if digit == '0' {
+			tones = append(tones, tone{beepTone, longBeep})
+			continue
+		}
+		for n := 0; n < int(digit-'0'); n++ {
+			if n > 0 {
+				tones = append(tones, tone{0, beepGap})
+			}
+			tones = append(tones, tone{beepTone, shortBeep})
+		}
+	}
+	return tones
+}
+
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error {
+	return nil
+}
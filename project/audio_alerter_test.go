@@ -0,0 +1,254 @@
+package poker
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"learn-go-with-tests/clock"
+)
+
+func TestAudioAlerter(t *testing.T) {
+	start := time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC)
+
+	t.Run("it plays the blind alert and still writes its text", func(t *testing.T) {
+		fake := clock.NewFake(start)
+		audio, text := &bytes.Buffer{}, &bytes.Buffer{}
+
+		alerter := NewAudioAlerter(fake, audio)
+		alerter.ScheduleAlertAt(context.Background(), time.Minute, 300, text)
+		fake.Advance(time.Minute)
+		alerter.wait()
+
+		assertAudioAlert(t, readWAV(t, audio.Bytes()), "rising", "300")
+		if text.String() != "Blind is now 300\n" {
+			t.Errorf("got %q want %q", text.String(), "Blind is now 300\n")
+		}
+	})
+
+	t.Run("it plays warnings with a falling chime", func(t *testing.T) {
+		fake := clock.NewFake(start)
+		audio, text := &bytes.Buffer{}, &bytes.Buffer{}
+
+		alerter := NewAudioAlerter(fake, audio)
+		alerter.ScheduleWarningAt(context.Background(), time.Minute, 4000, 10*time.Second, text)
+		fake.Advance(time.Minute)
+		alerter.wait()
+
+		assertAudioAlert(t, readWAV(t, audio.Bytes()), "falling", "4000")
+		if want := "Blinds go up to 4000 in 10 seconds\n"; text.String() != want {
+			t.Errorf("got %q want %q", text.String(), want)
+		}
+	})
+
+	t.Run("it announces the alert while the audio is stalled", func(t *testing.T) {
+		fake := clock.NewFake(start)
+		audio, text := &bytes.Buffer{}, &bytes.Buffer{}
+		unblock := make(chan struct{})
+		alerter := NewAudioAlerter(fake, audio)
+		// opening a named pipe blocks until something reads it.
+		alerter.open = func() (io.WriteCloser, error) {
+			<-unblock
+			return nopWriteCloser{audio}, nil
+		}
+
+		alerter.ScheduleAlertAt(context.Background(), time.Minute, 300, text)
+		fake.Advance(time.Minute)
+
+		if text.String() != "Blind is now 300\n" {
+			t.Errorf("got %q want %q", text.String(), "Blind is now 300\n")
+		}
+		close(unblock)
+		alerter.wait()
+		assertAudioAlert(t, readWAV(t, audio.Bytes()), "rising", "300")
+	})
+
+	t.Run("it passes the amounts on to a BlindAnnouncer", func(t *testing.T) {
+		fake := clock.NewFake(start)
+		out := &spyBlindAnnouncer{amounts: make(chan int, 1)}
+
+		NewAudioAlerter(fake, &bytes.Buffer{}).ScheduleAlertAt(context.Background(), time.Minute, 1250, out)
+		fake.Advance(time.Minute)
+
+		if got := <-out.amounts; got != 1250 {
+			t.Errorf("got %d want %d", got, 1250)
+		}
+	})
+
+	t.Run("it plays nothing once the context is cancelled", func(t *testing.T) {
+		fake := clock.NewFake(start)
+		audio := &bytes.Buffer{}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		alerter := NewAudioAlerter(fake, audio)
+		alerter.ScheduleAlertAt(ctx, time.Minute, 300, &bytes.Buffer{})
+		cancel()
+		fake.Advance(time.Hour)
+		alerter.wait()
+
+		if audio.Len() != 0 {
+			t.Errorf("got %d bytes of audio but expected none", audio.Len())
+		}
+	})
+
+	t.Run("it replaces the file with each alert", func(t *testing.T) {
+		fake := clock.NewFake(start)
+		path := filepath.Join(t.TempDir(), "alert.wav")
+		alerter := NewAudioFileAlerter(fake, path)
+
+		alerter.ScheduleAlertAt(context.Background(), time.Minute, 100, &bytes.Buffer{})
+		alerter.ScheduleAlertAt(context.Background(), 2*time.Minute, 2000, &bytes.Buffer{})
+		fake.Advance(time.Minute)
+		alerter.wait()
+		fake.Advance(time.Minute)
+		alerter.wait()
+
+		data, err := os.ReadFile(path)
+		assertNoError(t, err)
+		assertAudioAlert(t, readWAV(t, data), "rising", "2000")
+	})
+}
+
+func TestWriteWAV(t *testing.T) {
+	samples := []int16{0, 1000, -1000, 32767, -32768}
+	wav := &bytes.Buffer{}
+	assertNoError(t, writeWAV(wav, 8000, samples))
+
+	got := readWAV(t, wav.Bytes())
+	if len(got) != len(samples) {
+		t.Fatalf("got %d samples want %d", len(got), len(samples))
+	}
+	for i := range samples {
+		if got[i] != samples[i] {
+			t.Errorf("sample %d got %d want %d", i, got[i], samples[i])
+		}
+	}
+}
+
+// readWAV checks data is a mono 16 bit WAV at audioSampleRate and returns its samples.
+func readWAV(t testing.TB, data []byte) []int16 {
+	t.Helper()
+	var header struct {
+		RIFF          [4]byte
+		Size          uint32
+		WAVE          [4]byte
+		Fmt           [4]byte
+		FmtSize       uint32
+		Format        uint16
+		Channels      uint16
+		SampleRate    uint32
+		ByteRate      uint32
+		BlockAlign    uint16
+		BitsPerSample uint16
+		Data          [4]byte
+		DataSize      uint32
+	}
+	r := bytes.NewReader(data)
+	if err := binary.Read(r, binary.LittleEndian, &header); err != nil {
+		t.Fatalf("could not read WAV header from %d bytes, %v", len(data), err)
+	}
+
+	switch {
+	case string(header.RIFF[:]) != "RIFF" || string(header.WAVE[:]) != "WAVE" || string(header.Data[:]) != "data":
+		t.Fatalf("not a WAV file %+v", header)
+	case header.Format != 1 || header.Channels != 1 || header.BitsPerSample != 16:
+		t.Fatalf("got format %+v want mono 16 bit PCM", header)
+	case header.SampleRate != audioSampleRate || header.ByteRate != 2*audioSampleRate:
+		t.Fatalf("got sample rate %+v want %d", header, audioSampleRate)
+	case int(header.DataSize) != r.Len() || int(header.Size) != len(data)-8:
+		t.Fatalf("got sizes %+v for %d bytes", header, len(data))
+	}
+
+	samples := make([]int16, header.DataSize/2)
+	if err := binary.Read(r, binary.LittleEndian, samples); err != nil {
+		t.Fatalf("could not read WAV samples, %v", err)
+	}
+	return samples
+}
+
+// assertAudioAlert decodes the chime and beep code of an audio alert.
+func assertAudioAlert(t testing.TB, samples []int16, chime, amount string) {
+	t.Helper()
+	sounds, silences := audioRuns(samples)
+	if len(sounds) == 0 || sounds[0] != 2*chimeBeep {
+		t.Fatalf("expected the alert to start with a chime, got sounds %v", sounds)
+	}
+
+	beep := int(chimeBeep * audioSampleRate / time.Second)
+	gotChime := "rising"
+	if zeroCrossings(samples[:beep]) > zeroCrossings(samples[beep:2*beep]) {
+		gotChime = "falling"
+	}
+	if gotChime != chime {
+		t.Errorf("got a %s chime want %s", gotChime, chime)
+	}
+
+	var digits string
+	count := 0
+	for i, sound := range sounds[1:] {
+		if i > 0 && silences[i+1] >= digitGap {
+			digits += strconv.Itoa(count)
+			count = 0
+		}
+		switch sound {
+		case shortBeep:
+			count++
+		case longBeep:
+			count = 0
+		default:
+			t.Fatalf("got a beep of %v", sound)
+		}
+	}
+	digits += strconv.Itoa(count)
+
+	if digits != amount {
+		t.Errorf("got beep code for %s want %s", digits, amount)
+	}
+}
+
+// audioRuns splits samples into 10ms windows and returns how long each run
+// of sound lasts, and how long the silence before it lasts.
+func audioRuns(samples []int16) (sounds, silences []time.Duration) {
+	const window = audioSampleRate / 100
+	loud := func(i int) bool {
+		for _, s := range samples[i:min(i+window, len(samples))] {
+			if s > 1000 || s < -1000 {
+				return true
+			}
+		}
+		return false
+	}
+
+	var sound, silence time.Duration
+	for i := 0; i < len(samples); i += window {
+		switch {
+		case loud(i):
+			sound += 10 * time.Millisecond
+		case sound > 0:
+			sounds, silences = append(sounds, sound), append(silences, silence)
+			sound, silence = 0, 10*time.Millisecond
+		default:
+			silence += 10 * time.Millisecond
+		}
+	}
+	if sound > 0 {
+		sounds, silences = append(sounds, sound), append(silences, silence)
+	}
+	return sounds, silences
+}
+
+func zeroCrossings(samples []int16) int {
+	n := 0
+	for i := 1; i < len(samples); i++ {
+		if (samples[i-1] < 0) != (samples[i] < 0) {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,132 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Types of chip transaction.
+const (
+	ChipBuyIn  = "buy_in"
+	ChipRebuy  = "rebuy"
+	ChipAddOn  = "add_on"
+	ChipPayout = "payout"
+)
+
+// ChipTransaction is chips a player put into, or took out of, a game.
+type ChipTransaction struct {
+	Player string    `json:"player"`
+	Game   string    `json:"game,omitempty"`
+	Type   string    `json:"type"`
+	Amount int       `json:"amount"`
+	At     time.Time `json:"at"`
+}
+
+// ErrInvalidChipTransaction is wrapped by the errors of transactions that can't be recorded.
+var ErrInvalidChipTransaction = errors.New("invalid chip transaction")
+
+// Validate reports whether the transaction can be recorded.
+func (c ChipTransaction) Validate() error {
+	if c.Player == "" {
+		return fmt.Errorf("%w, it needs a player", ErrInvalidChipTransaction)
+	}
+	switch c.Type {
+	case ChipBuyIn, ChipRebuy, ChipAddOn, ChipPayout:
+	default:
+		return fmt.Errorf("%w, unknown type %q", ErrInvalidChipTransaction, c.Type)
+	}
+	if c.Amount <= 0 {
+		return fmt.Errorf("%w, the amount must be positive, got %d", ErrInvalidChipTransaction, c.Amount)
+	}
+	return nil
+}
+
+// Net is the change the transaction makes to the player's bankroll,
+// payouts add chips and everything else costs them.
+func (c ChipTransaction) Net() int {
+	if c.Type == ChipPayout {
+		return c.Amount
+	}
+	return -c.Amount
+}
+
+// ChipLedger stores the chips players put into and take out of games.
+type ChipLedger interface {
+	// RecordChips returns an error wrapping ErrInvalidChipTransaction for
+	// transactions that don't Validate.
+	RecordChips(transaction ChipTransaction) error
+	GetChipTransactions(name string) ([]ChipTransaction, error)
+}
+
+// ChipStack totals a player's chip transactions.
+type ChipStack struct {
+	BuyIns  int `json:"buy_ins"`
+	Rebuys  int `json:"rebuys"`
+	AddOns  int `json:"add_ons"`
+	Payouts int `json:"payouts"`
+	Net     int `json:"net"`
+}
+
+func (c *ChipStack) add(transaction ChipTransaction) {
+	switch transaction.Type {
+	case ChipBuyIn:
+		c.BuyIns += transaction.Amount
+	case ChipRebuy:
+		c.Rebuys += transaction.Amount
+	case ChipAddOn:
+		c.AddOns += transaction.Amount
+	case ChipPayout:
+		c.Payouts += transaction.Amount
+	}
+	c.Net += transaction.Net()
+}
+
+// GameStack is a player's chip stack in a single game.
+type GameStack struct {
+	Game string `json:"game"`
+	ChipStack
+}
+
+// Bankroll is a player's chip stack over every game, and in each game they played.
+type Bankroll struct {
+	Player string `json:"player"`
+	ChipStack
+	Games []GameStack `json:"games"`
+}
+
+// NewBankroll totals the transactions of name, in the order they were recorded.
+func NewBankroll(name string, transactions []ChipTransaction) Bankroll {
+	bankroll := Bankroll{Player: name, Games: []GameStack{}}
+	games := map[string]int{}
+
+	for _, transaction := range transactions {
+		if transaction.Player != name {
+			continue
+		}
+		bankroll.add(transaction)
+
+		if transaction.Game == "" {
+			continue
+		}
+		i, ok := games[transaction.Game]
+		if !ok {
+			i = len(bankroll.Games)
+			games[transaction.Game] = i
+			bankroll.Games = append(bankroll.Games, GameStack{Game: transaction.Game})
+		}
+		bankroll.Games[i].add(transaction)
+	}
+	return bankroll
+}
+
+// playerChipTransactions returns the transactions of name.
+func playerChipTransactions(transactions []ChipTransaction, name string) []ChipTransaction {
+	found := []ChipTransaction{}
+	for _, c := range transactions {
+		if c.Player == name {
+			found = append(found, c)
+		}
+	}
+	return found
+}
@@ -0,0 +1,66 @@
+package poker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChipTransaction(t *testing.T) {
+	t.Run("validates transactions", func(t *testing.T) {
+		cases := []struct {
+			transaction ChipTransaction
+			valid       bool
+		}{
+			{ChipTransaction{Player: "Chris", Type: ChipBuyIn, Amount: 100}, true},
+			{ChipTransaction{Player: "Chris", Type: ChipPayout, Amount: 1}, true},
+			{ChipTransaction{Type: ChipBuyIn, Amount: 100}, false},
+			{ChipTransaction{Player: "Chris", Type: "bribe", Amount: 100}, false},
+			{ChipTransaction{Player: "Chris", Type: ChipRebuy, Amount: 0}, false},
+			{ChipTransaction{Player: "Chris", Type: ChipAddOn, Amount: -10}, false},
+		}
+
+		for _, c := range cases {
+			err := c.transaction.Validate()
+			if c.valid && err != nil {
+				t.Errorf("didn't expect an error for %+v but got %v", c.transaction, err)
+			}
+			if !c.valid && err == nil {
+				t.Errorf("expected an error for %+v", c.transaction)
+			}
+		}
+	})
+
+	t.Run("payouts add chips and everything else costs them", func(t *testing.T) {
+		for typ, want := range map[string]int{ChipBuyIn: -50, ChipRebuy: -50, ChipAddOn: -50, ChipPayout: 50} {
+			if got := (ChipTransaction{Type: typ, Amount: 50}).Net(); got != want {
+				t.Errorf("got net %d for a %s want %d", got, typ, want)
+			}
+		}
+	})
+}
+
+func TestNewBankroll(t *testing.T) {
+	transactions := []ChipTransaction{
+		{Player: "Chris", Game: "1", Type: ChipBuyIn, Amount: 100},
+		{Player: "Cleo", Game: "1", Type: ChipBuyIn, Amount: 100},
+		{Player: "Chris", Game: "1", Type: ChipRebuy, Amount: 100},
+		{Player: "Chris", Game: "1", Type: ChipAddOn, Amount: 50},
+		{Player: "Chris", Game: "2", Type: ChipBuyIn, Amount: 200},
+		{Player: "Chris", Game: "2", Type: ChipPayout, Amount: 600},
+		{Player: "Chris", Type: ChipPayout, Amount: 20},
+	}
+
+	got := NewBankroll("Chris", transactions)
+	want := Bankroll{
+		Player:    "Chris",
+		ChipStack: ChipStack{BuyIns: 300, Rebuys: 100, AddOns: 50, Payouts: 620, Net: 170},
+		Games: []GameStack{
+			{Game: "1", ChipStack: ChipStack{BuyIns: 100, Rebuys: 100, AddOns: 50, Net: -250}},
+			{Game: "2", ChipStack: ChipStack{BuyIns: 200, Payouts: 600, Net: 400}},
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got bankroll %+v want %+v", got, want)
+	}
+}
@@ -0,0 +1,397 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Types of action a player can take when it is their turn to bet.
+const (
+	ActionCheck = "check"
+	ActionBet   = "bet"
+	ActionCall  = "call"
+	ActionRaise = "raise"
+	ActionFold  = "fold"
+	ActionAllIn = "all_in"
+)
+
+// Reasons an action is illegal, wrapped in an IllegalActionError.
+var (
+	ErrBettingOver     = errors.New("betting is over for this hand")
+	ErrStreetNotDealt  = errors.New("the street being bet on hasn't been dealt")
+	ErrNotYourTurn     = errors.New("it is not their turn")
+	ErrUnknownAction   = errors.New("unknown action")
+	ErrCannotCheck     = errors.New("there is a bet to call")
+	ErrNothingToCall   = errors.New("there is no bet to call")
+	ErrBetAlreadyMade  = errors.New("there is already a bet, raise instead")
+	ErrNoBetToRaise    = errors.New("there is no bet to raise, bet instead")
+	ErrBetTooSmall     = errors.New("bet is too small")
+	ErrRaiseTooSmall   = errors.New("raise is too small")
+	ErrRaiseNotAllowed = errors.New("betting was not reopened by a full raise")
+	ErrNotEnoughChips  = errors.New("not enough chips")
+)
+
+// IllegalActionError is returned for an action that breaks the rules of betting,
+// Err is one of the reasons above.
+type IllegalActionError struct {
+	Action Action
+	Err    error
+}
+
+func (e *IllegalActionError) Error() string {
+	return fmt.Sprintf("%s can't %s, %v", e.Action.Player, e.Action.Type, e.Err)
+}
+
+func (e *IllegalActionError) Unwrap() error {
+	return e.Err
+}
+
+// Action is a player's move when it is their turn. Amount is only used by
+// bets and raises, and is what the player's bet on the street is made up to.
+type Action struct {
+	Player string `json:"player"`
+	Type   string `json:"type"`
+	Amount int    `json:"amount,omitempty"`
+}
+
+// Seat is a player in a hand with the chips they have left and have bet.
+// Bet is what they have put in on the current street and Committed what
+// they have put in over the whole hand, antes included.
+type Seat struct {
+	Player    string `json:"player"`
+	Stack     int    `json:"stack"`
+	Bet       int    `json:"bet"`
+	Committed int    `json:"committed"`
+	Folded    bool   `json:"folded"`
+	AllIn     bool   `json:"all_in"`
+
+	// acted is whether the player has acted since the last full raise.
+	acted bool
+}
+
+func (s *Seat) canAct() bool {
+	return !s.Folded && !s.AllIn
+}
+
+func (s *Seat) put(amount int) {
+	s.Stack -= amount
+	s.Bet += amount
+	s.Committed += amount
+	s.AllIn = s.Stack == 0
+}
+
+// Betting runs the betting of one hand, from posting the blinds to the end
+// of the river or until all but one player has folded.
+type Betting struct {
+	seats      []Seat
+	button     int
+	street     string
+	toAct      int
+	currentBet int
+	minRaise   int
+	bigBlind   int
+	settled    bool
+}
+
+// NewBetting seats players in order, with the dealer button at seat button,
+// and posts the antes and blinds of level.
+func NewBetting(seats []Seat, level BlindLevel, button int) (*Betting, error) {
+	if len(seats) < 2 {
+		return nil, fmt.Errorf("betting needs at least 2 players, got %d", len(seats))
+	}
+	if button < 0 || button >= len(seats) {
+		return nil, fmt.Errorf("button must be at one of the %d seats, got %d", len(seats), button)
+	}
+
+	b := &Betting{
+		button:     button,
+		street:     PreFlop,
+		currentBet: level.BigBlind,
+		minRaise:   level.BigBlind,
+		bigBlind:   level.BigBlind,
+	}
+	seen := map[string]bool{}
+	for _, seat := range seats {
+		if seen[seat.Player] {
+			return nil, fmt.Errorf("player %q is seated twice", seat.Player)
+		}
+		if seat.Stack <= 0 {
+			return nil, fmt.Errorf("player %q has no chips", seat.Player)
+		}
+		seen[seat.Player] = true
+		b.seats = append(b.seats, Seat{Player: seat.Player, Stack: seat.Stack})
+	}
+
+	for i := range b.seats {
+		seat := &b.seats[i]
+		seat.put(min(level.Ante, seat.Stack))
+		seat.Bet = 0
+	}
+
+	// heads up the button posts the small blind.
+	smallBlind, bigBlind := b.next(button), b.next(b.next(button))
+	if len(b.seats) == 2 {
+		smallBlind, bigBlind = button, b.next(button)
+	}
+	b.post(smallBlind, level.SmallBlind)
+	b.post(bigBlind, level.BigBlind)
+
+	b.toAct = bigBlind
+	b.advance()
+	return b, nil
+}
+
+func (b *Betting) post(i, blind int) {
+	seat := &b.seats[i]
+	if !seat.AllIn {
+		seat.put(min(blind, seat.Stack))
+	}
+}
+
+func (b *Betting) next(i int) int {
+	return (i + 1) % len(b.seats)
+}
+
+// Street is the street being bet on, Showdown once betting is over.
+func (b *Betting) Street() string {
+	return b.street
+}
+
+// Over reports whether betting is over for the hand.
+func (b *Betting) Over() bool {
+	return b.street == Showdown
+}
+
+// ToAct is the player whose turn it is, empty once betting is over.
+func (b *Betting) ToAct() string {
+	if b.Over() {
+		return ""
+	}
+	return b.seats[b.toAct].Player
+}
+
+// CurrentBet is the bet to call on this street.
+func (b *Betting) CurrentBet() int {
+	return b.currentBet
+}
+
+// MinRaiseTo is the least a bet or raise can be made up to, unless the player is all in.
+func (b *Betting) MinRaiseTo() int {
+	return b.currentBet + b.minRaise
+}
+
+// Seats returns the players in the hand, in seat order.
+func (b *Betting) Seats() []Seat {
+	return append([]Seat{}, b.seats...)
+}
+
+// Act applies the action of the player whose turn it is, an illegal action
+// returns an *IllegalActionError and changes nothing.
+func (b *Betting) Act(action Action) error {
+	if b.Over() {
+		return &IllegalActionError{action, ErrBettingOver}
+	}
+	seat := &b.seats[b.toAct]
+	if seat.Player != action.Player {
+		return &IllegalActionError{action, ErrNotYourTurn}
+	}
+
+	var err error
+	switch action.Type {
+	case ActionFold:
+		seat.Folded = true
+	case ActionCheck:
+		if seat.Bet < b.currentBet {
+			err = ErrCannotCheck
+		}
+	case ActionCall:
+		if seat.Bet >= b.currentBet {
+			err = ErrNothingToCall
+			break
+		}
+		seat.put(min(b.currentBet-seat.Bet, seat.Stack))
+	case ActionBet:
+		if b.currentBet > 0 {
+			err = ErrBetAlreadyMade
+			break
+		}
+		err = b.raiseTo(seat, action.Amount, ErrBetTooSmall)
+	case ActionRaise:
+		if b.currentBet == 0 {
+			err = ErrNoBetToRaise
+			break
+		}
+		err = b.raiseTo(seat, action.Amount, ErrRaiseTooSmall)
+	case ActionAllIn:
+		allIn := seat.Bet + seat.Stack
+		if allIn <= b.currentBet {
+			seat.put(seat.Stack)
+			break
+		}
+		err = b.raiseTo(seat, allIn, ErrRaiseTooSmall)
+	default:
+		err = ErrUnknownAction
+	}
+	if err != nil {
+		return &IllegalActionError{action, err}
+	}
+
+	seat.acted = true
+	b.advance()
+	return nil
+}
+
+// raiseTo makes the seat's bet up to to. A raise by less than the last full
+// raise is only allowed all in, and doesn't reopen the betting for the
+// players who have already acted.
+func (b *Betting) raiseTo(seat *Seat, to int, tooSmall error) error {
+	allIn := seat.Bet + seat.Stack
+	switch {
+	case seat.acted:
+		return ErrRaiseNotAllowed
+	case to > allIn:
+		return fmt.Errorf("%w, they have %d", ErrNotEnoughChips, allIn)
+	case to < b.MinRaiseTo() && to != allIn, to <= b.currentBet:
+		return fmt.Errorf("%w, the least is %d", tooSmall, b.MinRaiseTo())
+	}
+
+	seat.put(to - seat.Bet)
+	if raise := to - b.currentBet; raise >= b.minRaise {
+		b.minRaise = raise
+		for i := range b.seats {
+			b.seats[i].acted = false
+		}
+	}
+	b.currentBet = to
+	return nil
+}
+
+// advance moves the turn on to the next player who has to act, dealing
+// with the end of the street when there is nobody left to act.
+func (b *Betting) advance() {
+	inHand, canAct := 0, 0
+	for i := range b.seats {
+		if !b.seats[i].Folded {
+			inHand++
+		}
+		if b.seats[i].canAct() {
+			canAct++
+		}
+	}
+	if inHand == 1 {
+		b.returnUncalledBet()
+		b.street = Showdown
+		return
+	}
+
+	for i, n := b.next(b.toAct), 0; n < len(b.seats); i, n = b.next(i), n+1 {
+		seat := &b.seats[i]
+		needsAction := !seat.acted || seat.Bet < b.currentBet
+		if canAct == 1 && seat.Bet >= b.currentBet {
+			// nobody is left to bet against.
+			needsAction = false
+		}
+		if seat.canAct() && needsAction {
+			b.toAct = i
+			return
+		}
+	}
+
+	b.returnUncalledBet()
+	if b.street == River || canAct < 2 {
+		// nobody can bet any more, the rest of the board is run out.
+		b.street = Showdown
+		return
+	}
+
+	b.street = map[string]string{PreFlop: Flop, Flop: Turn, Turn: River}[b.street]
+	b.currentBet = 0
+	b.minRaise = b.bigBlind
+	for i := range b.seats {
+		b.seats[i].Bet = 0
+		b.seats[i].acted = false
+	}
+	b.toAct = b.button
+	b.advance()
+}
+
+// returnUncalledBet gives back the part of the biggest bet nobody matched.
+func (b *Betting) returnUncalledBet() {
+	top, second := -1, 0
+	for i := range b.seats {
+		bet := b.seats[i].Bet
+		switch {
+		case top < 0 || bet > b.seats[top].Bet:
+			if top >= 0 {
+				second = b.seats[top].Bet
+			}
+			top = i
+		case bet > second:
+			second = bet
+		}
+	}
+
+	seat := &b.seats[top]
+	if uncalled := seat.Bet - second; uncalled > 0 {
+		seat.Stack += uncalled
+		seat.Bet -= uncalled
+		seat.Committed -= uncalled
+		seat.AllIn = false
+		if b.currentBet > second {
+			b.currentBet = second
+		}
+	}
+}
+
+// BettingState is how the betting of a hand stands. ToCall is what the
+// player to act has to put in to call and MinRaiseTo the least they can bet
+// or raise to, unless they are all in.
+type BettingState struct {
+	Street     string `json:"street"`
+	ToAct      string `json:"to_act,omitempty"`
+	ToCall     int    `json:"to_call,omitempty"`
+	MinRaiseTo int    `json:"min_raise_to,omitempty"`
+	Seats      []Seat `json:"seats"`
+	Pots       []Pot  `json:"pots"`
+}
+
+// State is how the betting stands now.
+func (b *Betting) State() BettingState {
+	state := BettingState{Street: b.street, Seats: b.Seats(), Pots: b.Pots()}
+	if !b.Over() {
+		seat := b.seats[b.toAct]
+		state.ToAct = seat.Player
+		state.ToCall = min(b.currentBet-seat.Bet, seat.Stack)
+		state.MinRaiseTo = b.MinRaiseTo()
+	}
+	return state
+}
+
+// BettingAnnouncer is implemented by alert destinations that want how the
+// betting stands rather than the text of it.
+type BettingAnnouncer interface {
+	AnnounceBetting(state BettingState) error
+}
+
+// announceBetting announces the pot and whose turn it is.
+func announceBetting(to io.Writer, state BettingState) error {
+	if announcer, ok := to.(BettingAnnouncer); ok {
+		return announcer.AnnounceBetting(state)
+	}
+
+	pot := 0
+	for _, p := range state.Pots {
+		pot += p.Amount
+	}
+	var err error
+	switch {
+	case state.ToAct == "":
+		_, err = fmt.Fprintf(to, "Pot %d, betting is over for the hand\n", pot)
+	case state.ToCall > 0:
+		_, err = fmt.Fprintf(to, "Pot %d, %s to act on the %s, %d to call\n", pot, state.ToAct, state.Street, state.ToCall)
+	default:
+		_, err = fmt.Fprintf(to, "Pot %d, %s to act on the %s\n", pot, state.ToAct, state.Street)
+	}
+	return err
+}
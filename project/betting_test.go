@@ -0,0 +1,381 @@
+package poker
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+var testBlinds = BlindLevel{SmallBlind: 50, BigBlind: 100}
+
+func TestNewBetting(t *testing.T) {
+	t.Run("posts the blinds left of the button and the next player acts first", func(t *testing.T) {
+		betting := mustNewBetting(t, testBlinds, 0, "Chris", 1000, "Cleo", 1000, "Pepper", 1000)
+
+		assertSeats(t, betting, []Seat{
+			{Player: "Chris", Stack: 1000},
+			{Player: "Cleo", Stack: 950, Bet: 50, Committed: 50},
+			{Player: "Pepper", Stack: 900, Bet: 100, Committed: 100},
+		})
+		assertToAct(t, betting, "Chris")
+		assertStreet(t, betting, PreFlop)
+		assertInts(t, betting.MinRaiseTo(), 200)
+	})
+
+	t.Run("posts antes from everyone as dead money", func(t *testing.T) {
+		betting := mustNewBetting(t, BlindLevel{SmallBlind: 50, BigBlind: 100, Ante: 10}, 2, "Chris", 1000, "Cleo", 1000, "Pepper", 1000)
+
+		assertSeats(t, betting, []Seat{
+			{Player: "Chris", Stack: 940, Bet: 50, Committed: 60},
+			{Player: "Cleo", Stack: 890, Bet: 100, Committed: 110},
+			{Player: "Pepper", Stack: 990, Committed: 10},
+		})
+		assertToAct(t, betting, "Pepper")
+		assertInts(t, betting.CurrentBet(), 100)
+	})
+
+	t.Run("heads up the button posts the small blind and acts first before the flop only", func(t *testing.T) {
+		betting := mustNewBetting(t, testBlinds, 1, "Chris", 1000, "Cleo", 1000)
+
+		assertSeats(t, betting, []Seat{
+			{Player: "Chris", Stack: 900, Bet: 100, Committed: 100},
+			{Player: "Cleo", Stack: 950, Bet: 50, Committed: 50},
+		})
+		assertToAct(t, betting, "Cleo")
+
+		mustAct(t, betting, Action{"Cleo", ActionCall, 0}, Action{"Chris", ActionCheck, 0})
+		assertStreet(t, betting, Flop)
+		assertToAct(t, betting, "Chris")
+	})
+
+	t.Run("a short stack posts what it has and is all in", func(t *testing.T) {
+		betting := mustNewBetting(t, testBlinds, 0, "Chris", 1000, "Cleo", 1000, "Pepper", 60)
+
+		assertSeats(t, betting, []Seat{
+			{Player: "Chris", Stack: 1000},
+			{Player: "Cleo", Stack: 950, Bet: 50, Committed: 50},
+			{Player: "Pepper", Stack: 0, Bet: 60, Committed: 60, AllIn: true},
+		})
+		assertInts(t, betting.CurrentBet(), 100)
+	})
+
+	t.Run("rejects tables it can't deal to", func(t *testing.T) {
+		cases := map[string][]Seat{
+			"one player":      {{Player: "Chris", Stack: 100}},
+			"seated twice":    {{Player: "Chris", Stack: 100}, {Player: "Chris", Stack: 100}},
+			"player no chips": {{Player: "Chris", Stack: 100}, {Player: "Cleo"}},
+		}
+		for name, seats := range cases {
+			if _, err := NewBetting(seats, testBlinds, 0); err == nil {
+				t.Errorf("expected an error for %s", name)
+			}
+		}
+		if _, err := NewBetting([]Seat{{Player: "Chris", Stack: 100}, {Player: "Cleo", Stack: 100}}, testBlinds, 2); err == nil {
+			t.Error("expected an error for a button off the table")
+		}
+	})
+}
+
+func TestBettingRounds(t *testing.T) {
+	t.Run("the big blind gets the option and every street is bet in turn", func(t *testing.T) {
+		betting := mustNewBetting(t, testBlinds, 0, "Chris", 1000, "Cleo", 1000, "Pepper", 1000)
+
+		mustAct(t, betting, Action{"Chris", ActionCall, 0}, Action{"Cleo", ActionCall, 0})
+		assertToAct(t, betting, "Pepper")
+		mustAct(t, betting, Action{"Pepper", ActionCheck, 0})
+
+		for _, street := range []string{Flop, Turn, River} {
+			assertStreet(t, betting, street)
+			assertToAct(t, betting, "Cleo")
+			mustAct(t, betting, Action{"Cleo", ActionCheck, 0}, Action{"Pepper", ActionCheck, 0}, Action{"Chris", ActionCheck, 0})
+		}
+
+		assertStreet(t, betting, Showdown)
+		assertToAct(t, betting, "")
+		assertPots(t, betting, []Pot{{300, []string{"Chris", "Cleo", "Pepper"}}})
+	})
+
+	t.Run("a raise sets the next minimum raise and reopens the betting", func(t *testing.T) {
+		betting := mustNewBetting(t, testBlinds, 0, "Chris", 1000, "Cleo", 1000, "Pepper", 1000)
+
+		mustAct(t, betting, Action{"Chris", ActionRaise, 350})
+		assertInts(t, betting.MinRaiseTo(), 600)
+		mustAct(t, betting, Action{"Cleo", ActionFold, 0}, Action{"Pepper", ActionRaise, 600})
+		assertToAct(t, betting, "Chris")
+		assertInts(t, betting.MinRaiseTo(), 850)
+
+		mustAct(t, betting, Action{"Chris", ActionCall, 0})
+		assertStreet(t, betting, Flop)
+		assertToAct(t, betting, "Pepper")
+
+		mustAct(t, betting, Action{"Pepper", ActionBet, 100})
+		assertInts(t, betting.MinRaiseTo(), 200)
+	})
+
+	t.Run("an all in for less than a full raise doesn't reopen the betting", func(t *testing.T) {
+		betting := mustNewBetting(t, testBlinds, 0, "Chris", 1000, "Cleo", 250, "Pepper", 1000)
+		mustAct(t, betting, Action{"Chris", ActionCall, 0}, Action{"Cleo", ActionCall, 0}, Action{"Pepper", ActionCheck, 0})
+
+		mustAct(t, betting, Action{"Cleo", ActionCheck, 0}, Action{"Pepper", ActionBet, 100}, Action{"Chris", ActionCall, 0})
+		mustAct(t, betting, Action{"Cleo", ActionAllIn, 0})
+		assertInts(t, betting.CurrentBet(), 150)
+		assertInts(t, betting.MinRaiseTo(), 250)
+
+		assertIllegal(t, betting.Act(Action{"Pepper", ActionRaise, 400}), ErrRaiseNotAllowed)
+		mustAct(t, betting, Action{"Pepper", ActionCall, 0}, Action{"Chris", ActionCall, 0})
+		assertStreet(t, betting, Turn)
+	})
+
+	t.Run("everyone folding ends the hand and returns the uncalled bet", func(t *testing.T) {
+		betting := mustNewBetting(t, testBlinds, 0, "Chris", 1000, "Cleo", 1000, "Pepper", 1000)
+
+		mustAct(t, betting, Action{"Chris", ActionRaise, 300}, Action{"Cleo", ActionFold, 0}, Action{"Pepper", ActionFold, 0})
+
+		assertStreet(t, betting, Showdown)
+		assertPots(t, betting, []Pot{{250, []string{"Chris"}}})
+
+		won, err := betting.Settle(nil)
+		assertNoError(t, err)
+		assertWon(t, won, map[string]int{"Chris": 250})
+		assertStacks(t, betting, 1150, 950, 900)
+	})
+
+	t.Run("nobody bets again once all but one player is all in", func(t *testing.T) {
+		betting := mustNewBetting(t, testBlinds, 0, "Chris", 1000, "Cleo", 300, "Pepper", 1000)
+
+		mustAct(t, betting, Action{"Chris", ActionFold, 0}, Action{"Cleo", ActionAllIn, 0}, Action{"Pepper", ActionCall, 0})
+
+		assertStreet(t, betting, Showdown)
+		assertPots(t, betting, []Pot{{600, []string{"Cleo", "Pepper"}}})
+	})
+}
+
+func TestIllegalActions(t *testing.T) {
+	preFlop := func(t *testing.T) *Betting {
+		return mustNewBetting(t, testBlinds, 0, "Chris", 1000, "Cleo", 1000, "Pepper", 1000)
+	}
+	onTheFlop := func(t *testing.T) *Betting {
+		betting := preFlop(t)
+		mustAct(t, betting, Action{"Chris", ActionCall, 0}, Action{"Cleo", ActionCall, 0}, Action{"Pepper", ActionCheck, 0})
+		return betting
+	}
+	handOver := func(t *testing.T) *Betting {
+		betting := preFlop(t)
+		mustAct(t, betting, Action{"Chris", ActionFold, 0}, Action{"Cleo", ActionFold, 0})
+		return betting
+	}
+
+	cases := []struct {
+		name    string
+		betting func(t *testing.T) *Betting
+		action  Action
+		want    error
+	}{
+		{"acting out of turn", preFlop, Action{"Pepper", ActionFold, 0}, ErrNotYourTurn},
+		{"unknown action", preFlop, Action{"Chris", "shove", 0}, ErrUnknownAction},
+		{"checking facing a bet", preFlop, Action{"Chris", ActionCheck, 0}, ErrCannotCheck},
+		{"calling without a bet", onTheFlop, Action{"Cleo", ActionCall, 0}, ErrNothingToCall},
+		{"betting into a bet", preFlop, Action{"Chris", ActionBet, 300}, ErrBetAlreadyMade},
+		{"raising without a bet", onTheFlop, Action{"Cleo", ActionRaise, 300}, ErrNoBetToRaise},
+		{"betting less than the big blind", onTheFlop, Action{"Cleo", ActionBet, 50}, ErrBetTooSmall},
+		{"raising by less than the big blind", preFlop, Action{"Chris", ActionRaise, 150}, ErrRaiseTooSmall},
+		{"raising to the current bet", preFlop, Action{"Chris", ActionRaise, 100}, ErrRaiseTooSmall},
+		{"betting more than their stack", onTheFlop, Action{"Cleo", ActionBet, 1000}, ErrNotEnoughChips},
+		{"acting once the hand is over", handOver, Action{"Pepper", ActionCheck, 0}, ErrBettingOver},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			betting := c.betting(t)
+			before := betting.Seats()
+			toAct := betting.ToAct()
+
+			assertIllegal(t, betting.Act(c.action), c.want)
+
+			assertSeats(t, betting, before)
+			assertToAct(t, betting, toAct)
+		})
+	}
+}
+
+func TestPots(t *testing.T) {
+	t.Run("all ins for different amounts make side pots", func(t *testing.T) {
+		betting := mustNewBetting(t, testBlinds, 0, "Chris", 100, "Cleo", 300, "Pepper", 500, "Tiest", 1000)
+
+		mustAct(t, betting,
+			Action{"Tiest", ActionCall, 0},
+			Action{"Chris", ActionAllIn, 0},
+			Action{"Cleo", ActionAllIn, 0},
+			Action{"Pepper", ActionAllIn, 0},
+			Action{"Tiest", ActionFold, 0},
+		)
+
+		assertStreet(t, betting, Showdown)
+		assertPots(t, betting, []Pot{
+			{400, []string{"Chris", "Cleo", "Pepper"}},
+			{400, []string{"Cleo", "Pepper"}},
+		})
+		assertStacks(t, betting, 0, 0, 200, 900)
+
+		won, err := betting.Settle([]HandResult{
+			handResult("Chris", FullHouse),
+			handResult("Cleo", OnePair),
+			handResult("Pepper", HighCard),
+		})
+		assertNoError(t, err)
+		assertWon(t, won, map[string]int{"Chris": 400, "Cleo": 400})
+		assertStacks(t, betting, 400, 400, 200, 900)
+	})
+
+	t.Run("the short stack's win leaves the side pot to the next best hand", func(t *testing.T) {
+		betting := mustNewBetting(t, testBlinds, 0, "Chris", 1000, "Cleo", 200, "Pepper", 1000)
+		mustAct(t, betting, Action{"Chris", ActionCall, 0}, Action{"Cleo", ActionAllIn, 0}, Action{"Pepper", ActionCall, 0}, Action{"Chris", ActionCall, 0})
+		for betting.ToAct() != "" {
+			mustAct(t, betting, Action{betting.ToAct(), ActionCheck, 0})
+		}
+
+		won, err := betting.Settle([]HandResult{
+			handResult("Chris", TwoPair),
+			handResult("Cleo", Straight),
+			handResult("Pepper", OnePair),
+		})
+		assertNoError(t, err)
+		assertWon(t, won, map[string]int{"Cleo": 600})
+		assertStacks(t, betting, 800, 600, 800)
+	})
+
+	t.Run("a split pot gives the odd chip left of the button first", func(t *testing.T) {
+		betting := mustNewBetting(t, BlindLevel{SmallBlind: 25, BigBlind: 50}, 0, "Chris", 1000, "Cleo", 1000, "Pepper", 1000)
+		mustAct(t, betting, Action{"Chris", ActionFold, 0}, Action{"Cleo", ActionRaise, 101}, Action{"Pepper", ActionCall, 0})
+		for betting.ToAct() != "" {
+			mustAct(t, betting, Action{betting.ToAct(), ActionCheck, 0})
+		}
+
+		won, err := betting.Settle([]HandResult{handResult("Cleo", Flush), handResult("Pepper", Flush)})
+		assertNoError(t, err)
+		assertWon(t, won, map[string]int{"Cleo": 101, "Pepper": 101})
+
+		betting = mustNewBetting(t, BlindLevel{SmallBlind: 25, BigBlind: 50}, 0, "Chris", 1000, "Cleo", 1000, "Pepper", 1000)
+		mustAct(t, betting, Action{"Chris", ActionCall, 0}, Action{"Cleo", ActionFold, 0}, Action{"Pepper", ActionCheck, 0})
+		for betting.ToAct() != "" {
+			mustAct(t, betting, Action{betting.ToAct(), ActionCheck, 0})
+		}
+
+		won, err = betting.Settle([]HandResult{handResult("Chris", Flush), handResult("Pepper", Flush)})
+		assertNoError(t, err)
+		assertWon(t, won, map[string]int{"Pepper": 63, "Chris": 62})
+	})
+
+	t.Run("can only settle once betting is over, with everyone's hand", func(t *testing.T) {
+		betting := mustNewBetting(t, testBlinds, 0, "Chris", 1000, "Cleo", 1000)
+		if _, err := betting.Settle(nil); err != ErrBettingNotOver {
+			t.Errorf("got error %v want %v", err, ErrBettingNotOver)
+		}
+
+		mustAct(t, betting, Action{"Chris", ActionAllIn, 0}, Action{"Cleo", ActionCall, 0})
+		if _, err := betting.Settle([]HandResult{handResult("Chris", Flush)}); err == nil {
+			t.Error("expected an error settling without Cleo's hand")
+		}
+		_, err := betting.Settle([]HandResult{handResult("Chris", Flush), handResult("Cleo", Straight)})
+		assertNoError(t, err)
+		if _, err := betting.Settle(nil); err == nil {
+			t.Error("expected an error settling twice")
+		}
+	})
+}
+
+// mustNewBetting seats the players and stacks given in pairs, e.g. "Chris", 1000, "Cleo", 500.
+func mustNewBetting(t testing.TB, level BlindLevel, button int, playersAndStacks ...interface{}) *Betting {
+	t.Helper()
+	var seats []Seat
+	for i := 0; i < len(playersAndStacks); i += 2 {
+		seats = append(seats, Seat{Player: playersAndStacks[i].(string), Stack: playersAndStacks[i+1].(int)})
+	}
+	betting, err := NewBetting(seats, level, button)
+	assertNoError(t, err)
+	return betting
+}
+
+func mustAct(t testing.TB, betting *Betting, actions ...Action) {
+	t.Helper()
+	for _, action := range actions {
+		if err := betting.Act(action); err != nil {
+			t.Fatalf("didn't expect an error but got one, %v", err)
+		}
+	}
+}
+
+func handResult(player string, category HandCategory) HandResult {
+	return HandResult{Player: player, Value: HandValue{Category: category}}
+}
+
+func assertIllegal(t testing.TB, err, want error) {
+	t.Helper()
+	var illegal *IllegalActionError
+	if !errors.As(err, &illegal) {
+		t.Fatalf("got error %v want an *IllegalActionError", err)
+	}
+	if !errors.Is(err, want) {
+		t.Errorf("got error %v want %v", err, want)
+	}
+}
+
+func assertSeats(t testing.TB, betting *Betting, want []Seat) {
+	t.Helper()
+	got := betting.Seats()
+	for i := range got {
+		got[i].acted = false
+	}
+	for i := range want {
+		want[i].acted = false
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got seats %+v want %+v", got, want)
+	}
+}
+
+func assertStacks(t testing.TB, betting *Betting, want ...int) {
+	t.Helper()
+	var got []int
+	for _, seat := range betting.Seats() {
+		got = append(got, seat.Stack)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got stacks %v want %v", got, want)
+	}
+}
+
+func assertPots(t testing.TB, betting *Betting, want []Pot) {
+	t.Helper()
+	if got := betting.Pots(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got pots %+v want %+v", got, want)
+	}
+}
+
+func assertWon(t testing.TB, got, want map[string]int) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got winnings %v want %v", got, want)
+	}
+}
+
+func assertToAct(t testing.TB, betting *Betting, want string) {
+	t.Helper()
+	if got := betting.ToAct(); got != want {
+		t.Errorf("got %q to act want %q", got, want)
+	}
+}
+
+func assertStreet(t testing.TB, betting *Betting, want string) {
+	t.Helper()
+	if got := betting.Street(); got != want {
+		t.Errorf("got street %q want %q", got, want)
+	}
+}
+
+func assertInts(t testing.TB, got, want int) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got %d want %d", got, want)
+	}
+}
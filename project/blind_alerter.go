@@ -1,24 +1,131 @@
 package poker
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"log"
+	"strings"
 	"time"
+
+	"learn-go-with-tests/clock"
 )
 
+// BlindAlerter schedules an alert of the blind amount to be written after duration,
+// the alert is dropped if ctx is done first.
 type BlindAlerter interface {
-	ScheduleAlertAt(duration time.Duration, amount int, to io.Writer)
+	ScheduleAlertAt(ctx context.Context, duration time.Duration, amount int, to io.Writer)
+}
+
+type BlinderAlerterFunc func(ctx context.Context, duration time.Duration, amount int, to io.Writer)
+
+func (a BlinderAlerterFunc) ScheduleAlertAt(ctx context.Context, duration time.Duration, amount int, to io.Writer) {
+	a(ctx, duration, amount, to)
 }
 
-type BlinderAlerterFunc func(duration time.Duration, amount int, to io.Writer)
+// Alerter schedules blind alerts on the real clock.
+func Alerter(ctx context.Context, duration time.Duration, amount int, to io.Writer) {
+	NewAlerter(clock.Real{}).ScheduleAlertAt(ctx, duration, amount, to)
+}
+
+// BlindWarner is implemented by alerters that can also warn players the
+// blinds are about to go up.
+type BlindWarner interface {
+	// ScheduleWarningAt schedules a warning to be written after duration
+	// that the blinds go up to amount in another in.
+	ScheduleWarningAt(ctx context.Context, duration time.Duration, amount int, in time.Duration, to io.Writer)
+}
+
+// ClockAlerter schedules blind alerts and warnings on a clock.
+type ClockAlerter struct {
+	clock clock.Clock
+}
+
+// NewAlerter returns a ClockAlerter that schedules on c.
+func NewAlerter(c clock.Clock) *ClockAlerter {
+	return &ClockAlerter{clock: c}
+}
+
+func (a *ClockAlerter) ScheduleAlertAt(ctx context.Context, duration time.Duration, amount int, to io.Writer) {
+	a.schedule(ctx, duration, func() error {
+		return announceBlind(to, amount)
+	})
+}
 
-func (a BlinderAlerterFunc) ScheduleAlertAt(duration time.Duration, amount int, to io.Writer) {
-	a(duration, amount, to)
+func (a *ClockAlerter) ScheduleWarningAt(ctx context.Context, duration time.Duration, amount int, in time.Duration, to io.Writer) {
+	a.schedule(ctx, duration, func() error {
+		return announceWarning(to, amount, in)
+	})
 }
 
-func Alerter(duration time.Duration, amount int, to io.Writer) {
-	time.AfterFunc(duration, func() {
-		_, err := fmt.Fprintf(to, "Blind is now %d\n", amount)
-		check(err)
+// schedule announces after duration unless ctx is done first. Once the alert
+// has fired it no longer needs stopping, so it stops watching ctx, otherwise a
+// long tournament would keep every alert it ever scheduled registered on it.
+func (a *ClockAlerter) schedule(ctx context.Context, duration time.Duration, announce func() error) {
+	var stopWatching func() bool
+	watching := make(chan struct{})
+	timer := a.clock.AfterFunc(duration, func() {
+		<-watching
+		stopWatching()
+		if ctx.Err() != nil {
+			return
+		}
+		if err := announce(); err != nil {
+			log.Printf("problem sending blind alert %v\n", err)
+		}
 	})
+	stopWatching = context.AfterFunc(ctx, func() { timer.Stop() })
+	close(watching)
+}
+
+// BlindAnnouncer is implemented by alert destinations that want the blind
+// amount itself rather than the text of the alert.
+type BlindAnnouncer interface {
+	AnnounceBlind(amount int) error
+}
+
+// WarningAnnouncer is implemented by alert destinations that want the blind
+// amount and time left rather than the text of a warning.
+type WarningAnnouncer interface {
+	AnnounceWarning(amount int, in time.Duration) error
+}
+
+func announceBlind(to io.Writer, amount int) error {
+	if announcer, ok := to.(BlindAnnouncer); ok {
+		return announcer.AnnounceBlind(amount)
+	}
+	_, err := fmt.Fprintf(to, "Blind is now %d\n", amount)
+	return err
+}
+
+func announceWarning(to io.Writer, amount int, in time.Duration) error {
+	if announcer, ok := to.(WarningAnnouncer); ok {
+		return announcer.AnnounceWarning(amount, in)
+	}
+	_, err := fmt.Fprintf(to, "Blinds go up to %d in %s\n", amount, formatCountdown(in))
+	return err
+}
+
+// formatCountdown writes d to the second for people, e.g. "1 minute 30 seconds".
+func formatCountdown(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d <= 0 {
+		return "0 seconds"
+	}
+
+	var parts []string
+	for _, unit := range []struct {
+		name string
+		size time.Duration
+	}{{"hour", time.Hour}, {"minute", time.Minute}, {"second", time.Second}} {
+		n := int(d / unit.size)
+		d -= time.Duration(n) * unit.size
+		switch {
+		case n == 1:
+			parts = append(parts, "1 "+unit.name)
+		case n > 1:
+			parts = append(parts, fmt.Sprintf("%d %ss", n, unit.name))
+		}
+	}
+	return strings.Join(parts, " ")
 }
@@ -0,0 +1,210 @@
+package poker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"learn-go-with-tests/clock"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.String()
+}
+
+type spyBlindAnnouncer struct {
+	syncBuffer
+	amounts chan int
+}
+
+func (s *spyBlindAnnouncer) AnnounceBlind(amount int) error {
+	s.amounts <- amount
+	return nil
+}
+
+func TestAlerter(t *testing.T) {
+	t.Run("it writes the blind once the duration has passed", func(t *testing.T) {
+		out := &syncBuffer{}
+
+		Alerter(context.Background(), time.Millisecond, 100, out)
+
+		passed := retryUntil(500*time.Millisecond, func() bool {
+			return out.String() == "Blind is now 100\n"
+		})
+		if !passed {
+			t.Errorf("got %q want %q", out.String(), "Blind is now 100\n")
+		}
+	})
+
+	t.Run("it announces the amount to a BlindAnnouncer", func(t *testing.T) {
+		out := &spyBlindAnnouncer{amounts: make(chan int, 1)}
+
+		Alerter(context.Background(), time.Millisecond, 200, out)
+
+		select {
+		case got := <-out.amounts:
+			if got != 200 {
+				t.Errorf("got %d want %d", got, 200)
+			}
+		case <-time.After(500 * time.Millisecond):
+			t.Error("timed out waiting for the blind to be announced")
+		}
+	})
+
+	t.Run("it writes nothing once the context is cancelled", func(t *testing.T) {
+		out := &syncBuffer{}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		Alerter(ctx, 10*time.Millisecond, 100, out)
+		cancel()
+
+		time.Sleep(30 * time.Millisecond)
+		if got := out.String(); got != "" {
+			t.Errorf("got %q but expected nothing to be written", got)
+		}
+	})
+}
+
+func TestClockAlerter(t *testing.T) {
+	t.Run("it writes alerts and warnings once the clock reaches them", func(t *testing.T) {
+		fake := clock.NewFake(time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC))
+		alerter := NewAlerter(fake)
+		out := &bytes.Buffer{}
+
+		alerter.ScheduleWarningAt(context.Background(), 9*time.Minute, 200, time.Minute, out)
+		alerter.ScheduleWarningAt(context.Background(), 9*time.Minute+50*time.Second, 200, 10*time.Second, out)
+		alerter.ScheduleAlertAt(context.Background(), 10*time.Minute, 200, out)
+
+		fake.Advance(9*time.Minute + 59*time.Second)
+		want := "Blinds go up to 200 in 1 minute\nBlinds go up to 200 in 10 seconds\n"
+		if out.String() != want {
+			t.Errorf("got %q want %q", out.String(), want)
+		}
+
+		fake.Advance(time.Second)
+		want += "Blind is now 200\n"
+		if out.String() != want {
+			t.Errorf("got %q want %q", out.String(), want)
+		}
+	})
+
+	t.Run("it announces warnings to a WarningAnnouncer", func(t *testing.T) {
+		fake := clock.NewFake(time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC))
+		out := &spyWarningAnnouncer{}
+
+		NewAlerter(fake).ScheduleWarningAt(context.Background(), time.Minute, 400, 30*time.Second, out)
+		fake.Advance(time.Minute)
+
+		if want := []string{"400 in 30s"}; !reflect.DeepEqual(out.warnings, want) {
+			t.Errorf("got %v want %v", out.warnings, want)
+		}
+	})
+
+	t.Run("it drops warnings once the context is cancelled", func(t *testing.T) {
+		fake := clock.NewFake(time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC))
+		out := &bytes.Buffer{}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		NewAlerter(fake).ScheduleWarningAt(ctx, time.Minute, 400, 30*time.Second, out)
+		cancel()
+		fake.Advance(time.Hour)
+
+		if got := out.String(); got != "" {
+			t.Errorf("got %q but expected nothing to be written", got)
+		}
+	})
+
+	t.Run("it stops watching the context once an alert has fired", func(t *testing.T) {
+		fake := clock.NewFake(time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC))
+		ctx := &watchedContext{Context: context.Background(), done: make(chan struct{})}
+
+		alerter := NewAlerter(fake)
+		alerter.ScheduleAlertAt(ctx, time.Minute, 200, &bytes.Buffer{})
+		alerter.ScheduleAlertAt(ctx, time.Hour, 400, &bytes.Buffer{})
+		fake.Advance(time.Minute)
+
+		if got := ctx.watchers(); got != 1 {
+			t.Errorf("got %d functions watching the context want 1", got)
+		}
+	})
+}
+
+// watchedContext counts the functions waiting for it to be done.
+type watchedContext struct {
+	context.Context
+	done chan struct{}
+
+	mu       sync.Mutex
+	watching int
+}
+
+func (c *watchedContext) Done() <-chan struct{} {
+	return c.done
+}
+
+func (c *watchedContext) AfterFunc(f func()) (stop func() bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.watching++
+	return func() bool {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.watching--
+		return true
+	}
+}
+
+func (c *watchedContext) watchers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.watching
+}
+
+type spyWarningAnnouncer struct {
+	bytes.Buffer
+	warnings []string
+}
+
+func (s *spyWarningAnnouncer) AnnounceWarning(amount int, in time.Duration) error {
+	s.warnings = append(s.warnings, fmt.Sprintf("%d in %v", amount, in))
+	return nil
+}
+
+func TestFormatCountdown(t *testing.T) {
+	cases := []struct {
+		in   time.Duration
+		want string
+	}{
+		{time.Minute, "1 minute"},
+		{10 * time.Second, "10 seconds"},
+		{time.Second, "1 second"},
+		{90 * time.Second, "1 minute 30 seconds"},
+		{4*time.Minute + 12*time.Second + 400*time.Millisecond, "4 minutes 12 seconds"},
+		{time.Hour + 2*time.Second, "1 hour 2 seconds"},
+		{2 * time.Hour, "2 hours"},
+		{0, "0 seconds"},
+	}
+
+	for _, c := range cases {
+		if got := formatCountdown(c.in); got != c.want {
+			t.Errorf("formatCountdown(%v) got %q want %q", c.in, got, c.want)
+		}
+	}
+}
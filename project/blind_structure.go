@@ -0,0 +1,293 @@
+package poker
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultBlindStructure is the name of the structure used when none is chosen.
+const DefaultBlindStructure = "standard"
+
+// BlindLevel is a single level of a blind structure.
+// A zero Duration means the level lasts 5 minutes plus one minute per player.
+type BlindLevel struct {
+	SmallBlind int           `json:"small_blind" yaml:"small_blind"`
+	BigBlind   int           `json:"big_blind" yaml:"big_blind"`
+	Ante       int           `json:"ante" yaml:"ante"`
+	Duration   time.Duration `json:"duration" yaml:"duration"`
+}
+
+// UnmarshalJSON reads the duration as a string such as "10m".
+func (l *BlindLevel) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		SmallBlind int    `json:"small_blind"`
+		BigBlind   int    `json:"big_blind"`
+		Ante       int    `json:"ante"`
+		Duration   string `json:"duration"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	var duration time.Duration
+	if raw.Duration != "" {
+		d, err := time.ParseDuration(raw.Duration)
+		if err != nil {
+			return fmt.Errorf("problem parsing level duration %q, %v", raw.Duration, err)
+		}
+		duration = d
+	}
+
+	*l = BlindLevel{raw.SmallBlind, raw.BigBlind, raw.Ante, duration}
+	return nil
+}
+
+// MarshalJSON writes the duration as a string such as "10m0s".
+func (l BlindLevel) MarshalJSON() ([]byte, error) {
+	raw := struct {
+		SmallBlind int    `json:"small_blind"`
+		BigBlind   int    `json:"big_blind"`
+		Ante       int    `json:"ante"`
+		Duration   string `json:"duration,omitempty"`
+	}{l.SmallBlind, l.BigBlind, l.Ante, ""}
+	if l.Duration != 0 {
+		raw.Duration = l.Duration.String()
+	}
+	return json.Marshal(raw)
+}
+
+// DefaultBlindWarnings are how long before the blinds go up players are
+// warned, for structures that don't say.
+var DefaultBlindWarnings = Durations{time.Minute, 10 * time.Second}
+
+// BlindStructure is a named schedule of blind levels.
+// Warnings are how long before each level starts players are warned the
+// blinds are going up, DefaultBlindWarnings when not set and none when empty.
+type BlindStructure struct {
+	Name     string       `json:"name" yaml:"name"`
+	Levels   []BlindLevel `json:"levels" yaml:"levels"`
+	Warnings Durations    `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+}
+
+// Durations are read and written in JSON as strings such as "1m".
+type Durations []time.Duration
+
+// UnmarshalJSON reads each duration from a string such as "10s".
+func (d *Durations) UnmarshalJSON(data []byte) error {
+	var raw []string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if raw == nil {
+		*d = nil
+		return nil
+	}
+
+	durations := Durations{}
+	for _, s := range raw {
+		duration, err := time.ParseDuration(s)
+		if err != nil {
+			return fmt.Errorf("problem parsing duration %q, %v", s, err)
+		}
+		durations = append(durations, duration)
+	}
+	*d = durations
+	return nil
+}
+
+// MarshalJSON writes each duration as a string such as "10s".
+func (d Durations) MarshalJSON() ([]byte, error) {
+	if d == nil {
+		return []byte("null"), nil
+	}
+	raw := make([]string, len(d))
+	for i, duration := range d {
+		raw[i] = duration.String()
+	}
+	return json.Marshal(raw)
+}
+
+// LevelWarnings returns how long before each level starts players are warned, longest first.
+func (b BlindStructure) LevelWarnings() Durations {
+	warnings := b.Warnings
+	if warnings == nil {
+		warnings = DefaultBlindWarnings
+	}
+	warnings = append(Durations{}, warnings...)
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i] > warnings[j] })
+	return warnings
+}
+
+// LevelDuration returns how long the given level lasts for a game of numberOfPlayers.
+func (b BlindStructure) LevelDuration(level, numberOfPlayers int) time.Duration {
+	if d := b.Levels[level].Duration; d != 0 {
+		return d
+	}
+	return time.Duration(5+numberOfPlayers) * time.Minute
+}
+
+// Validate checks the structure can be used to run a game.
+func (b BlindStructure) Validate() error {
+	if b.Name == "" {
+		return fmt.Errorf("blind structure has no name")
+	}
+	if len(b.Levels) == 0 {
+		return fmt.Errorf("blind structure %q has no levels", b.Name)
+	}
+	for i, level := range b.Levels {
+		if level.SmallBlind <= 0 || level.BigBlind < level.SmallBlind || level.Ante < 0 || level.Duration < 0 {
+			return fmt.Errorf("blind structure %q has an invalid level %d %+v", b.Name, i+1, level)
+		}
+	}
+	for _, warning := range b.Warnings {
+		if warning <= 0 {
+			return fmt.Errorf("blind structure %q has an invalid warning %v", b.Name, warning)
+		}
+	}
+	return nil
+}
+
+// StandardBlindStructure is the schedule the game has always used.
+var StandardBlindStructure = BlindStructure{
+	Name: DefaultBlindStructure,
+	Levels: []BlindLevel{
+		{SmallBlind: 100, BigBlind: 200},
+		{SmallBlind: 200, BigBlind: 400},
+		{SmallBlind: 300, BigBlind: 600},
+		{SmallBlind: 400, BigBlind: 800},
+		{SmallBlind: 500, BigBlind: 1000},
+		{SmallBlind: 600, BigBlind: 1200},
+		{SmallBlind: 800, BigBlind: 1600},
+		{SmallBlind: 1000, BigBlind: 2000},
+		{SmallBlind: 2000, BigBlind: 4000},
+		{SmallBlind: 4000, BigBlind: 8000},
+		{SmallBlind: 8000, BigBlind: 16000},
+	},
+}
+
+// BlindStructures holds the structures a game can be started with, by name.
+type BlindStructures map[string]BlindStructure
+
+// NewBlindStructures returns the standard structure plus any given.
+func NewBlindStructures(structures ...BlindStructure) BlindStructures {
+	b := BlindStructures{DefaultBlindStructure: StandardBlindStructure}
+	for _, s := range structures {
+		b[s.Name] = s
+	}
+	return b
+}
+
+// Get finds a structure by name, an empty name gives the default structure.
+func (b BlindStructures) Get(name string) (BlindStructure, error) {
+	if name == "" {
+		name = DefaultBlindStructure
+	}
+	s, ok := b[name]
+	if !ok {
+		return BlindStructure{}, fmt.Errorf("unknown blind structure %q, choose one of %s", name, strings.Join(b.Names(), ", "))
+	}
+	return s, nil
+}
+
+// Names returns the names of all structures, sorted.
+func (b BlindStructures) Names() []string {
+	var names []string
+	for name := range b {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LoadBlindStructure reads a structure from a .json, .yaml or .yml file.
+// When the file does not set a name, the file name without extension is used.
+func LoadBlindStructure(path string) (BlindStructure, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return BlindStructure{}, fmt.Errorf("problem reading blind structure %s, %v", path, err)
+	}
+	return parseBlindStructure(path, data)
+}
+
+func parseBlindStructure(path string, data []byte) (BlindStructure, error) {
+	var structure BlindStructure
+	var err error
+	ext := filepath.Ext(path)
+	switch ext {
+	case ".json":
+		err = json.Unmarshal(data, &structure)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &structure)
+	default:
+		return BlindStructure{}, fmt.Errorf("unsupported blind structure file %s", path)
+	}
+	if err != nil {
+		return BlindStructure{}, fmt.Errorf("problem parsing blind structure %s, %v", path, err)
+	}
+
+	if structure.Name == "" {
+		structure.Name = strings.TrimSuffix(filepath.Base(path), ext)
+	}
+
+	return structure, structure.Validate()
+}
+
+// LoadBlindStructures reads every structure file in dir, which must exist.
+func LoadBlindStructures(dir string) ([]BlindStructure, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("problem reading blind structures from %s, %v", dir, err)
+	}
+	return readBlindStructures(os.DirFS(dir), dir)
+}
+
+//go:embed blinds
+var builtInBlinds embed.FS
+
+// BuiltInBlindStructures are the structures in the blinds directory, built
+// into the binary so they don't depend on where it is run from.
+func BuiltInBlindStructures() ([]BlindStructure, error) {
+	dir, err := fs.Sub(builtInBlinds, "blinds")
+	if err != nil {
+		return nil, err
+	}
+	return readBlindStructures(dir, "blinds")
+}
+
+// readBlindStructures reads every structure file in fsys, which is called dir.
+func readBlindStructures(fsys fs.FS, dir string) ([]BlindStructure, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("problem reading blind structures from %s, %v", dir, err)
+	}
+
+	var structures []BlindStructure
+	for _, entry := range entries {
+		switch filepath.Ext(entry.Name()) {
+		case ".json", ".yaml", ".yml":
+		default:
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("problem reading blind structure %s, %v", path, err)
+		}
+		s, err := parseBlindStructure(path, data)
+		if err != nil {
+			return nil, err
+		}
+		structures = append(structures, s)
+	}
+
+	return structures, nil
+}
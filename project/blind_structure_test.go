@@ -0,0 +1,202 @@
+package poker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoadBlindStructure(t *testing.T) {
+	want := BlindStructure{
+		Name: "turbo",
+		Levels: []BlindLevel{
+			{SmallBlind: 25, BigBlind: 50, Duration: 5 * time.Minute},
+			{SmallBlind: 50, BigBlind: 100, Ante: 10, Duration: 5 * time.Minute},
+		},
+	}
+
+	t.Run("from a JSON file", func(t *testing.T) {
+		path := writeBlindStructureFile(t, "turbo.json", `{
+			"name": "turbo",
+			"levels": [
+				{"small_blind": 25, "big_blind": 50, "duration": "5m"},
+				{"small_blind": 50, "big_blind": 100, "ante": 10, "duration": "5m"}
+			]}`)
+
+		got, err := LoadBlindStructure(path)
+		assertNoError(t, err)
+		assertBlindStructure(t, got, want)
+	})
+
+	t.Run("from a YAML file", func(t *testing.T) {
+		path := writeBlindStructureFile(t, "turbo.yaml", `
+name: turbo
+levels:
+  - {small_blind: 25, big_blind: 50, duration: 5m}
+  - {small_blind: 50, big_blind: 100, ante: 10, duration: 5m}
+`)
+
+		got, err := LoadBlindStructure(path)
+		assertNoError(t, err)
+		assertBlindStructure(t, got, want)
+	})
+
+	t.Run("named after the file when no name is given", func(t *testing.T) {
+		path := writeBlindStructureFile(t, "turbo.yml", `
+levels:
+  - {small_blind: 25, big_blind: 50, duration: 5m}
+  - {small_blind: 50, big_blind: 100, ante: 10, duration: 5m}
+`)
+
+		got, err := LoadBlindStructure(path)
+		assertNoError(t, err)
+		assertBlindStructure(t, got, want)
+	})
+
+	t.Run("rejects a structure without levels", func(t *testing.T) {
+		path := writeBlindStructureFile(t, "empty.json", `{"name": "empty"}`)
+
+		_, err := LoadBlindStructure(path)
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+
+	t.Run("rejects a big blind smaller than the small blind", func(t *testing.T) {
+		path := writeBlindStructureFile(t, "bad.json", `{"levels": [{"small_blind": 100, "big_blind": 50}]}`)
+
+		_, err := LoadBlindStructure(path)
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+
+	t.Run("with warnings", func(t *testing.T) {
+		warned := want
+		warned.Warnings = Durations{2 * time.Minute, 30 * time.Second, 5 * time.Second}
+
+		json := writeBlindStructureFile(t, "turbo.json", `{
+			"name": "turbo",
+			"warnings": ["2m", "30s", "5s"],
+			"levels": [
+				{"small_blind": 25, "big_blind": 50, "duration": "5m"},
+				{"small_blind": 50, "big_blind": 100, "ante": 10, "duration": "5m"}
+			]}`)
+		yaml := writeBlindStructureFile(t, "turbo.yaml", `
+name: turbo
+warnings: [2m, 30s, 5s]
+levels:
+  - {small_blind: 25, big_blind: 50, duration: 5m}
+  - {small_blind: 50, big_blind: 100, ante: 10, duration: 5m}
+`)
+
+		for _, path := range []string{json, yaml} {
+			got, err := LoadBlindStructure(path)
+			assertNoError(t, err)
+			assertBlindStructure(t, got, warned)
+		}
+	})
+
+	t.Run("rejects a warning that isn't ahead of the level", func(t *testing.T) {
+		path := writeBlindStructureFile(t, "bad.json", `{"warnings": ["0s"], "levels": [{"small_blind": 50, "big_blind": 100}]}`)
+
+		_, err := LoadBlindStructure(path)
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+
+	t.Run("loads every structure in a directory", func(t *testing.T) {
+		structures, err := LoadBlindStructures("blinds")
+		assertNoError(t, err)
+
+		got := NewBlindStructures(structures...).Names()
+		want := []string{"deepstack", "standard", "tournament", "turbo"}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v want %v", got, want)
+		}
+	})
+
+	t.Run("a directory that doesn't exist is an error", func(t *testing.T) {
+		if _, err := LoadBlindStructures(filepath.Join(t.TempDir(), "missing")); err == nil {
+			t.Error("expected an error loading a directory that doesn't exist")
+		}
+	})
+
+	t.Run("the blinds directory is built in", func(t *testing.T) {
+		builtIn, err := BuiltInBlindStructures()
+		assertNoError(t, err)
+		loaded, err := LoadBlindStructures("blinds")
+		assertNoError(t, err)
+
+		if !reflect.DeepEqual(builtIn, loaded) {
+			t.Errorf("got built in structures %+v want %+v", builtIn, loaded)
+		}
+	})
+}
+
+func TestBlindStructure_LevelWarnings(t *testing.T) {
+	cases := []struct {
+		name     string
+		warnings Durations
+		want     Durations
+	}{
+		{"defaults when not set", nil, Durations{time.Minute, 10 * time.Second}},
+		{"none when empty", Durations{}, Durations{}},
+		{"longest first", Durations{5 * time.Second, 2 * time.Minute}, Durations{2 * time.Minute, 5 * time.Second}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := BlindStructure{Warnings: c.warnings}.LevelWarnings()
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("got %v want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestBlindStructures_Get(t *testing.T) {
+	structures := NewBlindStructures()
+
+	t.Run("empty name is the standard structure", func(t *testing.T) {
+		got, err := structures.Get("")
+		assertNoError(t, err)
+		assertBlindStructure(t, got, StandardBlindStructure)
+	})
+
+	t.Run("unknown name is an error", func(t *testing.T) {
+		_, err := structures.Get("hyper")
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+}
+
+func writeBlindStructureFile(t testing.TB, name, data string) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "blinds")
+	if err != nil {
+		t.Fatalf("could not create temp dir %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	path := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(path, []byte(data), 0666); err != nil {
+		t.Fatalf("could not write %s %v", path, err)
+	}
+	return path
+}
+
+func assertBlindStructure(t testing.TB, got, want BlindStructure) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
@@ -0,0 +1,102 @@
+package poker
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Suit of a playing card.
+type Suit int
+
+// Suits in a deck.
+const (
+	Clubs Suit = iota
+	Diamonds
+	Hearts
+	Spades
+)
+
+const suitLetters = "cdhs"
+
+func (s Suit) String() string {
+	return string(suitLetters[s])
+}
+
+// Rank of a playing card, from Two up to Ace.
+type Rank int
+
+// Ranks in a deck, aces are high except in a five high straight.
+const (
+	Two Rank = iota + 2
+	Three
+	Four
+	Five
+	Six
+	Seven
+	Eight
+	Nine
+	Ten
+	Jack
+	Queen
+	King
+	Ace
+)
+
+const rankLetters = "23456789TJQKA"
+
+func (r Rank) String() string {
+	return string(rankLetters[r-Two])
+}
+
+// Card is a playing card, written as its rank then suit, e.g. "As" or "Td".
+type Card struct {
+	Rank Rank
+	Suit Suit
+}
+
+func (c Card) String() string {
+	return c.Rank.String() + c.Suit.String()
+}
+
+// MarshalText writes the card as e.g. "As".
+func (c Card) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText reads a card written as e.g. "As".
+func (c *Card) UnmarshalText(text []byte) error {
+	card, err := ParseCard(string(text))
+	if err != nil {
+		return err
+	}
+	*c = card
+	return nil
+}
+
+// ParseCard reads a card written as its rank then suit, e.g. "As", "Td" or "10d".
+func ParseCard(s string) (Card, error) {
+	s = strings.Replace(s, "10", "T", 1)
+	if len(s) != 2 {
+		return Card{}, fmt.Errorf("invalid card %q", s)
+	}
+
+	rank := strings.IndexByte(rankLetters, strings.ToUpper(s[:1])[0])
+	suit := strings.IndexByte(suitLetters, strings.ToLower(s[1:])[0])
+	if rank < 0 || suit < 0 {
+		return Card{}, fmt.Errorf("invalid card %q", s)
+	}
+	return Card{Rank(rank) + Two, Suit(suit)}, nil
+}
+
+// ParseCards reads cards separated by spaces, e.g. "As Kd 7c".
+func ParseCards(s string) ([]Card, error) {
+	var cards []Card
+	for _, field := range strings.Fields(s) {
+		card, err := ParseCard(field)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
@@ -2,6 +2,7 @@ package poker
 
 import (
 	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
@@ -16,6 +17,8 @@ const (
 	BadPlayerInputErrMsg = "Bad value received for number of players, please try again with number"
 	// BadWinnerInputMessage is the text telling user they declared the winner wrong
 	BadWinnerInputMessage = "invalid winner input, expect format of 'PlayerName wins'"
+	// BadCommandMessage is the text telling user a command during the game wasn't understood.
+	BadCommandMessage = "invalid command, expect 'register PlayerName', 'seat PlayerName...', 'deal', 'PlayerName check|call|fold|all_in', 'PlayerName bet|raise Amount', 'showdown', 'PlayerName out', 'rebuy PlayerName', 'pause', 'resume', 'clock' or 'PlayerName wins'\n"
 )
 
 type CLI struct {
@@ -33,32 +36,182 @@ func NewCLI(in io.Reader, out io.Writer, game Game) *CLI {
 }
 
 func (cli *CLI) PlayPoker() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
 	fmt.Fprint(cli.out, PlayerPrompt)
 
-	numberOfPlayersInput := cli.readLine()
-	numberOfPlayers, err := strconv.Atoi(strings.Trim(numberOfPlayersInput, "\n"))
+	numberOfPlayers, blindStructure, err := extractGameSettings(cli.readLine())
 	if err != nil {
 		fmt.Fprint(cli.out, BadPlayerInputErrMsg)
 		return
 	}
 
-	cli.game.Start(numberOfPlayers, cli.out)
+	if err := cli.game.Start(ctx, numberOfPlayers, blindStructure, cli.out); err != nil {
+		fmt.Fprint(cli.out, err)
+		return
+	}
+
+	for cli.in.Scan() {
+		if finished := cli.runCommand(cli.in.Text()); finished {
+			return
+		}
+	}
+}
+
+// runCommand runs one of the commands accepted while a tournament is being
+// played, reporting whether it finished the game.
+func (cli *CLI) runCommand(command string) (finished bool) {
+	var err error
+	fields := strings.Fields(command)
+	action, isAction := parseAction(fields)
+
+	switch {
+	case len(fields) == 1 && fields[0] == "pause":
+		err = cli.pause(Pauser.Pause)
+	case len(fields) == 1 && fields[0] == "resume":
+		err = cli.pause(Pauser.Resume)
+	case len(fields) == 1 && fields[0] == "clock":
+		cli.showClock()
+	case len(fields) == 1 && fields[0] == "deal":
+		err = cli.deal(Dealer.Deal)
+	case len(fields) == 1 && fields[0] == "showdown":
+		err = cli.deal(func(dealer Dealer) error {
+			_, err := dealer.Showdown()
+			return err
+		})
+	case len(fields) == 2 && fields[0] == "register":
+		err = cli.register(fields[1])
+	case len(fields) >= 2 && fields[0] == "seat":
+		err = cli.seat(fields[1:])
+	case isAction:
+		err = cli.act(action)
+	case len(fields) == 2 && fields[0] == "rebuy":
+		err = cli.game.Rebuy(fields[1])
+	case len(fields) == 2 && fields[1] == "out":
+		err = cli.game.Eliminate(fields[0])
+	case strings.HasSuffix(command, " wins"):
+		winner, err := extractWinner(command)
+		if err != nil {
+			fmt.Fprint(cli.out, BadWinnerInputMessage)
+			return false
+		}
+		if err := cli.game.Finish(winner); err != nil {
+			fmt.Fprintln(cli.out, err)
+			return false
+		}
+		return true
+	default:
+		fmt.Fprint(cli.out, BadCommandMessage)
+	}
 
-	winnerInput := cli.readLine()
-	winner, err := extractWinner(winnerInput)
 	if err != nil {
-		fmt.Fprint(cli.out, BadWinnerInputMessage)
-		return
+		fmt.Fprintln(cli.out, err)
+	}
+	return false
+}
+
+// showClock prints the blind level and how long is left of it.
+func (cli *CLI) showClock() {
+	status := cli.game.Status()
+	paused := ""
+	if status.Paused {
+		paused = ", the clock is paused"
+	}
+	fmt.Fprintf(cli.out, "Level %d, blinds %d/%d, %s%s\n",
+		status.Level, status.SmallBlind, status.BigBlind, status.Countdown(), paused)
+}
+
+// register registers name with the game, so they can be knocked out,
+// buy back in and win.
+func (cli *CLI) register(name string) error {
+	registrar, ok := cli.game.(Registrar)
+	if !ok {
+		return ErrCannotRegister
+	}
+	if err := registrar.Register(name); err != nil {
+		return err
+	}
+	fmt.Fprintf(cli.out, "%s is registered\n", name)
+	return nil
+}
+
+// seat seats players at the game, so they are recorded as playing in it.
+func (cli *CLI) seat(players []string) error {
+	if err := seat(cli.game, players); err != nil {
+		return err
 	}
+	fmt.Fprintf(cli.out, "Seated %s\n", strings.Join(players, ", "))
+	return nil
+}
+
+// deal deals with the game's dealer, the cards are announced by the game.
+func (cli *CLI) deal(deal func(Dealer) error) error {
+	dealer, ok := cli.game.(Dealer)
+	if !ok {
+		return ErrCannotDeal
+	}
+	return deal(dealer)
+}
+
+// act takes a player's betting action, how the betting stands after it is
+// announced by the game.
+func (cli *CLI) act(action Action) error {
+	bettor, ok := cli.game.(Bettor)
+	if !ok {
+		return ErrCannotBet
+	}
+	return bettor.Act(action)
+}
+
+func (cli *CLI) pause(pauseOrResume func(Pauser) error) error {
+	pauser, ok := cli.game.(Pauser)
+	if !ok {
+		return ErrCannotPause
+	}
+	return pauseOrResume(pauser)
+}
 
-	cli.game.Finish(winner)
+// parseAction reads a betting action such as "Chris call" or "Chris raise 400".
+func parseAction(fields []string) (Action, bool) {
+	switch {
+	case len(fields) == 2 && contains([]string{ActionCheck, ActionCall, ActionFold, ActionAllIn}, fields[1]):
+		return Action{Player: fields[0], Type: fields[1]}, true
+	case len(fields) == 3 && (fields[1] == ActionBet || fields[1] == ActionRaise):
+		amount, err := strconv.Atoi(fields[2])
+		return Action{Player: fields[0], Type: fields[1], Amount: amount}, err == nil
+	}
+	return Action{}, false
+}
+
+// extractGameSettings reads the number of players optionally followed by
+// the name of a blind structure, e.g. "5" or "5 turbo".
+func extractGameSettings(userInput string) (numberOfPlayers int, blindStructure string, err error) {
+	fields := strings.Fields(userInput)
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, "", errors.New(BadPlayerInputErrMsg)
+	}
+
+	numberOfPlayers, err = strconv.Atoi(fields[0])
+	if err != nil {
+		return 0, "", err
+	}
+
+	if len(fields) == 2 {
+		blindStructure = fields[1]
+	}
+	return numberOfPlayers, blindStructure, nil
 }
 
 func extractWinner(userInput string) (string, error) {
 	if !strings.Contains(userInput, "wins") {
 		return "", errors.New(BadPlayerInputErrMsg)
 	}
-	return strings.Replace(userInput, " wins", "", 1), nil
+	winner := strings.TrimSpace(strings.Replace(userInput, " wins", "", 1))
+	if winner == "" {
+		return "", errors.New(BadWinnerInputMessage)
+	}
+	return winner, nil
 }
 
 func (cli *CLI) readLine() string {
@@ -2,12 +2,17 @@ package poker
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
 	"time"
+
+	"learn-go-with-tests/clock"
 )
 
 var (
@@ -18,26 +23,95 @@ var (
 )
 
 type GameSpy struct {
-	StartCalled     bool
-	StartCalledWith int
-	BlindAlert      []byte
+	StartCalled                   bool
+	StartCalledWith               int
+	StartCalledWithBlindStructure string
+	StartError                    error
+	StartCtx                      context.Context
+	BlindAlert                    []byte
 
 	FinishCalled     bool
 	FinishCalledWith string
+	// FinishError is returned by the next call to Finish, which leaves the game running.
+	FinishError error
+
+	// Commands are the tournament commands the game was given, in order.
+	Commands     []string
+	CommandError error
 }
 
-func (g *GameSpy) Start(numberOfPlayers int, out io.Writer) {
+func (g *GameSpy) Start(ctx context.Context, numberOfPlayers int, blindStructure string, out io.Writer) error {
 	fmt.Printf("Start called.. %d\n", numberOfPlayers)
+	g.StartCtx = ctx
 	g.StartCalled = true
 	g.StartCalledWith = numberOfPlayers
+	g.StartCalledWithBlindStructure = blindStructure
+	if g.StartError != nil {
+		return g.StartError
+	}
 	_, err := out.Write(g.BlindAlert)
 	check(err)
+	return nil
+}
+
+func (g *GameSpy) Status() GameStatus {
+	return GameStatus{Players: g.StartCalledWith, BlindStructure: g.StartCalledWithBlindStructure}
+}
+
+func (g *GameSpy) Eliminate(player string) error {
+	g.Commands = append(g.Commands, player+" out")
+	return g.CommandError
+}
+
+func (g *GameSpy) Rebuy(player string) error {
+	g.Commands = append(g.Commands, "rebuy "+player)
+	return g.CommandError
+}
+
+func (g *GameSpy) Register(name string) error {
+	g.Commands = append(g.Commands, "register "+name)
+	return g.CommandError
+}
+
+func (g *GameSpy) Seat(players ...string) error {
+	g.Commands = append(g.Commands, "seat "+strings.Join(players, " "))
+	return g.CommandError
+}
+
+func (g *GameSpy) Deal() error {
+	g.Commands = append(g.Commands, "deal")
+	return g.CommandError
+}
+
+func (g *GameSpy) Showdown() ([]HandResult, error) {
+	g.Commands = append(g.Commands, "showdown")
+	return nil, g.CommandError
+}
+
+func (g *GameSpy) Act(action Action) error {
+	g.Commands = append(g.Commands, strings.TrimSpace(fmt.Sprintf("%s %s %d", action.Player, action.Type, action.Amount)))
+	return g.CommandError
 }
 
-func (g *GameSpy) Finish(winner string) {
+func (g *GameSpy) Pause() error {
+	g.Commands = append(g.Commands, "pause")
+	return g.CommandError
+}
+
+func (g *GameSpy) Resume() error {
+	g.Commands = append(g.Commands, "resume")
+	return g.CommandError
+}
+
+func (g *GameSpy) Finish(winner string) error {
 	fmt.Printf("Finished called.. %q\n", winner)
+	if err := g.FinishError; err != nil {
+		g.FinishError = nil
+		return err
+	}
 	g.FinishCalled = true
 	g.FinishCalledWith = winner
+	return nil
 }
 
 func TestCLI(t *testing.T) {
@@ -67,6 +141,32 @@ func TestCLI(t *testing.T) {
 		assertGameFinishCalledWith(t, game, "Maxx")
 	})
 
+	t.Run("start game with 5 players on the turbo blind structure", func(t *testing.T) {
+		game := &GameSpy{}
+		in := userSends("5 turbo", "Manu wins")
+
+		cli := NewCLI(in, dummyStdOut, game)
+		cli.PlayPoker()
+
+		assertGameStartedWith(t, game, 5)
+		if game.StartCalledWithBlindStructure != "turbo" {
+			t.Errorf("got blind structure %q want %q", game.StartCalledWithBlindStructure, "turbo")
+		}
+	})
+
+	t.Run("it prints the error when the game cannot start and does not finish it", func(t *testing.T) {
+		game := &GameSpy{StartError: errors.New("unknown blind structure")}
+
+		stdout := &bytes.Buffer{}
+		in := userSends("5 hyper", "Manu wins")
+
+		cli := NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		assertGameNotFinished(t, game)
+		assertMessagesSentToUser(t, stdout, PlayerPrompt, "unknown blind structure")
+	})
+
 	t.Run("it prints error when a non numeric value is entered and does not start the game", func(t *testing.T) {
 		game := &GameSpy{}
 
@@ -84,7 +184,7 @@ func TestCLI(t *testing.T) {
 		game := &GameSpy{}
 
 		stdout := &bytes.Buffer{}
-		in := userSends("8", "Lloyd is a killer")
+		in := userSends("8", " wins")
 
 		cli := NewCLI(in, stdout, game)
 		cli.PlayPoker()
@@ -93,6 +193,188 @@ func TestCLI(t *testing.T) {
 		assertMessagesSentToUser(t, stdout, PlayerPrompt, BadWinnerInputMessage)
 	})
 
+	t.Run("it runs tournament commands until someone wins", func(t *testing.T) {
+		game := &GameSpy{}
+
+		stdout := &bytes.Buffer{}
+		in := userSends("5", "Alice out", "Bob out", "rebuy Carol", "pause", "resume", "Dave wins", "Erin out")
+
+		cli := NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		assertGameCommands(t, game, "Alice out", "Bob out", "rebuy Carol", "pause", "resume")
+		assertGameFinishCalledWith(t, game, "Dave")
+		assertMessagesSentToUser(t, stdout, PlayerPrompt)
+	})
+
+	t.Run("it prints an error for commands it doesn't understand and carries on", func(t *testing.T) {
+		game := &GameSpy{}
+
+		stdout := &bytes.Buffer{}
+		in := userSends("8", "Lloyd is a killer", "Lloyd out", "Manu wins")
+
+		cli := NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		assertGameCommands(t, game, "Lloyd out")
+		assertGameFinishCalledWith(t, game, "Manu")
+		assertMessagesSentToUser(t, stdout, PlayerPrompt, BadCommandMessage)
+	})
+
+	t.Run("it prints the errors of commands the game refuses", func(t *testing.T) {
+		game := &GameSpy{CommandError: errors.New("Lloyd is already out")}
+
+		stdout := &bytes.Buffer{}
+		in := userSends("8", "Lloyd out")
+
+		cli := NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		assertGameNotFinished(t, game)
+		assertMessagesSentToUser(t, stdout, PlayerPrompt, "Lloyd is already out\n")
+	})
+
+	t.Run("it keeps playing when the winner isn't registered", func(t *testing.T) {
+		err := &PlayerError{"Nobody", ErrPlayerNotFound}
+		game := &GameSpy{FinishError: err}
+
+		stdout := &bytes.Buffer{}
+		in := userSends("5", "Nobody wins", "Manu wins")
+
+		cli := NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		assertGameFinishCalledWith(t, game, "Manu")
+		assertMessagesSentToUser(t, stdout, PlayerPrompt, err.Error()+"\n")
+	})
+
+	t.Run("it shows the time left at the blind level", func(t *testing.T) {
+		fake := clock.NewFake(time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC))
+		game := NewTexasHoldem(dummyBlindAlerter, dummyPlayerStore, WithClock(fake))
+
+		stdout := &bytes.Buffer{}
+		in := userSends("5", "clock", "pause", "clock")
+
+		cli := NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		assertMessagesSentToUser(t, stdout, PlayerPrompt,
+			"Level 1, blinds 100/200, blinds go up to 200 in 10 minutes\n",
+			"Game paused, the blind clock is stopped\n",
+			"Level 1, blinds 100/200, blinds go up to 200 in 10 minutes, the clock is paused\n",
+		)
+	})
+
+	t.Run("it registers players new to the league", func(t *testing.T) {
+		store := NewInMemoryPlayerStore()
+		game := NewTexasHoldem(dummyBlindAlerter, store)
+
+		stdout := &bytes.Buffer{}
+		in := userSends("3", "Pepper out", "register Pepper", "Pepper out", "register Pepper", "Pepper wins")
+
+		cli := NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		assertMessagesSentToUser(t, stdout, PlayerPrompt,
+			(&PlayerError{"Pepper", ErrPlayerNotFound}).Error()+"\n",
+			"Pepper is registered\n",
+			(&PlayerError{"Pepper", ErrPlayerExists}).Error()+"\n",
+		)
+		assertScoreEquals(t, getScore(t, store, "Pepper"), 1)
+	})
+
+	t.Run("it seats the players at the game", func(t *testing.T) {
+		game := &GameSpy{}
+
+		stdout := &bytes.Buffer{}
+		in := userSends("3", "seat Chris Cleo Manu", "Manu wins")
+
+		cli := NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		assertGameCommands(t, game, "seat Chris Cleo Manu")
+		assertMessagesSentToUser(t, stdout, PlayerPrompt, "Seated Chris, Cleo, Manu\n")
+	})
+
+	t.Run("it deals hands and shows them down", func(t *testing.T) {
+		game := NewTexasHoldem(dummyBlindAlerter, newRegisteredPlayerStore(t, "Chris", "Cleo"), WithDeck(NewDeck))
+
+		stdout := &bytes.Buffer{}
+		in := userSends("2", "seat Chris Cleo", "deal", "Chris call", "Cleo check", "deal", "Cleo check", "Chris check", "showdown")
+
+		cli := NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		assertMessagesSentToUser(t, stdout, PlayerPrompt,
+			"Seated Chris, Cleo\n",
+			"Hole cards dealt to Chris, Cleo\n",
+			"Pot 300, Chris to act on the preflop, 100 to call\n",
+			"Pot 400, Cleo to act on the preflop\n",
+			"Pot 400, Cleo to act on the flop\n",
+			"Flop: 7c 8c 9c\n",
+			"Pot 400, Cleo to act on the flop\n",
+			"Pot 400, Chris to act on the flop\n",
+			"Pot 400, Cleo to act on the turn\n",
+			"betting is not over for this hand\n",
+		)
+	})
+
+	t.Run("it takes bets and tells the user about illegal ones", func(t *testing.T) {
+		game := NewTexasHoldem(dummyBlindAlerter, newRegisteredPlayerStore(t, "Chris", "Cleo"), WithDeck(NewDeck))
+
+		stdout := &bytes.Buffer{}
+		in := userSends("2", "seat Chris Cleo", "deal", "Cleo check", "Chris raise 300", "Cleo raise lots", "Cleo fold")
+
+		cli := NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		assertMessagesSentToUser(t, stdout, PlayerPrompt,
+			"Seated Chris, Cleo\n",
+			"Hole cards dealt to Chris, Cleo\n",
+			"Pot 300, Chris to act on the preflop, 100 to call\n",
+			"Cleo can't check, it is not their turn\n",
+			"Chris can't raise, raise is too small, the least is 400\n",
+			BadCommandMessage,
+			"Cleo can't fold, it is not their turn\n",
+		)
+	})
+
+	t.Run("it tells the user when the game can't take bets", func(t *testing.T) {
+		game := struct{ Game }{&GameSpy{}}
+
+		stdout := &bytes.Buffer{}
+		in := userSends("8", "Chris bet 400")
+
+		cli := NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		assertMessagesSentToUser(t, stdout, PlayerPrompt, ErrCannotBet.Error()+"\n")
+	})
+
+	t.Run("it tells the user when the game can't deal", func(t *testing.T) {
+		game := struct{ Game }{&GameSpy{}}
+
+		stdout := &bytes.Buffer{}
+		in := userSends("8", "deal", "showdown")
+
+		cli := NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		assertMessagesSentToUser(t, stdout, PlayerPrompt, ErrCannotDeal.Error()+"\n", ErrCannotDeal.Error()+"\n")
+	})
+
+	t.Run("it tells the user when the game can't be paused", func(t *testing.T) {
+		game := struct{ Game }{&GameSpy{}}
+
+		stdout := &bytes.Buffer{}
+		in := userSends("8", "pause")
+
+		cli := NewCLI(in, stdout, game)
+		cli.PlayPoker()
+
+		assertMessagesSentToUser(t, stdout, PlayerPrompt, ErrCannotPause.Error()+"\n")
+	})
+
 }
 
 func assertGameNotFinished(t testing.TB, game *GameSpy) {
@@ -102,6 +384,13 @@ func assertGameNotFinished(t testing.TB, game *GameSpy) {
 	}
 }
 
+func assertGameCommands(t testing.TB, game *GameSpy, want ...string) {
+	t.Helper()
+	if !reflect.DeepEqual(game.Commands, want) {
+		t.Errorf("got commands %q want %q", game.Commands, want)
+	}
+}
+
 func assertGameNotStarted(t testing.TB, game *GameSpy) {
 	t.Helper()
 	if game.StartCalled {
@@ -1,25 +1,109 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"learn-go-with-tests/clock"
 	poker "learn-go-with-tests/project"
 	"log"
 	"os"
+	"strings"
 )
 
-const dbFileName = "game.db.json"
+var (
+	storeDSN    = flag.String("store", "game.db.json", "player store, a JSON file, sqlite:{path} for a SQLite database or events:{path} for an event log")
+	backups     = flag.Int("backups", 3, "number of previous versions of a JSON player store to keep")
+	blindsDir   = flag.String("blinds", "", "directory of blind structure files (.json, .yaml) to play with rather than the ones built in")
+	seasonsFile = flag.String("seasons", "", "file of the league's seasons (.json, .yaml), the seasons are calendar quarters when not given")
+	audio       = flag.String("audio", "", "WAV file or named pipe to also play blind alerts into, e.g. one read by aplay")
+	eloK        = flag.Float64("elo-k", poker.DefaultElo.K, "K factor of the Elo ratings, how far one game can move a rating")
+)
 
 func main() {
-	store, closeFunc, err := poker.FileSystemPlayerStoreFromFile(dbFileName)
+	flag.Parse()
+
+	store, closeFunc, err := poker.OpenPlayerStore(*storeDSN, poker.WithBackups(*backups))
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer closeFunc()
 
+	store, err = poker.NewRatedPlayerStore(store, poker.Elo{K: *eloK})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var seasons poker.Seasons
+	if *seasonsFile != "" {
+		if seasons, err = poker.LoadSeasons(*seasonsFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	store = poker.NewSeasonalPlayerStore(store, seasons, clock.Real{})
+
+	if flag.Arg(0) == "export" {
+		if err := export(store, flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	structures, err := poker.BuiltInBlindStructures()
+	if *blindsDir != "" {
+		structures, err = poker.LoadBlindStructures(*blindsDir)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	fmt.Println("Let's play poker")
-	fmt.Println("Type `{Name} wins` to record a win")
+	fmt.Printf("Enter the number of players, optionally followed by a blind structure (%s)\n",
+		strings.Join(poker.NewBlindStructures(structures...).Names(), ", "))
+	fmt.Println("Type `register {Name}` for a player new to the league, `seat {Name} {Name}...` for who is playing,")
+	fmt.Println("`{Name} out` when a player is knocked out, `rebuy {Name}` when they buy back in,")
+	fmt.Println("`deal` to deal a hand and then each street, `{Name} check`, `call`, `fold` or `all_in`,")
+	fmt.Println("`{Name} bet {Amount}` or `raise {Amount}` when it's their turn, `showdown` once the betting is over,")
+	fmt.Println("`pause` and `resume` for a break, `clock` for the time left at this level,")
+	fmt.Println("and `{Name} wins` to finish the tournament")
 
-	game := poker.NewTexasHoldem(poker.BlinderAlerterFunc(poker.StdOutAlerter), store)
+	var alerter poker.BlindAlerter = poker.NewAlerter(clock.Real{})
+	if *audio != "" {
+		alerter = poker.NewAudioFileAlerter(clock.Real{}, *audio)
+	}
+
+	game := poker.NewTexasHoldem(alerter, store, poker.WithBlindStructures(structures...))
 	cli := poker.NewCLI(os.Stdin, os.Stdout, game)
 	cli.PlayPoker()
 }
+
+// export writes the league out rather than playing, e.g. `export -format csv -o league.csv`.
+func export(store poker.PlayerStore, args []string) error {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	format := flags.String("format", poker.FormatJSON, "format to export the league in, json, csv, html or markdown")
+	out := flags.String("o", "", "file to write the league to, standard output when not given")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if _, ok := poker.LeagueFormats[*format]; !ok {
+		return fmt.Errorf("%v, got %q", poker.ErrUnknownFormat, *format)
+	}
+
+	league, err := store.GetLeague()
+	if err != nil {
+		return fmt.Errorf("problem reading the league, %v", err)
+	}
+
+	if *out == "" {
+		return poker.WriteLeague(os.Stdout, league, *format)
+	}
+	file, err := os.Create(*out)
+	if err != nil {
+		return fmt.Errorf("problem creating %s, %v", *out, err)
+	}
+	if err := poker.WriteLeague(file, league, *format); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("problem exporting the league to %s, %v", *out, err)
+	}
+	return file.Close()
+}
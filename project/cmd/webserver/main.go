@@ -1,23 +1,57 @@
 package main
 
 import (
+	"flag"
+	"learn-go-with-tests/clock"
 	poker "learn-go-with-tests/project"
 	"log"
 	"net/http"
 )
 
-const dbFileName = "game.db.json"
+var (
+	storeDSN    = flag.String("store", "game.db.json", "player store, a JSON file, sqlite:{path} for a SQLite database or events:{path} for an event log")
+	backups     = flag.Int("backups", 3, "number of previous versions of a JSON player store to keep")
+	blindsDir   = flag.String("blinds", "", "directory of blind structure files (.json, .yaml) to play with rather than the ones built in")
+	seasonsFile = flag.String("seasons", "", "file of the league's seasons (.json, .yaml), the seasons are calendar quarters when not given")
+	eloK        = flag.Float64("elo-k", poker.DefaultElo.K, "K factor of the Elo ratings, how far one game can move a rating")
+)
 
 func main() {
-	store, closeFunc, err := poker.FileSystemPlayerStoreFromFile(dbFileName)
+	flag.Parse()
+
+	store, closeFunc, err := poker.OpenPlayerStore(*storeDSN, poker.WithBackups(*backups))
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer closeFunc()
 
-	game := poker.NewTexasHoldem(poker.BlinderAlerterFunc(poker.Alerter), store)
+	store, err = poker.NewRatedPlayerStore(store, poker.Elo{K: *eloK})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var seasons poker.Seasons
+	if *seasonsFile != "" {
+		if seasons, err = poker.LoadSeasons(*seasonsFile); err != nil {
+			log.Fatal(err)
+		}
+	}
+	store = poker.NewSeasonalPlayerStore(store, seasons, clock.Real{})
+
+	structures, err := poker.BuiltInBlindStructures()
+	if *blindsDir != "" {
+		structures, err = poker.LoadBlindStructures(*blindsDir)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	newGame := func(id string) poker.Game {
+		return poker.NewTexasHoldem(poker.NewAlerter(clock.Real{}), store,
+			poker.WithBlindStructures(structures...), poker.WithGameID(id))
+	}
 
-	server, err := poker.NewPlayerServer(store, game)
+	server, err := poker.NewPlayerServer(store, newGame)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -0,0 +1,61 @@
+package poker
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+)
+
+// ErrNotEnoughCards is returned when more cards are dealt than are left in a deck.
+var ErrNotEnoughCards = errors.New("not enough cards left in the deck")
+
+// Deck is a deck of playing cards, dealt from the top.
+type Deck struct {
+	cards []Card
+}
+
+// NewDeck returns the 52 cards of a deck in order, by suit then rank.
+func NewDeck() *Deck {
+	cards := make([]Card, 0, 52)
+	for suit := Clubs; suit <= Spades; suit++ {
+		for rank := Two; rank <= Ace; rank++ {
+			cards = append(cards, Card{rank, suit})
+		}
+	}
+	return &Deck{cards}
+}
+
+// NewShuffledDeck returns a deck shuffled by a random source seeded with seed,
+// so the same seed always gives the same deal.
+func NewShuffledDeck(seed int64) *Deck {
+	deck := NewDeck()
+	deck.Shuffle(rand.New(rand.NewSource(seed)))
+	return deck
+}
+
+// NewRandomDeck returns a deck shuffled with a seed taken from the clock.
+func NewRandomDeck() *Deck {
+	return NewShuffledDeck(time.Now().UnixNano())
+}
+
+// Shuffle puts the cards left in the deck in a random order taken from rnd.
+func (d *Deck) Shuffle(rnd *rand.Rand) {
+	rnd.Shuffle(len(d.cards), func(i, j int) {
+		d.cards[i], d.cards[j] = d.cards[j], d.cards[i]
+	})
+}
+
+// Deal takes n cards from the top of the deck.
+func (d *Deck) Deal(n int) ([]Card, error) {
+	if n > len(d.cards) {
+		return nil, ErrNotEnoughCards
+	}
+	cards := append([]Card{}, d.cards[:n]...)
+	d.cards = d.cards[n:]
+	return cards, nil
+}
+
+// Remaining is how many cards are left to deal.
+func (d *Deck) Remaining() int {
+	return len(d.cards)
+}
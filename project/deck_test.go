@@ -0,0 +1,105 @@
+package poker
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseCard(t *testing.T) {
+	cases := []struct {
+		in   string
+		want Card
+	}{
+		{"As", Card{Ace, Spades}},
+		{"2c", Card{Two, Clubs}},
+		{"Td", Card{Ten, Diamonds}},
+		{"10d", Card{Ten, Diamonds}},
+		{"kH", Card{King, Hearts}},
+		{"9h", Card{Nine, Hearts}},
+	}
+
+	for _, c := range cases {
+		t.Run(c.in, func(t *testing.T) {
+			got, err := ParseCard(c.in)
+			assertNoError(t, err)
+			if got != c.want {
+				t.Errorf("got %v want %v", got, c.want)
+			}
+		})
+	}
+
+	for _, bad := range []string{"", "A", "1s", "Ax", "Asd", "Zs"} {
+		t.Run("rejects "+bad, func(t *testing.T) {
+			if _, err := ParseCard(bad); err == nil {
+				t.Errorf("expected an error parsing %q", bad)
+			}
+		})
+	}
+
+	t.Run("every card reads back from its string and its JSON", func(t *testing.T) {
+		for _, card := range NewDeck().cards {
+			got, err := ParseCard(card.String())
+			assertNoError(t, err)
+			if got != card {
+				t.Errorf("got %v from %q want %v", got, card.String(), card)
+			}
+
+			data, err := json.Marshal(card)
+			assertNoError(t, err)
+			var decoded Card
+			assertNoError(t, json.Unmarshal(data, &decoded))
+			if decoded != card {
+				t.Errorf("got %v from %s want %v", decoded, data, card)
+			}
+		}
+	})
+}
+
+func TestDeck(t *testing.T) {
+	t.Run("has the 52 different cards", func(t *testing.T) {
+		deck := NewDeck()
+		if deck.Remaining() != 52 {
+			t.Fatalf("got %d cards want 52", deck.Remaining())
+		}
+
+		seen := map[Card]bool{}
+		for _, c := range deck.cards {
+			seen[c] = true
+		}
+		if len(seen) != 52 {
+			t.Errorf("got %d different cards want 52", len(seen))
+		}
+	})
+
+	t.Run("the same seed shuffles the same way", func(t *testing.T) {
+		first, second, other := NewShuffledDeck(42), NewShuffledDeck(42), NewShuffledDeck(7)
+
+		if !reflect.DeepEqual(first.cards, second.cards) {
+			t.Errorf("decks shuffled with the same seed differ")
+		}
+		if reflect.DeepEqual(first.cards, other.cards) {
+			t.Errorf("decks shuffled with different seeds are the same")
+		}
+		if reflect.DeepEqual(first.cards, NewDeck().cards) {
+			t.Errorf("shuffled deck is still in order")
+		}
+	})
+
+	t.Run("deals from the top until it runs out", func(t *testing.T) {
+		deck := NewDeck()
+
+		cards, err := deck.Deal(2)
+		assertNoError(t, err)
+		want := []Card{{Two, Clubs}, {Three, Clubs}}
+		if !reflect.DeepEqual(cards, want) {
+			t.Errorf("got %v want %v", cards, want)
+		}
+
+		_, err = deck.Deal(50)
+		assertNoError(t, err)
+		if _, err := deck.Deal(1); err != ErrNotEnoughCards {
+			t.Errorf("got error %v want %v", err, ErrNotEnoughCards)
+		}
+	})
+}
@@ -0,0 +1,379 @@
+package poker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Types of event kept in an event log.
+const (
+	EventWinRecorded      = "WinRecorded"
+	EventGameRecorded     = "GameRecorded"
+	EventChipsRecorded    = "ChipsRecorded"
+	EventPlayerRegistered = "PlayerRegistered"
+	EventPlayerUpdated    = "PlayerUpdated"
+	EventPlayerDeleted    = "PlayerDeleted"
+)
+
+// DefaultSnapshotEvery is how many events an event log holds before it is compacted.
+const DefaultSnapshotEvery = 1000
+
+// Event is a change to the league, kept as one line of an event log.
+type Event struct {
+	Seq     int              `json:"seq"`
+	Type    string           `json:"type"`
+	At      time.Time        `json:"at"`
+	Name    string           `json:"name,omitempty"`
+	Game    *GameRecord      `json:"game,omitempty"`
+	Chips   *ChipTransaction `json:"chips,omitempty"`
+	Profile *Profile         `json:"profile,omitempty"`
+}
+
+// eventSnapshot is the state of the league after every event up to Seq.
+type eventSnapshot struct {
+	Seq int `json:"seq"`
+	fileSystemDatabase
+}
+
+// EventLogPlayerStore appends every change to a JSON-lines log and rebuilds
+// the league by replaying it. Every snapshotEvery events the league is
+// written to a snapshot, and the log moved aside to {path}.{seq} so the full
+// history stays available for auditing.
+type EventLogPlayerStore struct {
+	mu            sync.RWMutex
+	path          string
+	log           *os.File
+	snapshot      *tape
+	snapshotEvery int
+	seq           int
+	sinceSnapshot int
+	league        League
+	games         []GameRecord
+	chips         []ChipTransaction
+}
+
+// EventLogPlayerStoreFromFile opens the event log at path, and its snapshot at
+// {path}.snapshot, creating them if needed.
+func EventLogPlayerStoreFromFile(path string, snapshotEvery int) (*EventLogPlayerStore, func(), error) {
+	if snapshotEvery <= 0 {
+		snapshotEvery = DefaultSnapshotEvery
+	}
+
+	store := &EventLogPlayerStore{
+		path:          path,
+		snapshot:      &tape{path: path + ".snapshot"},
+		snapshotEvery: snapshotEvery,
+		league:        League{},
+	}
+
+	if err := store.loadSnapshot(); err != nil {
+		return nil, nil, fmt.Errorf("problem loading snapshot of %s, %v", path, err)
+	}
+	endsMidLine, err := store.replay()
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem replaying %s, %v", path, err)
+	}
+
+	logFile, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem opening %s %v", path, err)
+	}
+	store.log = logFile
+
+	if endsMidLine {
+		if _, err := logFile.Write([]byte("\n")); err != nil {
+			_ = logFile.Close()
+			return nil, nil, fmt.Errorf("problem ending the last event in %s, %v", path, err)
+		}
+	}
+
+	closeFunc := func() {
+		store.mu.Lock()
+		defer store.mu.Unlock()
+		check(store.log.Close())
+	}
+	return store, closeFunc, nil
+}
+
+func (e *EventLogPlayerStore) loadSnapshot() error {
+	data, err := ioutil.ReadFile(e.snapshot.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	var snapshot eventSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	e.seq = snapshot.Seq
+	if snapshot.League != nil {
+		e.league = snapshot.League
+	}
+	e.games = snapshot.Games
+	e.chips = snapshot.Chips
+	return nil
+}
+
+// replay applies the events in the log that are newer than the snapshot.
+// A partly written last line, left by a crash, is dropped, and endsMidLine
+// reports a complete last event that is missing its newline.
+func (e *EventLogPlayerStore) replay() (endsMidLine bool, err error) {
+	data, err := ioutil.ReadFile(e.path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	valid := 0
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		var event Event
+		if err := json.Unmarshal(line, &event); err != nil {
+			if valid+len(line)+1 < len(data) {
+				return false, fmt.Errorf("corrupt event at byte %d, %v", valid, err)
+			}
+			log.Printf("dropping partly written event at the end of %s\n", e.path)
+			return false, os.Truncate(e.path, int64(valid))
+		}
+		valid += len(line) + 1
+
+		if event.Seq <= e.seq {
+			continue
+		}
+		e.apply(event)
+		e.sinceSnapshot++
+	}
+	return len(data) > 0 && data[len(data)-1] != '\n', scanner.Err()
+}
+
+// apply changes the league by event. Events are checked before they are
+// logged, but logs from before players had to register have wins for
+// players who never did, so they are added to the league.
+func (e *EventLogPlayerStore) apply(event Event) {
+	e.seq = event.Seq
+	var profile Profile
+	if event.Profile != nil {
+		profile = *event.Profile
+	}
+
+	switch event.Type {
+	case EventWinRecorded:
+		if err := e.league.addWin(event.Name); err != nil {
+			e.league = append(e.league, Player{Name: event.Name, Wins: 1})
+		}
+	case EventPlayerRegistered:
+		e.league = append(e.league, Player{Name: event.Name, Profile: profile})
+	case EventPlayerUpdated:
+		if err := e.league.update(event.Name, profile); err != nil {
+			log.Printf("skipping event %d, %v\n", event.Seq, err)
+		}
+	case EventPlayerDeleted:
+		league, err := e.league.remove(event.Name)
+		if err != nil {
+			log.Printf("skipping event %d, %v\n", event.Seq, err)
+		}
+		e.league = league
+	case EventGameRecorded:
+		e.games = append(e.games, *event.Game)
+	case EventChipsRecorded:
+		e.chips = append(e.chips, *event.Chips)
+		if err := e.league.addChips(*event.Chips); err != nil {
+			e.league = append(e.league, Player{Name: event.Chips.Player, Chips: event.Chips.Net()})
+		}
+	default:
+		log.Printf("skipping unknown event %q\n", event.Type)
+	}
+}
+
+// append writes event to the log and applies it, compacting the log when it
+// is due. The event isn't applied unless it is safely in the log, and a
+// compaction that fails is tried again on the next event.
+func (e *EventLogPlayerStore) append(event Event) error {
+	event.Seq = e.seq + 1
+	event.At = time.Now().UTC()
+
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("problem encoding event, %v", err)
+	}
+	info, err := e.log.Stat()
+	if err != nil {
+		return fmt.Errorf("problem getting file info from file %s, %v", e.path, err)
+	}
+	if _, err := e.log.Write(append(line, '\n')); err != nil {
+		// drop what was written so the next event doesn't follow half of this one.
+		_ = e.log.Truncate(info.Size())
+		return fmt.Errorf("problem appending to %s, %v", e.path, err)
+	}
+	if err := e.log.Sync(); err != nil {
+		return fmt.Errorf("problem syncing %s, %v", e.path, err)
+	}
+
+	e.apply(event)
+	e.sinceSnapshot++
+
+	if e.sinceSnapshot >= e.snapshotEvery {
+		if err := e.compact(); err != nil {
+			log.Printf("problem compacting %s, %v\n", e.path, err)
+		}
+	}
+	return nil
+}
+
+// compact writes a snapshot of every event so far and starts a new log.
+func (e *EventLogPlayerStore) compact() error {
+	data, err := json.Marshal(eventSnapshot{e.seq, fileSystemDatabase{e.league, e.games, e.chips}})
+	if err != nil {
+		return err
+	}
+	if _, err := e.snapshot.Write(data); err != nil {
+		return err
+	}
+
+	archive := fmt.Sprintf("%s.%d", e.path, e.seq)
+	if err := os.Rename(e.path, archive); err != nil {
+		return err
+	}
+	logFile, err := os.OpenFile(e.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return err
+	}
+	if err := e.log.Close(); err != nil {
+		log.Printf("problem closing compacted log %v\n", err)
+	}
+	e.log = logFile
+	e.sinceSnapshot = 0
+	return nil
+}
+
+// GetLeague ..
+func (e *EventLogPlayerStore) GetLeague() (League, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	league := append(League{}, e.league...)
+	league.Sort()
+	return league, nil
+}
+
+// GetPlayerScore ..
+func (e *EventLogPlayerStore) GetPlayerScore(name string) (int, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	if player := e.league.Find(name); player != nil {
+		return player.Wins, nil
+	}
+	return 0, nil
+}
+
+// RecordWin ..
+func (e *EventLogPlayerStore) RecordWin(name string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.league.Find(name) == nil {
+		return &PlayerError{name, ErrPlayerNotFound}
+	}
+	return e.append(Event{Type: EventWinRecorded, Name: name})
+}
+
+// RegisterPlayer ..
+func (e *EventLogPlayerStore) RegisterPlayer(name string, profile Profile) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if err := ValidatePlayerName(name); err != nil {
+		return err
+	}
+	if e.league.Find(name) != nil {
+		return &PlayerError{name, ErrPlayerExists}
+	}
+	return e.append(Event{Type: EventPlayerRegistered, Name: name, Profile: &profile})
+}
+
+// UpdatePlayer ..
+func (e *EventLogPlayerStore) UpdatePlayer(name string, profile Profile) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.league.Find(name) == nil {
+		return &PlayerError{name, ErrPlayerNotFound}
+	}
+	return e.append(Event{Type: EventPlayerUpdated, Name: name, Profile: &profile})
+}
+
+// DeletePlayer ..
+func (e *EventLogPlayerStore) DeletePlayer(name string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.league.Find(name) == nil {
+		return &PlayerError{name, ErrPlayerNotFound}
+	}
+	return e.append(Event{Type: EventPlayerDeleted, Name: name})
+}
+
+// GetPlayer ..
+func (e *EventLogPlayerStore) GetPlayer(name string) (Player, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.league.get(name)
+}
+
+// RecordGame ..
+func (e *EventLogPlayerStore) RecordGame(game GameRecord) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.append(Event{Type: EventGameRecorded, Game: &game})
+}
+
+// GetGames ..
+func (e *EventLogPlayerStore) GetGames() ([]GameRecord, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return append([]GameRecord{}, e.games...), nil
+}
+
+// GetPlayerGames ..
+func (e *EventLogPlayerStore) GetPlayerGames(name string) ([]GameRecord, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return playerGames(e.games, name), nil
+}
+
+// RecordChips ..
+func (e *EventLogPlayerStore) RecordChips(transaction ChipTransaction) error {
+	if err := transaction.Validate(); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.league.Find(transaction.Player) == nil {
+		return &PlayerError{transaction.Player, ErrPlayerNotFound}
+	}
+	return e.append(Event{Type: EventChipsRecorded, Chips: &transaction})
+}
+
+// GetChipTransactions ..
+func (e *EventLogPlayerStore) GetChipTransactions(name string) ([]ChipTransaction, error) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return playerChipTransactions(e.chips, name), nil
+}
@@ -0,0 +1,177 @@
+package poker
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEventLogPlayerStore(t *testing.T) {
+	t.Run("appends an event for every registration and win", func(t *testing.T) {
+		path := filepath.Join(createTempDir(t), "game.events")
+		store := mustOpenEventLogPlayerStore(t, path, 100)
+
+		assertNoError(t, store.RegisterPlayer("Chris", Profile{Nickname: "CJ"}))
+		assertNoError(t, store.RecordWin("Chris"))
+		assertNoError(t, store.RegisterPlayer("Cleo", Profile{}))
+		assertNoError(t, store.RecordWin("Cleo"))
+
+		events := readEvents(t, path)
+		want := []struct {
+			Type string
+			Name string
+		}{
+			{EventPlayerRegistered, "Chris"},
+			{EventWinRecorded, "Chris"},
+			{EventPlayerRegistered, "Cleo"},
+			{EventWinRecorded, "Cleo"},
+		}
+		if len(events) != len(want) {
+			t.Fatalf("got %d events want %d", len(events), len(want))
+		}
+		for i, w := range want {
+			if events[i].Seq != i+1 || events[i].Type != w.Type || events[i].Name != w.Name {
+				t.Errorf("got event %+v, want event %d to be %s for %s", events[i], i+1, w.Type, w.Name)
+			}
+		}
+		if events[0].Profile == nil || events[0].Profile.Nickname != "CJ" {
+			t.Errorf("got profile %+v in %+v want nickname CJ", events[0].Profile, events[0])
+		}
+	})
+
+	t.Run("doesn't append wins for players who aren't registered", func(t *testing.T) {
+		path := filepath.Join(createTempDir(t), "game.events")
+		store := mustOpenEventLogPlayerStore(t, path, 100)
+
+		if err := store.RecordWin("Chris"); !errors.Is(err, ErrPlayerNotFound) {
+			t.Errorf("got error %v want %v", err, ErrPlayerNotFound)
+		}
+		if events := readEvents(t, path); len(events) != 0 {
+			t.Errorf("got events %+v want none", events)
+		}
+	})
+
+	t.Run("replays wins recorded before players were registered", func(t *testing.T) {
+		path := filepath.Join(createTempDir(t), "game.events")
+		assertNoError(t, ioutil.WriteFile(path, []byte(`{"seq":1,"type":"WinRecorded","name":"Chris"}`+"\n"), 0666))
+
+		store := mustOpenEventLogPlayerStore(t, path, 100)
+		assertNoError(t, store.RecordWin("Chris"))
+
+		assertLeague(t, getLeague(t, store), []Player{{Name: "Chris", Wins: 2}})
+	})
+
+	t.Run("compacts into a snapshot and keeps the old log for auditing", func(t *testing.T) {
+		path := filepath.Join(createTempDir(t), "game.events")
+		store := mustOpenEventLogPlayerStore(t, path, 2)
+
+		assertNoError(t, store.RegisterPlayer("Chris", Profile{}))
+		assertNoError(t, store.RegisterPlayer("Cleo", Profile{}))
+		assertNoError(t, store.RecordWin("Chris"))
+		assertNoError(t, store.RecordWin("Chris"))
+		assertNoError(t, store.RecordWin("Cleo"))
+
+		if got := len(readEvents(t, path+".4")); got != 2 {
+			t.Errorf("got %d events in the archived log want 2", got)
+		}
+		if got := len(readEvents(t, path)); got != 1 {
+			t.Errorf("got %d events in the log want 1", got)
+		}
+
+		reopened := mustOpenEventLogPlayerStore(t, path, 2)
+		assertLeague(t, getLeague(t, reopened), []Player{{Name: "Chris", Wins: 2}, {Name: "Cleo", Wins: 1}})
+	})
+
+	t.Run("drops a partly written last event", func(t *testing.T) {
+		path := filepath.Join(createTempDir(t), "game.events")
+		store := mustOpenEventLogPlayerStore(t, path, 100)
+		assertNoError(t, store.RegisterPlayer("Chris", Profile{}))
+		assertNoError(t, store.RecordWin("Chris"))
+
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0666)
+		assertNoError(t, err)
+		_, _ = f.Write([]byte(`{"seq":3,"type":"WinReco`))
+		_ = f.Close()
+
+		reopened := mustOpenEventLogPlayerStore(t, path, 100)
+		assertScoreEquals(t, getScore(t, reopened, "Chris"), 1)
+
+		assertNoError(t, reopened.RecordWin("Chris"))
+		again := mustOpenEventLogPlayerStore(t, path, 100)
+		assertScoreEquals(t, getScore(t, again, "Chris"), 2)
+	})
+
+	t.Run("returns an error and doesn't apply events it can't append", func(t *testing.T) {
+		path := filepath.Join(createTempDir(t), "game.events")
+		store := mustOpenEventLogPlayerStore(t, path, 100)
+		assertNoError(t, store.RegisterPlayer("Chris", Profile{}))
+
+		readOnly, err := os.Open(path)
+		assertNoError(t, err)
+		writable := store.log
+		store.log = readOnly
+
+		if err := store.RecordWin("Chris"); err == nil {
+			t.Error("expected an error appending to a read only log")
+		}
+		assertScoreEquals(t, getScore(t, store, "Chris"), 0)
+
+		store.log = writable
+		assertNoError(t, readOnly.Close())
+		assertNoError(t, store.RecordWin("Chris"))
+		again := mustOpenEventLogPlayerStore(t, path, 100)
+		assertScoreEquals(t, getScore(t, again, "Chris"), 1)
+	})
+
+	t.Run("refuses to start with a corrupt event in the middle of the log", func(t *testing.T) {
+		path := filepath.Join(createTempDir(t), "game.events")
+		assertNoError(t, ioutil.WriteFile(path, []byte("{not json}\n"+`{"seq":2,"type":"WinRecorded","name":"Chris"}`+"\n"), 0666))
+
+		if _, _, err := EventLogPlayerStoreFromFile(path, 100); err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	})
+}
+
+func createTempDir(t testing.TB) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "events")
+	if err != nil {
+		t.Fatalf("could not create temp dir %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	return dir
+}
+
+func mustOpenEventLogPlayerStore(t testing.TB, path string, snapshotEvery int) *EventLogPlayerStore {
+	t.Helper()
+
+	store, closeFunc, err := EventLogPlayerStoreFromFile(path, snapshotEvery)
+	assertNoError(t, err)
+	t.Cleanup(closeFunc)
+	return store
+}
+
+func readEvents(t testing.TB, path string) []Event {
+	t.Helper()
+
+	f, err := os.Open(path)
+	assertNoError(t, err)
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event Event
+		assertNoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	return events
+}
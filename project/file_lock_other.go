@@ -0,0 +1,24 @@
+//go:build !unix
+
+package poker
+
+import "os"
+
+// fileLock does nothing where flock is not available, the store is then
+// only safe to share within a single process.
+type fileLock struct {
+	file *os.File
+}
+
+func openFileLock(path string) (*fileLock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, err
+	}
+	return &fileLock{file}, nil
+}
+
+func (l *fileLock) Lock() error   { return nil }
+func (l *fileLock) RLock() error  { return nil }
+func (l *fileLock) Unlock() error { return nil }
+func (l *fileLock) Close() error  { return l.file.Close() }
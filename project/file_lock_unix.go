@@ -0,0 +1,48 @@
+//go:build unix
+
+package poker
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileLock is an advisory lock shared between processes, held on a lock file.
+type fileLock struct {
+	file *os.File
+}
+
+func openFileLock(path string) (*fileLock, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, err
+	}
+	return &fileLock{file}, nil
+}
+
+// Lock blocks until the lock is held exclusively.
+func (l *fileLock) Lock() error {
+	return l.flock(syscall.LOCK_EX)
+}
+
+// RLock blocks until the lock is held shared with other readers.
+func (l *fileLock) RLock() error {
+	return l.flock(syscall.LOCK_SH)
+}
+
+func (l *fileLock) Unlock() error {
+	return l.flock(syscall.LOCK_UN)
+}
+
+func (l *fileLock) Close() error {
+	return l.file.Close()
+}
+
+func (l *fileLock) flock(how int) error {
+	for {
+		err := syscall.Flock(int(l.file.Fd()), how)
+		if err != syscall.EINTR {
+			return err
+		}
+	}
+}
@@ -1,17 +1,55 @@
 package poker
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"log"
 	"os"
-	"sort"
+	"path/filepath"
+	"sync"
+	"time"
 )
 
 // FileSystemPlayerStore ..
+// Every call holds a lock on {path}.lock and reloads the database first if
+// another process has changed it, so several processes can share one file.
 type FileSystemPlayerStore struct {
-	database io.Writer
+	mu       sync.Mutex
+	database *tape
+	lock     *fileLock
+	loaded   os.FileInfo
 	league   League
+	games    []GameRecord
+	chips    []ChipTransaction
+}
+
+// fileSystemDatabase is the layout of the database file. Older files hold only
+// the league, as a JSON array, and are read as a database without games or chips.
+type fileSystemDatabase struct {
+	League League            `json:"league"`
+	Games  []GameRecord      `json:"games"`
+	Chips  []ChipTransaction `json:"chips,omitempty"`
+}
+
+func loadFileSystemDatabase(rdr io.Reader) (fileSystemDatabase, error) {
+	data, err := ioutil.ReadAll(rdr)
+	if err != nil {
+		return fileSystemDatabase{}, err
+	}
+
+	var db fileSystemDatabase
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		db.League, err = NewLeague(bytes.NewReader(data))
+		return db, err
+	}
+
+	if err := json.Unmarshal(data, &db); err != nil {
+		return fileSystemDatabase{}, fmt.Errorf("Problem parsing database, %v", err)
+	}
+	return db, nil
 }
 
 func initialisePlayerDBFile(file *os.File) error {
@@ -37,69 +75,328 @@ func initialisePlayerDBFile(file *os.File) error {
 	return nil
 }
 
-// NewFileSystemPlayerStore ..
-func NewFileSystemPlayerStore(file *os.File) (*FileSystemPlayerStore, error) {
-	err := initialisePlayerDBFile(file)
+// FileSystemOption configures a FileSystemPlayerStore.
+type FileSystemOption func(*tape)
 
+// WithBackups keeps the previous n versions of the database file alongside it.
+func WithBackups(n int) FileSystemOption {
+	return func(t *tape) {
+		t.backups = n
+	}
+}
+
+// NewFileSystemPlayerStore loads the store from file. A corrupt database file
+// is moved aside and the store recovered from the newest readable backup,
+// or started empty when there is none.
+func NewFileSystemPlayerStore(file *os.File, options ...FileSystemOption) (*FileSystemPlayerStore, error) {
+	lock, err := openFileLock(file.Name() + ".lock")
 	if err != nil {
+		return nil, fmt.Errorf("problem opening lock for %s, %v", file.Name(), err)
+	}
+	if err := lock.Lock(); err != nil {
+		_ = lock.Close()
+		return nil, fmt.Errorf("problem locking %s, %v", file.Name(), err)
+	}
+	defer func() {
+		_ = lock.Unlock()
+	}()
+
+	err = initialisePlayerDBFile(file)
+
+	if err != nil {
+		_ = lock.Close()
 		return nil, fmt.Errorf("problem initialising player db file, %v", err)
 	}
 
-	league, err := NewLeague(file)
+	database := &tape{path: file.Name()}
+	for _, option := range options {
+		option(database)
+	}
+	removeTempFiles(file.Name())
+
+	loaded, err := file.Stat()
+	if err != nil {
+		_ = lock.Close()
+		return nil, fmt.Errorf("problem getting file info from file %s, %v", file.Name(), err)
+	}
+
+	db, err := loadFileSystemDatabase(file)
 	if err != nil {
-		return nil, fmt.Errorf("problem loading player store from file %s, %v", file.Name(), err)
+		db, err = recoverFileSystemDatabase(file.Name(), err)
+		if err != nil {
+			_ = lock.Close()
+			return nil, fmt.Errorf("problem loading player store from file %s, %v", file.Name(), err)
+		}
+		// the recovered database has replaced file, it is reloaded on first use.
+		loaded = nil
 	}
 
 	return &FileSystemPlayerStore{
-		database: &tape{file},
-		league:   league,
+		database: database,
+		lock:     lock,
+		loaded:   loaded,
+		league:   db.League,
+		games:    db.Games,
+		chips:    db.Chips,
 	}, nil
 }
 
+// recoverFileSystemDatabase moves the corrupt database at path aside
+// and loads the newest backup that can be read.
+func recoverFileSystemDatabase(path string, cause error) (fileSystemDatabase, error) {
+	corruptPath := fmt.Sprintf("%s.corrupt-%s", path, time.Now().Format("20060102T150405"))
+	if err := os.Rename(path, corruptPath); err != nil {
+		return fileSystemDatabase{}, fmt.Errorf("%v, and could not move it aside, %v", cause, err)
+	}
+	log.Printf("database %s is corrupt, moved it to %s, %v\n", path, corruptPath, cause)
+
+	db := fileSystemDatabase{League: League{}}
+	for i := 1; ; i++ {
+		backup, err := os.Open(backupPath(path, i))
+		if err != nil {
+			log.Printf("no readable backup of %s, starting with an empty database\n", path)
+			break
+		}
+		recovered, err := loadFileSystemDatabase(backup)
+		_ = backup.Close()
+		if err == nil {
+			log.Printf("recovered database %s from %s\n", path, backup.Name())
+			db = recovered
+			break
+		}
+	}
+
+	data, err := json.Marshal(db)
+	if err != nil {
+		return fileSystemDatabase{}, err
+	}
+	if _, err := (&tape{path: path}).Write(data); err != nil {
+		return fileSystemDatabase{}, fmt.Errorf("problem writing recovered database, %v", err)
+	}
+	return db, nil
+}
+
+// removeTempFiles cleans up after writes that were interrupted by a crash.
+func removeTempFiles(path string) {
+	leftovers, _ := filepath.Glob(path + ".tmp-*")
+	for _, leftover := range leftovers {
+		_ = os.Remove(leftover)
+	}
+}
+
 // GetLeague ..
-func (f *FileSystemPlayerStore) GetLeague() League {
-	sort.Slice(f.league, func(i, j int) bool {
-		return f.league[i].Wins > f.league[j].Wins
-	})
-	return f.league
+func (f *FileSystemPlayerStore) GetLeague() (League, error) {
+	var league League
+	if err := f.read(func() {
+		league = append(League{}, f.league...)
+	}); err != nil {
+		return nil, err
+	}
+	league.Sort()
+	return league, nil
 }
 
 // GetPlayerScore ..
-func (f *FileSystemPlayerStore) GetPlayerScore(name string) int {
-	player := f.league.Find(name)
+func (f *FileSystemPlayerStore) GetPlayerScore(name string) (int, error) {
+	wins := 0
+	err := f.read(func() {
+		if player := f.league.Find(name); player != nil {
+			wins = player.Wins
+		}
+	})
+	return wins, err
+}
+
+// RecordWin ..
+func (f *FileSystemPlayerStore) RecordWin(name string) error {
+	return f.write(func() error {
+		return f.league.addWin(name)
+	})
+}
+
+// RegisterPlayer ..
+func (f *FileSystemPlayerStore) RegisterPlayer(name string, profile Profile) error {
+	return f.write(func() (err error) {
+		f.league, err = f.league.register(name, profile)
+		return err
+	})
+}
+
+// UpdatePlayer ..
+func (f *FileSystemPlayerStore) UpdatePlayer(name string, profile Profile) error {
+	return f.write(func() error {
+		return f.league.update(name, profile)
+	})
+}
+
+// DeletePlayer ..
+func (f *FileSystemPlayerStore) DeletePlayer(name string) error {
+	return f.write(func() (err error) {
+		f.league, err = f.league.remove(name)
+		return err
+	})
+}
 
-	if player != nil {
-		return player.Wins
+// GetPlayer ..
+func (f *FileSystemPlayerStore) GetPlayer(name string) (player Player, err error) {
+	if readErr := f.read(func() {
+		player, err = f.league.get(name)
+	}); readErr != nil {
+		return Player{}, readErr
 	}
+	return player, err
+}
 
-	return 0
+// RecordGame ..
+func (f *FileSystemPlayerStore) RecordGame(game GameRecord) error {
+	return f.write(func() error {
+		f.games = append(f.games, game)
+		return nil
+	})
 }
 
-// RecordWin ..
-func (f *FileSystemPlayerStore) RecordWin(name string) {
-	player := f.league.Find(name)
+// GetGames ..
+func (f *FileSystemPlayerStore) GetGames() ([]GameRecord, error) {
+	var games []GameRecord
+	err := f.read(func() {
+		games = append([]GameRecord{}, f.games...)
+	})
+	return games, err
+}
+
+// GetPlayerGames ..
+func (f *FileSystemPlayerStore) GetPlayerGames(name string) ([]GameRecord, error) {
+	var games []GameRecord
+	err := f.read(func() {
+		games = playerGames(f.games, name)
+	})
+	return games, err
+}
+
+// RecordChips ..
+func (f *FileSystemPlayerStore) RecordChips(transaction ChipTransaction) error {
+	if err := transaction.Validate(); err != nil {
+		return err
+	}
+	return f.write(func() error {
+		if err := f.league.addChips(transaction); err != nil {
+			return err
+		}
+		f.chips = append(f.chips, transaction)
+		return nil
+	})
+}
+
+// GetChipTransactions ..
+func (f *FileSystemPlayerStore) GetChipTransactions(name string) ([]ChipTransaction, error) {
+	var transactions []ChipTransaction
+	err := f.read(func() {
+		transactions = playerChipTransactions(f.chips, name)
+	})
+	return transactions, err
+}
+
+// read runs fn with the database up to date, holding a shared lock on it.
+func (f *FileSystemPlayerStore) read(fn func()) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.lock.RLock(); err != nil {
+		return fmt.Errorf("problem locking %s, %v", f.database.path, err)
+	}
+	defer f.unlock()
+
+	if err := f.reloadIfChanged(); err != nil {
+		return err
+	}
+	fn()
+	return nil
+}
+
+// write runs fn with the database up to date and saves its changes, holding
+// an exclusive lock on it so no other process writes in between. Nothing is
+// saved if fn fails, or if the database can't be brought up to date, so what
+// another process wrote is never overwritten.
+func (f *FileSystemPlayerStore) write(fn func() error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if err := f.lock.Lock(); err != nil {
+		return fmt.Errorf("problem locking %s, %v", f.database.path, err)
+	}
+	defer f.unlock()
 
-	if player != nil {
-		player.Wins++
-	} else {
-		f.league = append(f.league, Player{name, 1})
+	if err := f.reloadIfChanged(); err != nil {
+		return err
+	}
+	if err := fn(); err != nil {
+		return err
 	}
+	return f.save()
+}
 
-	check(json.NewEncoder(f.database).Encode(f.league))
+func (f *FileSystemPlayerStore) unlock() {
+	if err := f.lock.Unlock(); err != nil {
+		log.Printf("problem unlocking %s, %v\n", f.database.path, err)
+	}
+}
 
+// reloadIfChanged reads the database file again when it isn't the version
+// last loaded. When it can't, what is in memory may be out of date.
+func (f *FileSystemPlayerStore) reloadIfChanged() error {
+	current, err := os.Stat(f.database.path)
+	if err != nil {
+		return fmt.Errorf("problem checking %s for changes, %v", f.database.path, err)
+	}
+	if f.loaded != nil && os.SameFile(f.loaded, current) &&
+		f.loaded.ModTime().Equal(current.ModTime()) && f.loaded.Size() == current.Size() {
+		return nil
+	}
+
+	data, err := os.Open(f.database.path)
+	if err != nil {
+		return fmt.Errorf("problem reloading %s, %v", f.database.path, err)
+	}
+	defer data.Close()
+
+	db, err := loadFileSystemDatabase(data)
+	if err != nil {
+		return fmt.Errorf("problem reloading %s, %v", f.database.path, err)
+	}
+	f.league, f.games, f.chips, f.loaded = db.League, db.Games, db.Chips, current
+	return nil
+}
+
+// save writes the database to its file. When it can't, the change that
+// wasn't saved is dropped by reloading the file the next time it is used.
+func (f *FileSystemPlayerStore) save() error {
+	if err := json.NewEncoder(f.database).Encode(fileSystemDatabase{f.league, f.games, f.chips}); err != nil {
+		f.loaded = nil
+		return fmt.Errorf("problem saving %s, %v", f.database.path, err)
+	}
+
+	saved, err := os.Stat(f.database.path)
+	if err != nil {
+		f.loaded = nil
+		return fmt.Errorf("problem getting file info from file %s, %v", f.database.path, err)
+	}
+	f.loaded = saved
+	return nil
 }
 
-func FileSystemPlayerStoreFromFile(path string) (*FileSystemPlayerStore, func(), error) {
+func FileSystemPlayerStoreFromFile(path string, options ...FileSystemOption) (*FileSystemPlayerStore, func(), error) {
 	db, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0666)
 	if err != nil {
 		return nil, nil, fmt.Errorf("problem opening %s %v", path, err)
 	}
-	closeFunc := func() {
-		check(db.Close())
-	}
-	store, err := NewFileSystemPlayerStore(db)
+	store, err := NewFileSystemPlayerStore(db, options...)
 	if err != nil {
+		_ = db.Close()
 		return nil, nil, fmt.Errorf("problem creating file system player store, %v", err)
 	}
+	closeFunc := func() {
+		check(db.Close())
+		check(store.lock.Close())
+	}
 	return store, closeFunc, nil
 }
@@ -1,6 +1,11 @@
 package poker
 
 import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
 	"testing"
 )
 
@@ -14,12 +19,12 @@ func TestFileSystemStore(t *testing.T) {
 		store, err := NewFileSystemPlayerStore(database)
 		assertNoError(t, err)
 
-		got := store.GetLeague()
+		got := getLeague(t, store)
 		want := []Player{
-			{"Chris", 33},
-			{"Cleo", 10},
+			{Name: "Chris", Wins: 33},
+			{Name: "Cleo", Wins: 10},
 		}
-		got = store.GetLeague()
+		got = getLeague(t, store)
 		assertLeague(t, got, want)
 	})
 
@@ -32,7 +37,7 @@ func TestFileSystemStore(t *testing.T) {
 		store, err := NewFileSystemPlayerStore(database)
 		assertNoError(t, err)
 
-		got := store.GetPlayerScore("Chris")
+		got := getScore(t, store, "Chris")
 		want := 33
 		assertScoreEquals(t, got, want)
 	})
@@ -48,13 +53,13 @@ func TestFileSystemStore(t *testing.T) {
 
 		store.RecordWin("Chris")
 
-		got := store.GetPlayerScore("Chris")
+		got := getScore(t, store, "Chris")
 		want := 34
 		assertScoreEquals(t, got, want)
 
 	})
 
-	t.Run("store wins for new players", func(t *testing.T) {
+	t.Run("store wins for newly registered players", func(t *testing.T) {
 		database, cleanDatabase := createTempFile(t, `[
             {"Name": "Cleo", "Wins": 10},
             {"Name": "Chris", "Wins": 33}]`)
@@ -63,9 +68,10 @@ func TestFileSystemStore(t *testing.T) {
 		store, err := NewFileSystemPlayerStore(database)
 		assertNoError(t, err)
 
-		store.RecordWin("Pepper")
+		assertNoError(t, store.RegisterPlayer("Pepper", Profile{}))
+		assertNoError(t, store.RecordWin("Pepper"))
 
-		got := store.GetPlayerScore("Pepper")
+		got := getScore(t, store, "Pepper")
 		want := 1
 		assertScoreEquals(t, got, want)
 
@@ -89,19 +95,180 @@ func TestFileSystemStore(t *testing.T) {
 		store, err := NewFileSystemPlayerStore(database)
 		assertNoError(t, err)
 
-		got := store.GetLeague()
+		got := getLeague(t, store)
 
 		want := []Player{
-			{"Chris", 33},
-			{"Cleo", 10},
+			{Name: "Chris", Wins: 33},
+			{Name: "Cleo", Wins: 10},
 		}
 
 		assertLeague(t, got, want)
 
 		// read again
-		got = store.GetLeague()
+		got = getLeague(t, store)
 		assertLeague(t, got, want)
 
 	})
 
+	t.Run("recovers a corrupt database from the newest backup", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, `[{"Name": "Chris", "Wins": 33}]`)
+		defer cleanDatabase()
+		defer removeBackups(database.Name())
+
+		store, closeFunc, err := FileSystemPlayerStoreFromFile(database.Name(), WithBackups(1))
+		assertNoError(t, err)
+		store.RecordWin("Chris")
+		closeFunc()
+
+		assertNoError(t, ioutil.WriteFile(database.Name(), []byte(`[{"Name": "Chr`), 0666))
+
+		recovered, closeFunc, err := FileSystemPlayerStoreFromFile(database.Name(), WithBackups(1))
+		assertNoError(t, err)
+		defer closeFunc()
+
+		assertScoreEquals(t, getScore(t, recovered, "Chris"), 33)
+		assertCorruptFileKept(t, database.Name(), `[{"Name": "Chr`)
+	})
+
+	t.Run("starts empty when a corrupt database has no backup", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, `{"league": [`)
+		defer cleanDatabase()
+		defer removeBackups(database.Name())
+
+		store, err := NewFileSystemPlayerStore(database)
+		assertNoError(t, err)
+
+		assertLeague(t, getLeague(t, store), []Player{})
+		assertCorruptFileKept(t, database.Name(), `{"league": [`)
+
+		assertNoError(t, store.RegisterPlayer("Pepper", Profile{}))
+		assertNoError(t, store.RecordWin("Pepper"))
+		reopened, closeFunc, err := FileSystemPlayerStoreFromFile(database.Name())
+		assertNoError(t, err)
+		defer closeFunc()
+		assertScoreEquals(t, getScore(t, reopened, "Pepper"), 1)
+	})
+
+	t.Run("sees changes made by another store on the same file", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, `[{"Name": "Chris", "Wins": 33}]`)
+		defer cleanDatabase()
+
+		first, closeFirst, err := FileSystemPlayerStoreFromFile(database.Name())
+		assertNoError(t, err)
+		defer closeFirst()
+		second, closeSecond, err := FileSystemPlayerStoreFromFile(database.Name())
+		assertNoError(t, err)
+		defer closeSecond()
+
+		first.RecordWin("Chris")
+		second.RecordWin("Chris")
+		assertNoError(t, second.RegisterPlayer("Pepper", Profile{}))
+		second.RecordWin("Pepper")
+
+		assertScoreEquals(t, getScore(t, first, "Chris"), 35)
+		assertScoreEquals(t, getScore(t, first, "Pepper"), 1)
+	})
+
+	t.Run("doesn't overwrite the database when it can't be reloaded", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, `[{"Name": "Chris", "Wins": 33}]`)
+		defer cleanDatabase()
+
+		store, closeFunc, err := FileSystemPlayerStoreFromFile(database.Name())
+		assertNoError(t, err)
+		defer closeFunc()
+
+		// another process leaves the database in a state this one can't read.
+		const unreadable = `{"league": [`
+		if err := ioutil.WriteFile(database.Name(), []byte(unreadable), 0666); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := store.RecordWin("Chris"); err == nil {
+			t.Error("expected an error recording a win in a database that can't be reloaded")
+		}
+		if _, err := store.GetLeague(); err == nil {
+			t.Error("expected an error reading a database that can't be reloaded")
+		}
+		if data, _ := ioutil.ReadFile(database.Name()); string(data) != unreadable {
+			t.Errorf("got database %q want it left as %q", data, unreadable)
+		}
+	})
+
+	t.Run("returns an error and drops the change when the database can't be saved", func(t *testing.T) {
+		database, cleanDatabase := createTempFile(t, `[{"Name": "Chris", "Wins": 33}]`)
+		defer cleanDatabase()
+
+		store, err := NewFileSystemPlayerStore(database)
+		assertNoError(t, err)
+
+		path := store.database.path
+		store.database.path = filepath.Join(path+".missing", "game.db.json")
+		if err := store.RecordWin("Chris"); err == nil {
+			t.Error("expected an error saving to a directory that doesn't exist")
+		}
+		store.database.path = path
+
+		assertScoreEquals(t, getScore(t, store, "Chris"), 33)
+	})
+}
+
+// lockWriterEnv makes the test binary run TestFileSystemStoreWriterProcess
+// against the database it names.
+const lockWriterEnv = "POKER_LOCK_WRITER_DB"
+
+func TestFileSystemStoreAcrossProcesses(t *testing.T) {
+	if testing.Short() {
+		t.Skip("spawns writer processes")
+	}
+
+	database, cleanDatabase := createTempFile(t, `[{"Name": "Chris"}]`)
+	defer cleanDatabase()
+
+	const writers = 4
+	var wg sync.WaitGroup
+	wg.Add(writers)
+	for i := 0; i < writers; i++ {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestFileSystemStoreWriterProcess$")
+		cmd.Env = append(os.Environ(), lockWriterEnv+"="+database.Name())
+		go func() {
+			defer wg.Done()
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Errorf("writer process failed %v\n%s", err, out)
+			}
+		}()
+	}
+	wg.Wait()
+
+	store, closeFunc, err := FileSystemPlayerStoreFromFile(database.Name())
+	assertNoError(t, err)
+	defer closeFunc()
+
+	assertScoreEquals(t, getScore(t, store, "Chris"), writers*lockWriterWins)
+}
+
+const lockWriterWins = 25
+
+// TestFileSystemStoreWriterProcess is run as a separate process by TestFileSystemStoreAcrossProcesses.
+func TestFileSystemStoreWriterProcess(t *testing.T) {
+	path := os.Getenv(lockWriterEnv)
+	if path == "" {
+		t.Skip("only run as a writer process")
+	}
+
+	store, closeFunc, err := FileSystemPlayerStoreFromFile(path)
+	assertNoError(t, err)
+	defer closeFunc()
+
+	for i := 0; i < lockWriterWins; i++ {
+		assertNoError(t, store.RecordWin("Chris"))
+	}
+}
+
+func assertCorruptFileKept(t testing.TB, path, want string) {
+	t.Helper()
+	corrupt, _ := filepath.Glob(path + ".corrupt-*")
+	if len(corrupt) != 1 {
+		t.Fatalf("expected the corrupt database to be kept but found %v", corrupt)
+	}
+	assertFileContents(t, corrupt[0], want)
 }
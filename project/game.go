@@ -1,8 +1,109 @@
 package poker
 
-import "io"
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
 
+// Game is a game of poker, cancelling the context given to Start
+// aborts the game and stops its blind alerts.
 type Game interface {
-	Start(numberOfPlayers int, alertsDestination io.Writer)
-	Finish(winner string)
+	Start(ctx context.Context, numberOfPlayers int, blindStructure string, alertsDestination io.Writer) error
+	// Finish ends the game with winner, who must be a registered player.
+	// The game carries on if the winner can't be recorded.
+	Finish(winner string) error
+	Status() GameStatus
+	// Eliminate knocks player out of the tournament, they finish below
+	// everyone knocked out after them. They must be a registered player.
+	Eliminate(player string) error
+	// Rebuy buys player back into the tournament, undoing their
+	// elimination. They must be a registered player.
+	Rebuy(player string) error
+}
+
+// Errors returned when pausing or resuming a game, or registering or seating players at it.
+var (
+	ErrHandsDealt     = errors.New("players can't be seated once hands have been dealt")
+	ErrGameNotRunning = errors.New("the game is not running")
+	ErrGamePaused     = errors.New("the game is already paused")
+	ErrGameNotPaused  = errors.New("the game is not paused")
+	ErrCannotPause    = errors.New("this game can't be paused")
+	ErrCannotRegister = errors.New("this game can't register players")
+	ErrCannotSeat     = errors.New("this game can't seat players")
+)
+
+// Errors returned when dealing and betting on a game's hands.
+var (
+	ErrCannotDeal    = errors.New("this game can't deal cards")
+	ErrCannotBet     = errors.New("this game can't take bets")
+	ErrNobodySeated  = errors.New("seat the players with chips before dealing")
+	ErrNoHand        = errors.New("no hand is being played")
+	ErrStreetNotOver = errors.New("betting on this street isn't over")
+	ErrHandOver      = errors.New("everyone else has folded, show the hand down")
+)
+
+// Pauser is a Game whose blind clock can be stopped for a break.
+type Pauser interface {
+	Pause() error
+	Resume() error
+}
+
+// Registrar is a Game that can register players as they sit down to play.
+type Registrar interface {
+	Register(name string) error
+}
+
+// Seater is a Game that can be told who is sitting down to play, so that
+// everyone seated is recorded as playing in it.
+type Seater interface {
+	Seat(players ...string) error
+}
+
+// Dealer is a Game that deals hands of cards to the players seated at it.
+type Dealer interface {
+	// Deal deals a new hand, or the next street of the hand being played.
+	Deal() error
+	// Showdown shows the hands still in once the river is dealt, ending the hand.
+	Showdown() ([]HandResult, error)
+}
+
+// Bettor is a Game whose players bet on the hands they are dealt.
+type Bettor interface {
+	// Act takes the action of the player whose turn it is, an illegal
+	// action returns an *IllegalActionError.
+	Act(action Action) error
+}
+
+// GameStatus describes a game in progress.
+type GameStatus struct {
+	Players        int       `json:"players"`
+	BlindStructure string    `json:"blind_structure"`
+	Level          int       `json:"level"`
+	SmallBlind     int       `json:"small_blind"`
+	BigBlind       int       `json:"big_blind"`
+	Ante           int       `json:"ante"`
+	StartedAt      time.Time `json:"started_at"`
+	Paused         bool      `json:"paused"`
+	// NextSmallBlind is the small blind of the next level and
+	// NextLevelSeconds how many seconds until it starts, rounded up.
+	// Both are zero at the last level.
+	NextSmallBlind   int `json:"next_small_blind,omitempty"`
+	NextLevelSeconds int `json:"next_level_seconds,omitempty"`
+}
+
+// Countdown describes the time left at the current level, e.g.
+// "blinds go up to 400 in 4 minutes 12 seconds".
+func (s GameStatus) Countdown() string {
+	if s.NextSmallBlind == 0 {
+		return "blinds don't go up any more"
+	}
+	return fmt.Sprintf("blinds go up to %d in %s", s.NextSmallBlind, formatCountdown(time.Duration(s.NextLevelSeconds)*time.Second))
+}
+
+// Blinds is the blind level the game is at, to post the blinds of a hand from.
+func (s GameStatus) Blinds() BlindLevel {
+	return BlindLevel{SmallBlind: s.SmallBlind, BigBlind: s.BigBlind, Ante: s.Ante}
 }
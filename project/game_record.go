@@ -0,0 +1,106 @@
+package poker
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// GameRecord is the result of a finished game.
+type GameRecord struct {
+	ID             string    `json:"id"`
+	StartedAt      time.Time `json:"started_at"`
+	EndedAt        time.Time `json:"ended_at"`
+	Participants   []string  `json:"participants"`
+	FinishingOrder []string  `json:"finishing_order"`
+	BlindStructure string    `json:"blind_structure"`
+	Winner         string    `json:"winner"`
+	// Eliminations are the players knocked out, in the order they went out.
+	Eliminations []Elimination  `json:"eliminations,omitempty"`
+	Rebuys       map[string]int `json:"rebuys,omitempty"`
+}
+
+// Elimination is a player knocked out of a tournament and the place they finished in.
+type Elimination struct {
+	Player   string    `json:"player"`
+	Position int       `json:"position"`
+	At       time.Time `json:"at"`
+}
+
+// Played reports whether name took part in the game.
+func (g GameRecord) Played(name string) bool {
+	if g.Winner == name {
+		return true
+	}
+	for _, p := range g.Participants {
+		if p == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Position is the place name finished in, 1 for the winner, or 0 when the
+// game didn't record where they finished.
+func (g GameRecord) Position(name string) int {
+	for i, p := range g.FinishingOrder {
+		if p == name {
+			return i + 1
+		}
+	}
+	if g.Winner == name {
+		return 1
+	}
+	return 0
+}
+
+// GameHistory stores the records of finished games.
+type GameHistory interface {
+	RecordGame(game GameRecord) error
+	GetGames() ([]GameRecord, error)
+	GetPlayerGames(name string) ([]GameRecord, error)
+}
+
+// GameWinRecorder is implemented by stores that record a game's win
+// together with the game, like SeasonalPlayerStore, so the win only counts
+// if the game can be recorded.
+type GameWinRecorder interface {
+	// RecordGameWin records the win of game's winner and game, returning a
+	// *PlayerError if the winner isn't registered.
+	RecordGameWin(game GameRecord) error
+}
+
+// playerGames returns the games name took part in.
+func playerGames(games []GameRecord, name string) []GameRecord {
+	found := []GameRecord{}
+	for _, g := range games {
+		if g.Played(name) {
+			found = append(found, g)
+		}
+	}
+	return found
+}
+
+// lastGameID is the highest numbered ID of games, the IDs given by a
+// GameRegistry, or 0 when none of them are numbered.
+func lastGameID(games []GameRecord) int {
+	last := 0
+	for _, g := range games {
+		if id, err := strconv.Atoi(g.ID); err == nil && id > last {
+			last = id
+		}
+	}
+	return last
+}
+
+// newGameID returns a random ID for a game record, for games that aren't
+// played under an ID of their own.
+func newGameID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// without randomness the time is still unique enough to tell games apart.
+		return strconv.FormatInt(time.Now().UnixNano(), 16)
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,382 @@
+package poker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrGameNotFound is returned when there is no active game with the given ID.
+var ErrGameNotFound = errors.New("game not found")
+
+// GameInfo describes an active game and how many clients are watching it.
+type GameInfo struct {
+	ID      string `json:"id"`
+	Clients int    `json:"clients"`
+	GameStatus
+}
+
+// GameRegistry keeps track of the games being played, by ID.
+type GameRegistry struct {
+	newGame func(id string) Game
+
+	mu     sync.RWMutex
+	games  map[string]*gameSession
+	nextID int
+}
+
+// NewGameRegistry returns a registry starting each game it is asked for with
+// a game from newGame, given the ID it is played under. Games are numbered on
+// from lastID, so IDs already in the game history aren't used again.
+func NewGameRegistry(newGame func(id string) Game, lastID int) *GameRegistry {
+	return &GameRegistry{
+		newGame: newGame,
+		games:   map[string]*gameSession{},
+		nextID:  lastID,
+	}
+}
+
+// Start starts a new game with to as its first client, seating the named
+// players at it if there are any. to is told the game's ID before any of
+// the game's alerts. The returned channel is closed when the game finishes
+// or is abandoned.
+func (g *GameRegistry) Start(numberOfPlayers int, blindStructure string, to io.Writer, seats ...string) (id string, done <-chan struct{}, err error) {
+	g.mu.Lock()
+	g.nextID++
+	id = strconv.Itoa(g.nextID)
+	g.mu.Unlock()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	session := &gameSession{
+		game:    g.newGame(id),
+		cancel:  cancel,
+		clients: map[io.Writer]struct{}{to: {}},
+		done:    make(chan struct{}),
+	}
+
+	if err := session.game.Start(ctx, numberOfPlayers, blindStructure, session); err != nil {
+		cancel()
+		return "", nil, err
+	}
+	if len(seats) > 0 {
+		if err := seat(session.game, seats); err != nil {
+			cancel()
+			return "", nil, err
+		}
+	}
+
+	g.mu.Lock()
+	g.games[id] = session
+	g.mu.Unlock()
+
+	if err := announceStarted(to, id); err != nil {
+		log.Printf("problem announcing game %s started, %v\n", id, err)
+	}
+	session.release()
+	return id, session.done, nil
+}
+
+// StartAnnouncer is implemented by clients that want the ID of the game
+// they started rather than the text of it.
+type StartAnnouncer interface {
+	AnnounceStarted(id string) error
+}
+
+func announceStarted(to io.Writer, id string) error {
+	if announcer, ok := to.(StartAnnouncer); ok {
+		return announcer.AnnounceStarted(id)
+	}
+	_, err := fmt.Fprintf(to, "Game %s started\n", id)
+	return err
+}
+
+// Join adds to as a client of the game with id, so it receives the game's alerts.
+func (g *GameRegistry) Join(id string, to io.Writer) (done <-chan struct{}, err error) {
+	session, err := g.session(id)
+	if err != nil {
+		return nil, err
+	}
+
+	session.mu.Lock()
+	defer session.mu.Unlock()
+	session.clients[to] = struct{}{}
+	return session.done, nil
+}
+
+// Leave removes to from the game with id, a game without clients is abandoned.
+func (g *GameRegistry) Leave(id string, to io.Writer) {
+	session, err := g.session(id)
+	if err != nil {
+		return
+	}
+
+	session.mu.Lock()
+	delete(session.clients, to)
+	empty := len(session.clients) == 0
+	session.mu.Unlock()
+
+	if empty {
+		g.end(id, session)
+	}
+}
+
+// Finish finishes the game with id, recording the winner. The game is
+// still running if the winner can't be recorded.
+func (g *GameRegistry) Finish(id, winner string) error {
+	session, err := g.session(id)
+	if err != nil {
+		return err
+	}
+
+	// clients finishing the game at once take turns, those after the one
+	// that finished it find it gone.
+	session.finishMu.Lock()
+	defer session.finishMu.Unlock()
+	if _, err := g.session(id); err != nil {
+		return err
+	}
+
+	if err := session.game.Finish(winner); err != nil {
+		return err
+	}
+	g.end(id, session)
+	return nil
+}
+
+// Pause stops the blind clock of the game with id.
+func (g *GameRegistry) Pause(id string) error {
+	return g.pause(id, Pauser.Pause)
+}
+
+// Resume restarts the blind clock of the game with id.
+func (g *GameRegistry) Resume(id string) error {
+	return g.pause(id, Pauser.Resume)
+}
+
+func (g *GameRegistry) pause(id string, pauseOrResume func(Pauser) error) error {
+	session, err := g.session(id)
+	if err != nil {
+		return err
+	}
+
+	pauser, ok := session.game.(Pauser)
+	if !ok {
+		return ErrCannotPause
+	}
+	return pauseOrResume(pauser)
+}
+
+// Deal deals the next cards of the game with id.
+func (g *GameRegistry) Deal(id string) error {
+	return g.deal(id, Dealer.Deal)
+}
+
+// Showdown shows down the hand being played in the game with id.
+func (g *GameRegistry) Showdown(id string) error {
+	return g.deal(id, func(dealer Dealer) error {
+		_, err := dealer.Showdown()
+		return err
+	})
+}
+
+func (g *GameRegistry) deal(id string, deal func(Dealer) error) error {
+	session, err := g.session(id)
+	if err != nil {
+		return err
+	}
+
+	dealer, ok := session.game.(Dealer)
+	if !ok {
+		return ErrCannotDeal
+	}
+	return deal(dealer)
+}
+
+// Act takes a player's betting action in the game with id.
+func (g *GameRegistry) Act(id string, action Action) error {
+	session, err := g.session(id)
+	if err != nil {
+		return err
+	}
+
+	bettor, ok := session.game.(Bettor)
+	if !ok {
+		return ErrCannotBet
+	}
+	return bettor.Act(action)
+}
+
+func seat(game Game, players []string) error {
+	seater, ok := game.(Seater)
+	if !ok {
+		return ErrCannotSeat
+	}
+	return seater.Seat(players...)
+}
+
+// Get returns the game with id.
+func (g *GameRegistry) Get(id string) (GameInfo, error) {
+	session, err := g.session(id)
+	if err != nil {
+		return GameInfo{}, err
+	}
+	return session.info(id), nil
+}
+
+// List returns all active games ordered by ID.
+func (g *GameRegistry) List() []GameInfo {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	games := []GameInfo{}
+	for id, session := range g.games {
+		games = append(games, session.info(id))
+	}
+	sort.Slice(games, func(i, j int) bool {
+		a, _ := strconv.Atoi(games[i].ID)
+		b, _ := strconv.Atoi(games[j].ID)
+		return a < b
+	})
+	return games
+}
+
+func (g *GameRegistry) session(id string) (*gameSession, error) {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	session, ok := g.games[id]
+	if !ok {
+		return nil, ErrGameNotFound
+	}
+	return session, nil
+}
+
+func (g *GameRegistry) end(id string, session *gameSession) {
+	g.mu.Lock()
+	if g.games[id] != session {
+		g.mu.Unlock()
+		return
+	}
+	delete(g.games, id)
+	g.mu.Unlock()
+
+	session.cancel()
+	close(session.done)
+}
+
+// gameSession is a game and the clients its alerts are sent to. Alerts are
+// held back until the client that started the game has been told its ID.
+type gameSession struct {
+	game     Game
+	cancel   context.CancelFunc
+	done     chan struct{}
+	finishMu sync.Mutex
+
+	mu       sync.Mutex
+	clients  map[io.Writer]struct{}
+	released bool
+	held     []func(client io.Writer) error
+}
+
+// broadcast sends an alert to every client of the game, or holds it back
+// until the session is released.
+func (s *gameSession) broadcast(alert func(client io.Writer) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.released {
+		s.held = append(s.held, alert)
+		return
+	}
+	s.send(alert)
+}
+
+// release sends the alerts held back, and every alert after them straight away.
+func (s *gameSession) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.released = true
+	for _, alert := range s.held {
+		s.send(alert)
+	}
+	s.held = nil
+}
+
+// send sends alert to every client, s.mu must be held.
+func (s *gameSession) send(alert func(client io.Writer) error) {
+	for client := range s.clients {
+		if err := alert(client); err != nil {
+			log.Printf("problem sending to game client %v\n", err)
+		}
+	}
+}
+
+// Write sends p to every client of the game.
+func (s *gameSession) Write(p []byte) (int, error) {
+	message := append([]byte{}, p...)
+	s.broadcast(func(client io.Writer) error {
+		_, err := client.Write(message)
+		return err
+	})
+	return len(p), nil
+}
+
+// AnnounceBlind sends the blind to every client of the game.
+func (s *gameSession) AnnounceBlind(amount int) error {
+	s.broadcast(func(client io.Writer) error {
+		return announceBlind(client, amount)
+	})
+	return nil
+}
+
+// AnnounceWarning sends the warning to every client of the game.
+func (s *gameSession) AnnounceWarning(amount int, in time.Duration) error {
+	s.broadcast(func(client io.Writer) error {
+		return announceWarning(client, amount, in)
+	})
+	return nil
+}
+
+// AnnounceHand sends the hand to every client of the game, each seeing
+// only its own hole cards.
+func (s *gameSession) AnnounceHand(hand Hand) error {
+	s.broadcast(func(client io.Writer) error {
+		return announceHand(client, hand)
+	})
+	return nil
+}
+
+// AnnounceShowdown sends the showdown to every client of the game.
+func (s *gameSession) AnnounceShowdown(results []HandResult, winners []string) error {
+	s.broadcast(func(client io.Writer) error {
+		return announceShowdown(client, results, winners)
+	})
+	return nil
+}
+
+// AnnounceBetting sends how the betting stands to every client of the game.
+func (s *gameSession) AnnounceBetting(state BettingState) error {
+	s.broadcast(func(client io.Writer) error {
+		return announceBetting(client, state)
+	})
+	return nil
+}
+
+func (s *gameSession) info(id string) GameInfo {
+	s.mu.Lock()
+	clients := len(s.clients)
+	s.mu.Unlock()
+
+	return GameInfo{
+		ID:         id,
+		Clients:    clients,
+		GameStatus: s.game.Status(),
+	}
+}
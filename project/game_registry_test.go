@@ -0,0 +1,261 @@
+package poker
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGameRegistry(t *testing.T) {
+	newRegistry := func() (*GameRegistry, []*GameSpy) {
+		var games []*GameSpy
+		registry := NewGameRegistry(func(string) Game {
+			game := &GameSpy{BlindAlert: []byte("Blind is 100")}
+			games = append(games, game)
+			return game
+		}, 0)
+		return registry, games
+	}
+
+	t.Run("each game gets its own ID and game", func(t *testing.T) {
+		registry, _ := newRegistry()
+
+		first, _, err := registry.Start(3, "", &bytes.Buffer{})
+		assertNoError(t, err)
+		second, _, err := registry.Start(5, "turbo", &bytes.Buffer{})
+		assertNoError(t, err)
+
+		if first == second {
+			t.Fatalf("expected different IDs but both were %q", first)
+		}
+
+		games := registry.List()
+		if len(games) != 2 {
+			t.Fatalf("got %d games want 2", len(games))
+		}
+		if games[0].Players != 3 || games[1].Players != 5 {
+			t.Errorf("got games %+v", games)
+		}
+	})
+
+	t.Run("the client that starts a game is told its ID before any alert", func(t *testing.T) {
+		registry, _ := newRegistry()
+
+		host := &bytes.Buffer{}
+		_, _, err := registry.Start(3, "", host)
+		assertNoError(t, err)
+
+		if want := "Game 1 started\nBlind is 100"; host.String() != want {
+			t.Errorf("got %q want %q", host.String(), want)
+		}
+	})
+
+	t.Run("games are numbered on from the last ID and played under it", func(t *testing.T) {
+		var ids []string
+		registry := NewGameRegistry(func(id string) Game {
+			ids = append(ids, id)
+			return &GameSpy{}
+		}, 41)
+
+		id, _, err := registry.Start(3, "", &bytes.Buffer{})
+		assertNoError(t, err)
+
+		if id != "42" || len(ids) != 1 || ids[0] != id {
+			t.Errorf("got game %q played as %q want 42", id, ids)
+		}
+	})
+
+	t.Run("seats the named players at the game it starts", func(t *testing.T) {
+		game := &GameSpy{}
+		registry := NewGameRegistry(func(string) Game { return game }, 0)
+
+		_, _, err := registry.Start(3, "", &bytes.Buffer{}, "Chris", "Cleo")
+		assertNoError(t, err)
+		assertGameCommands(t, game, "seat Chris Cleo")
+
+		registry = NewGameRegistry(func(string) Game { return struct{ Game }{&GameSpy{}} }, 0)
+		if _, _, err := registry.Start(3, "", &bytes.Buffer{}, "Chris"); !errors.Is(err, ErrCannotSeat) {
+			t.Errorf("got error %v want %v", err, ErrCannotSeat)
+		}
+		if games := registry.List(); len(games) != 0 {
+			t.Errorf("got games %+v want the game that couldn't seat its players not to have started", games)
+		}
+	})
+
+	t.Run("finishing one game leaves the others running", func(t *testing.T) {
+		var games []*GameSpy
+		registry := NewGameRegistry(func(string) Game {
+			game := &GameSpy{}
+			games = append(games, game)
+			return game
+		}, 0)
+
+		first, firstDone, _ := registry.Start(3, "", &bytes.Buffer{})
+		second, secondDone, _ := registry.Start(4, "", &bytes.Buffer{})
+
+		assertNoError(t, registry.Finish(first, "Manu"))
+
+		assertGameFinishCalledWith(t, games[0], "Manu")
+		assertGameNotFinished(t, games[1])
+		assertClosed(t, firstDone)
+		assertOpen(t, secondDone)
+
+		if _, err := registry.Get(first); err != ErrGameNotFound {
+			t.Errorf("got %v want %v", err, ErrGameNotFound)
+		}
+		if _, err := registry.Get(second); err != nil {
+			t.Errorf("expected second game to still be running, %v", err)
+		}
+	})
+
+	t.Run("a game keeps running when its winner can't be recorded", func(t *testing.T) {
+		game := &GameSpy{FinishError: &PlayerError{"Nobody", ErrPlayerNotFound}}
+		registry := NewGameRegistry(func(string) Game { return game }, 0)
+		id, done, _ := registry.Start(3, "", &bytes.Buffer{})
+
+		if err := registry.Finish(id, "Nobody"); !errors.Is(err, ErrPlayerNotFound) {
+			t.Errorf("got %v want %v", err, ErrPlayerNotFound)
+		}
+		assertOpen(t, done)
+		if _, err := registry.Get(id); err != nil {
+			t.Errorf("expected the game to still be running, %v", err)
+		}
+
+		assertNoError(t, registry.Finish(id, "Manu"))
+		assertClosed(t, done)
+	})
+
+	t.Run("a game finished by several clients at once is only recorded once", func(t *testing.T) {
+		store := &StubPlayerStore{mu: &sync.RWMutex{}}
+		registry := NewGameRegistry(func(string) Game { return NewTexasHoldem(dummyBlindAlerter, store) }, 0)
+		id, done, _ := registry.Start(3, "", &bytes.Buffer{})
+
+		const clients = 10
+		errs := make(chan error, clients)
+		for i := 0; i < clients; i++ {
+			go func() {
+				errs <- registry.Finish(id, "Manu")
+			}()
+		}
+
+		finished := 0
+		for i := 0; i < clients; i++ {
+			switch err := <-errs; err {
+			case nil:
+				finished++
+			case ErrGameNotFound:
+			default:
+				t.Errorf("got %v want the game finished or %v", err, ErrGameNotFound)
+			}
+		}
+		if finished != 1 {
+			t.Errorf("game was finished %d times want once", finished)
+		}
+		assertClosed(t, done)
+		AssertPlayerWin(t, store, "Manu")
+		if games := getGames(t, store); len(games) != 1 {
+			t.Errorf("got %d games recorded want 1", len(games))
+		}
+	})
+
+	t.Run("clients that join receive the game's alerts", func(t *testing.T) {
+		registry := NewGameRegistry(func(string) Game { return &GameSpy{} }, 0)
+		id, _, _ := registry.Start(3, "", &bytes.Buffer{})
+
+		watcher := &bytes.Buffer{}
+		_, err := registry.Join(id, watcher)
+		assertNoError(t, err)
+
+		session, _ := registry.session(id)
+		_, _ = session.Write([]byte("Blind is now 200\n"))
+
+		if watcher.String() != "Blind is now 200\n" {
+			t.Errorf("got %q want %q", watcher.String(), "Blind is now 200\n")
+		}
+
+		info, _ := registry.Get(id)
+		if info.Clients != 2 {
+			t.Errorf("got %d clients want 2", info.Clients)
+		}
+	})
+
+	t.Run("a game is abandoned when its last client leaves", func(t *testing.T) {
+		game := &GameSpy{}
+		registry := NewGameRegistry(func(string) Game { return game }, 0)
+
+		host := &bytes.Buffer{}
+		id, done, _ := registry.Start(3, "", host)
+		watcher := &bytes.Buffer{}
+		_, _ = registry.Join(id, watcher)
+
+		registry.Leave(id, host)
+		assertOpen(t, done)
+
+		registry.Leave(id, watcher)
+		assertClosed(t, done)
+		assertDone(t, game.StartCtx)
+		assertGameNotFinished(t, game)
+	})
+
+	t.Run("pauses and resumes games that can be paused", func(t *testing.T) {
+		game := &GameSpy{}
+		registry := NewGameRegistry(func(string) Game { return game }, 0)
+		id, _, _ := registry.Start(3, "", &bytes.Buffer{})
+
+		assertNoError(t, registry.Pause(id))
+		assertNoError(t, registry.Resume(id))
+		assertGameCommands(t, game, "pause", "resume")
+
+		unpausable := NewGameRegistry(func(string) Game { return struct{ Game }{&GameSpy{}} }, 0)
+		id, _, _ = unpausable.Start(3, "", &bytes.Buffer{})
+		if err := unpausable.Pause(id); err != ErrCannotPause {
+			t.Errorf("got %v want %v", err, ErrCannotPause)
+		}
+		if err := registry.Pause("42"); err != ErrGameNotFound {
+			t.Errorf("got %v want %v", err, ErrGameNotFound)
+		}
+	})
+
+	t.Run("takes bets in games that take them", func(t *testing.T) {
+		game := &GameSpy{}
+		registry := NewGameRegistry(func(string) Game { return game }, 0)
+		id, _, _ := registry.Start(3, "", &bytes.Buffer{})
+
+		assertNoError(t, registry.Act(id, Action{Player: "Chris", Type: ActionRaise, Amount: 400}))
+		assertGameCommands(t, game, "Chris raise 400")
+
+		cannotBet := NewGameRegistry(func(string) Game { return struct{ Game }{&GameSpy{}} }, 0)
+		id, _, _ = cannotBet.Start(3, "", &bytes.Buffer{})
+		if err := cannotBet.Act(id, Action{Player: "Chris", Type: ActionCall}); err != ErrCannotBet {
+			t.Errorf("got %v want %v", err, ErrCannotBet)
+		}
+	})
+
+	t.Run("joining an unknown game is an error", func(t *testing.T) {
+		registry, _ := newRegistry()
+
+		if _, err := registry.Join("42", &bytes.Buffer{}); err != ErrGameNotFound {
+			t.Errorf("got %v want %v", err, ErrGameNotFound)
+		}
+	})
+}
+
+func assertClosed(t testing.TB, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+	case <-time.After(500 * time.Millisecond):
+		t.Error("expected channel to be closed")
+	}
+}
+
+func assertOpen(t testing.TB, done <-chan struct{}) {
+	t.Helper()
+	select {
+	case <-done:
+		t.Error("expected channel to be open")
+	default:
+	}
+}
@@ -0,0 +1,218 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Streets of a hand of Texas Hold'em, in the order they are dealt.
+const (
+	PreFlop = "preflop"
+	Flop    = "flop"
+	Turn    = "turn"
+	River   = "river"
+	// Showdown is reached once betting is over for the hand.
+	Showdown = "showdown"
+)
+
+// ErrHandComplete is returned when dealing past the river.
+var ErrHandComplete = errors.New("all the community cards have been dealt")
+
+// Hand is one hand of Texas Hold'em: each player's hole cards and the board.
+type Hand struct {
+	Players []string          `json:"players"`
+	Hole    map[string][]Card `json:"hole"`
+	Board   []Card            `json:"board"`
+
+	deck *Deck
+}
+
+// HandResult is the best hand a player made at showdown, and the hole cards
+// they show to make it.
+type HandResult struct {
+	Player string    `json:"player"`
+	Hole   []Card    `json:"hole,omitempty"`
+	Value  HandValue `json:"value"`
+}
+
+// HandView is a hand as one player at the table sees it: the board, how
+// many hole cards each player holds and only their own hole cards.
+type HandView struct {
+	Players   []string       `json:"players"`
+	HoleCards map[string]int `json:"hole_cards"`
+	Hole      []Card         `json:"hole,omitempty"`
+	Board     []Card         `json:"board"`
+}
+
+// SeenBy is the hand as player sees it, anyone not playing in it sees no
+// hole cards at all.
+func (h Hand) SeenBy(player string) HandView {
+	view := HandView{
+		Players:   append([]string{}, h.Players...),
+		HoleCards: map[string]int{},
+		Hole:      append([]Card(nil), h.Hole[player]...),
+		Board:     append([]Card{}, h.Board...),
+	}
+	for _, p := range h.Players {
+		view.HoleCards[p] = len(h.Hole[p])
+	}
+	return view
+}
+
+// DealHand deals two hole cards to each player from deck, one at a time in seat order.
+func DealHand(deck *Deck, players []string) (*Hand, error) {
+	if len(players) < 2 {
+		return nil, fmt.Errorf("a hand needs at least 2 players, got %d", len(players))
+	}
+
+	hand := &Hand{
+		Players: append([]string{}, players...),
+		Hole:    map[string][]Card{},
+		Board:   []Card{},
+		deck:    deck,
+	}
+	for round := 0; round < 2; round++ {
+		for _, player := range players {
+			if _, dealt := hand.Hole[player]; dealt && round == 0 {
+				return nil, fmt.Errorf("player %q is seated twice", player)
+			}
+			card, err := deck.Deal(1)
+			if err != nil {
+				return nil, err
+			}
+			hand.Hole[player] = append(hand.Hole[player], card...)
+		}
+	}
+	return hand, nil
+}
+
+// Street is the betting round the hand has reached.
+func (h *Hand) Street() string {
+	switch len(h.Board) {
+	case 0:
+		return PreFlop
+	case 3:
+		return Flop
+	case 4:
+		return Turn
+	}
+	return River
+}
+
+// DealNext burns a card and deals the next street, the flop then the turn then the river.
+func (h *Hand) DealNext() error {
+	n := 1
+	switch h.Street() {
+	case PreFlop:
+		n = 3
+	case River:
+		return ErrHandComplete
+	}
+
+	cards, err := h.deck.Deal(n + 1)
+	if err != nil {
+		return err
+	}
+	h.Board = append(h.Board, cards[1:]...)
+	return nil
+}
+
+// Showdown evaluates the hands of players, who must be in the hand, once
+// the river is dealt. Results are in the order of players.
+func (h *Hand) Showdown(players ...string) ([]HandResult, error) {
+	if h.Street() != River {
+		return nil, fmt.Errorf("can't show down before the river, the hand is at the %s", h.Street())
+	}
+
+	results := make([]HandResult, 0, len(players))
+	for _, player := range players {
+		hole, ok := h.Hole[player]
+		if !ok {
+			return nil, fmt.Errorf("player %q is not in the hand", player)
+		}
+		value, err := EvaluateHand(append(append([]Card{}, hole...), h.Board...))
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, HandResult{Player: player, Hole: append([]Card{}, hole...), Value: value})
+	}
+	return results, nil
+}
+
+// Winners returns the players with the best hand, more than one when they split the pot.
+func Winners(results []HandResult) []string {
+	var (
+		winners []string
+		best    HandValue
+	)
+	for _, result := range results {
+		if winners == nil {
+			winners, best = []string{result.Player}, result.Value
+			continue
+		}
+		switch result.Value.Compare(best) {
+		case 1:
+			winners, best = []string{result.Player}, result.Value
+		case 0:
+			winners = append(winners, result.Player)
+		}
+	}
+	return winners
+}
+
+// HandAnnouncer is implemented by alert destinations that want the cards of
+// a hand and its showdown rather than the text of them. They are given the
+// whole hand and must only show each player their own hole cards.
+type HandAnnouncer interface {
+	AnnounceHand(hand Hand) error
+	AnnounceShowdown(results []HandResult, winners []string) error
+}
+
+// announceHand announces who is dealt in when the hand is dealt and the
+// board as each street is. The text never shows anyone's hole cards, which
+// are only revealed at the showdown.
+func announceHand(to io.Writer, hand Hand) error {
+	if announcer, ok := to.(HandAnnouncer); ok {
+		return announcer.AnnounceHand(hand)
+	}
+
+	var text strings.Builder
+	if len(hand.Board) == 0 {
+		fmt.Fprintf(&text, "Hole cards dealt to %s\n", strings.Join(hand.Players, ", "))
+	} else {
+		street := hand.Street()
+		fmt.Fprintf(&text, "%s%s: %s\n", strings.ToUpper(street[:1]), street[1:], formatCards(hand.Board))
+	}
+	_, err := io.WriteString(to, text.String())
+	return err
+}
+
+// announceShowdown announces the hole cards and best hand of each player
+// shown down and who won.
+func announceShowdown(to io.Writer, results []HandResult, winners []string) error {
+	if announcer, ok := to.(HandAnnouncer); ok {
+		return announcer.AnnounceShowdown(results, winners)
+	}
+
+	var text strings.Builder
+	for _, result := range results {
+		fmt.Fprintf(&text, "%s shows %s, %s\n", result.Player, formatCards(result.Hole), result.Value)
+	}
+	if len(winners) == 1 {
+		fmt.Fprintf(&text, "%s wins the hand\n", winners[0])
+	} else {
+		fmt.Fprintf(&text, "%s split the pot\n", strings.Join(winners, " and "))
+	}
+	_, err := io.WriteString(to, text.String())
+	return err
+}
+
+func formatCards(cards []Card) string {
+	names := make([]string, len(cards))
+	for i, c := range cards {
+		names[i] = c.String()
+	}
+	return strings.Join(names, " ")
+}
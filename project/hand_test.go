@@ -0,0 +1,175 @@
+package poker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDealHand(t *testing.T) {
+	t.Run("deals two hole cards to each player one at a time", func(t *testing.T) {
+		hand, err := DealHand(NewDeck(), []string{"Chris", "Cleo", "Pepper"})
+		assertNoError(t, err)
+
+		want := map[string][]Card{
+			"Chris":  mustParseCards(t, "2c 5c"),
+			"Cleo":   mustParseCards(t, "3c 6c"),
+			"Pepper": mustParseCards(t, "4c 7c"),
+		}
+		if !reflect.DeepEqual(hand.Hole, want) {
+			t.Errorf("got hole cards %v want %v", hand.Hole, want)
+		}
+		if hand.Street() != PreFlop {
+			t.Errorf("got street %q want %q", hand.Street(), PreFlop)
+		}
+	})
+
+	t.Run("burns a card before dealing each street", func(t *testing.T) {
+		hand, err := DealHand(NewDeck(), []string{"Chris", "Cleo"})
+		assertNoError(t, err)
+
+		streets := []struct {
+			street string
+			board  string
+		}{
+			{Flop, "7c 8c 9c"},
+			{Turn, "7c 8c 9c Jc"},
+			{River, "7c 8c 9c Jc Kc"},
+		}
+		for _, s := range streets {
+			assertNoError(t, hand.DealNext())
+			if hand.Street() != s.street {
+				t.Errorf("got street %q want %q", hand.Street(), s.street)
+			}
+			if board := mustParseCards(t, s.board); !reflect.DeepEqual(hand.Board, board) {
+				t.Errorf("got board %v want %v", hand.Board, board)
+			}
+		}
+
+		if err := hand.DealNext(); err != ErrHandComplete {
+			t.Errorf("got error %v want %v", err, ErrHandComplete)
+		}
+		if hand.deck.Remaining() != 52-4-8 {
+			t.Errorf("got %d cards left want %d", hand.deck.Remaining(), 52-4-8)
+		}
+	})
+
+	t.Run("each player only sees their own hole cards", func(t *testing.T) {
+		hand, err := DealHand(NewDeck(), []string{"Chris", "Cleo"})
+		assertNoError(t, err)
+		assertNoError(t, hand.DealNext())
+
+		counts := map[string]int{"Chris": 2, "Cleo": 2}
+		board := mustParseCards(t, "7c 8c 9c")
+		for player, hole := range map[string][]Card{"Cleo": mustParseCards(t, "3c 5c"), "Pepper": nil} {
+			want := HandView{Players: []string{"Chris", "Cleo"}, HoleCards: counts, Hole: hole, Board: board}
+			if got := hand.SeenBy(player); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %s seeing %+v want %+v", player, got, want)
+			}
+		}
+	})
+
+	t.Run("the same seed deals the same hand", func(t *testing.T) {
+		first := mustDealToRiver(t, NewShuffledDeck(2026), "Chris", "Cleo")
+		second := mustDealToRiver(t, NewShuffledDeck(2026), "Chris", "Cleo")
+
+		if !reflect.DeepEqual(first.Hole, second.Hole) || !reflect.DeepEqual(first.Board, second.Board) {
+			t.Errorf("got different hands %v and %v", first, second)
+		}
+	})
+
+	t.Run("needs at least two different players and enough cards", func(t *testing.T) {
+		if _, err := DealHand(NewDeck(), []string{"Chris"}); err == nil {
+			t.Error("expected an error dealing to one player")
+		}
+		if _, err := DealHand(NewDeck(), []string{"Chris", "Chris"}); err == nil {
+			t.Error("expected an error dealing to the same player twice")
+		}
+		players := make([]string, 27)
+		for i := range players {
+			players[i] = string(rune('a' + i))
+		}
+		if _, err := DealHand(NewDeck(), players); err != ErrNotEnoughCards {
+			t.Errorf("got error %v want %v", err, ErrNotEnoughCards)
+		}
+	})
+}
+
+func TestShowdown(t *testing.T) {
+	cases := []struct {
+		name    string
+		board   string
+		hole    map[string]string
+		winners []string
+	}{
+		{
+			name:    "best hand wins",
+			board:   "Ah Kd 7c 7s 2h",
+			hole:    map[string]string{"Chris": "As Qc", "Cleo": "7h 3d", "Pepper": "Kh Ks"},
+			winners: []string{"Pepper"},
+		},
+		{
+			name:    "kicker decides",
+			board:   "Ah Kd 8c 7s 2h",
+			hole:    map[string]string{"Chris": "As Qc", "Cleo": "Ac Jd"},
+			winners: []string{"Chris"},
+		},
+		{
+			name:    "the board plays and the pot is split",
+			board:   "Ah Kh Qh Jh Th",
+			hole:    map[string]string{"Chris": "2s 3c", "Cleo": "4d 5d", "Pepper": "9c 9d"},
+			winners: []string{"Chris", "Cleo", "Pepper"},
+		},
+		{
+			name:    "same hand with different suits splits",
+			board:   "Kd 9c 7s 4h 2d",
+			hole:    map[string]string{"Chris": "Ac Qc", "Cleo": "Ad Qs", "Pepper": "Jc 3c"},
+			winners: []string{"Chris", "Cleo"},
+		},
+		{
+			name:    "a kicker below the board doesn't play",
+			board:   "As Ad Kc Kd Qh",
+			hole:    map[string]string{"Chris": "Jh 2c", "Cleo": "Tc 9c"},
+			winners: []string{"Chris", "Cleo"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			players := []string{"Chris", "Cleo", "Pepper"}[:len(c.hole)]
+			hand := &Hand{Players: players, Hole: map[string][]Card{}, Board: mustParseCards(t, c.board)}
+			for player, hole := range c.hole {
+				hand.Hole[player] = mustParseCards(t, hole)
+			}
+
+			results, err := hand.Showdown(players...)
+			assertNoError(t, err)
+
+			if got := Winners(results); !reflect.DeepEqual(got, c.winners) {
+				t.Errorf("got winners %v want %v from %v", got, c.winners, results)
+			}
+		})
+	}
+
+	t.Run("only after the river and for players in the hand", func(t *testing.T) {
+		hand, err := DealHand(NewDeck(), []string{"Chris", "Cleo"})
+		assertNoError(t, err)
+		if _, err := hand.Showdown("Chris", "Cleo"); err == nil {
+			t.Error("expected an error showing down before the river")
+		}
+
+		hand = mustDealToRiver(t, NewDeck(), "Chris", "Cleo")
+		if _, err := hand.Showdown("Chris", "Pepper"); err == nil {
+			t.Error("expected an error showing down a player not in the hand")
+		}
+	})
+}
+
+func mustDealToRiver(t testing.TB, deck *Deck, players ...string) *Hand {
+	t.Helper()
+	hand, err := DealHand(deck, players)
+	assertNoError(t, err)
+	for hand.Street() != River {
+		assertNoError(t, hand.DealNext())
+	}
+	return hand
+}
@@ -0,0 +1,188 @@
+package poker
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// HandCategory is the kind of a poker hand, from HighCard up to StraightFlush.
+type HandCategory int
+
+// Hand categories, weakest first.
+const (
+	HighCard HandCategory = iota
+	OnePair
+	TwoPair
+	ThreeOfAKind
+	Straight
+	Flush
+	FullHouse
+	FourOfAKind
+	StraightFlush
+)
+
+var handCategoryNames = []string{
+	"high card",
+	"one pair",
+	"two pair",
+	"three of a kind",
+	"straight",
+	"flush",
+	"full house",
+	"four of a kind",
+	"straight flush",
+}
+
+func (h HandCategory) String() string {
+	return handCategoryNames[h]
+}
+
+// HandValue is the best five card hand that can be made from some cards.
+// Ranks breaks ties between hands of the same category: the ranks of the
+// groups of cards, largest group first, then the kickers, highest first.
+type HandValue struct {
+	Category HandCategory `json:"category"`
+	Ranks    []Rank       `json:"ranks"`
+	Cards    []Card       `json:"cards"`
+}
+
+// Compare returns 1 if h beats other, -1 if other beats h and 0 if they split.
+func (h HandValue) Compare(other HandValue) int {
+	if h.Category != other.Category {
+		return compareInts(int(h.Category), int(other.Category))
+	}
+	for i := range h.Ranks {
+		if h.Ranks[i] != other.Ranks[i] {
+			return compareInts(int(h.Ranks[i]), int(other.Ranks[i]))
+		}
+	}
+	return 0
+}
+
+func (h HandValue) String() string {
+	cards := make([]string, len(h.Cards))
+	for i, c := range h.Cards {
+		cards[i] = c.String()
+	}
+	return fmt.Sprintf("%s (%s)", h.Category, strings.Join(cards, " "))
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a > b:
+		return 1
+	case a < b:
+		return -1
+	}
+	return 0
+}
+
+// EvaluateHand returns the best five card hand among 5 to 7 cards.
+func EvaluateHand(cards []Card) (HandValue, error) {
+	if len(cards) < 5 || len(cards) > 7 {
+		return HandValue{}, fmt.Errorf("can only evaluate 5 to 7 cards, got %d", len(cards))
+	}
+	seen := map[Card]bool{}
+	for _, c := range cards {
+		if seen[c] {
+			return HandValue{}, fmt.Errorf("card %s appears more than once", c)
+		}
+		seen[c] = true
+	}
+
+	var best HandValue
+	five := make([]Card, 5)
+	first := true
+	forEachFive(cards, five, 0, 0, func() {
+		value := evaluateFive(five)
+		if first || value.Compare(best) > 0 {
+			best, first = value, false
+		}
+	})
+	return best, nil
+}
+
+// forEachFive calls fn with five set to each combination of five of cards.
+func forEachFive(cards, five []Card, start, picked int, fn func()) {
+	if picked == len(five) {
+		fn()
+		return
+	}
+	for i := start; i <= len(cards)-(len(five)-picked); i++ {
+		five[picked] = cards[i]
+		forEachFive(cards, five, i+1, picked+1, fn)
+	}
+}
+
+// evaluateFive ranks exactly five distinct cards.
+func evaluateFive(five []Card) HandValue {
+	cards := append([]Card{}, five...)
+
+	var counts [Ace + 1]int
+	flush := true
+	for _, c := range cards {
+		counts[c.Rank]++
+		if c.Suit != cards[0].Suit {
+			flush = false
+		}
+	}
+
+	// order the cards by the size of their rank's group, then by rank, so
+	// the tie breaking ranks can be read off the front of each group.
+	sort.SliceStable(cards, func(i, j int) bool {
+		a, b := cards[i], cards[j]
+		if counts[a.Rank] != counts[b.Rank] {
+			return counts[a.Rank] > counts[b.Rank]
+		}
+		return a.Rank > b.Rank
+	})
+
+	var ranks []Rank
+	for i, c := range cards {
+		if i == 0 || c.Rank != cards[i-1].Rank {
+			ranks = append(ranks, c.Rank)
+		}
+	}
+
+	if len(ranks) == 5 {
+		high, straight := straightHigh(ranks)
+		if straight && high == Five {
+			// the ace plays low in a wheel, A-2-3-4-5.
+			cards = append(cards[1:], cards[0])
+		}
+		switch {
+		case straight && flush:
+			return HandValue{StraightFlush, []Rank{high}, cards}
+		case flush:
+			return HandValue{Flush, ranks, cards}
+		case straight:
+			return HandValue{Straight, []Rank{high}, cards}
+		}
+		return HandValue{HighCard, ranks, cards}
+	}
+
+	switch largest := counts[cards[0].Rank]; {
+	case largest == 4:
+		return HandValue{FourOfAKind, ranks, cards}
+	case largest == 3 && len(ranks) == 2:
+		return HandValue{FullHouse, ranks, cards}
+	case largest == 3:
+		return HandValue{ThreeOfAKind, ranks, cards}
+	case len(ranks) == 3:
+		return HandValue{TwoPair, ranks, cards}
+	}
+	return HandValue{OnePair, ranks, cards}
+}
+
+// straightHigh reports whether five distinct ranks, highest first, make a
+// straight and the rank of its top card.
+func straightHigh(ranks []Rank) (Rank, bool) {
+	if ranks[0]-ranks[4] == 4 {
+		return ranks[0], true
+	}
+	if ranks[0] == Ace && ranks[1] == Five {
+		return Five, true
+	}
+	return 0, false
+}
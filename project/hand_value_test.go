@@ -0,0 +1,162 @@
+package poker
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEvaluateHand(t *testing.T) {
+	cases := []struct {
+		name     string
+		cards    string
+		category HandCategory
+		ranks    []Rank
+		best     string
+	}{
+		{"royal flush", "As Ks Qs Js Ts 2d 3c", StraightFlush, []Rank{Ace}, "As Ks Qs Js Ts"},
+		{"straight flush", "9h 8h 7h 6h 5h Ah Kd", StraightFlush, []Rank{Nine}, "9h 8h 7h 6h 5h"},
+		{"steel wheel", "Ad 2d 3d 4d 5d Kd Qc", StraightFlush, []Rank{Five}, "5d 4d 3d 2d Ad"},
+		{"higher straight flush in seven suited cards", "4c 5c 6c 7c 8c 9c 3c", StraightFlush, []Rank{Nine}, "9c 8c 7c 6c 5c"},
+		{"four of a kind", "7s 7h 7d 7c Ks 2d 3c", FourOfAKind, []Rank{Seven, King}, "7s 7h 7d 7c Ks"},
+		{"four of a kind plays the best kicker", "7s 7h 7d 7c Ks Kd Ac", FourOfAKind, []Rank{Seven, Ace}, "7s 7h 7d 7c Ac"},
+		{"full house", "Js Jh Jd 4c 4s 2d 9c", FullHouse, []Rank{Jack, Four}, "Js Jh Jd 4c 4s"},
+		{"full house from two sets", "Js Jh Jd Qc Qs Qd 9c", FullHouse, []Rank{Queen, Jack}, "Qc Qs Qd Js Jh"},
+		{"full house with the higher of two pairs", "3s 3h 3d 9c 9s Kd Kc", FullHouse, []Rank{Three, King}, "3s 3h 3d Kd Kc"},
+		{"flush", "Ah Jh 8h 4h 2h Kd Qc", Flush, []Rank{Ace, Jack, Eight, Four, Two}, "Ah Jh 8h 4h 2h"},
+		{"flush plays the best five of its suit", "Ah Jh 8h 4h 2h 3h Qc", Flush, []Rank{Ace, Jack, Eight, Four, Three}, "Ah Jh 8h 4h 3h"},
+		{"flush beats a straight", "Ah Jh 8h 4h 2h 3c 5d", Flush, []Rank{Ace, Jack, Eight, Four, Two}, "Ah Jh 8h 4h 2h"},
+		{"straight", "9s 8h 7d 6c 5s 2d 2c", Straight, []Rank{Nine}, "9s 8h 7d 6c 5s"},
+		{"broadway", "As Kh Qd Jc Ts 2d 3c", Straight, []Rank{Ace}, "As Kh Qd Jc Ts"},
+		{"wheel", "As 2h 3d 4c 5s Kd 9c", Straight, []Rank{Five}, "5s 4c 3d 2h As"},
+		{"six high beats the wheel", "As 2h 3d 4c 5s 6d 9c", Straight, []Rank{Six}, "6d 5s 4c 3d 2h"},
+		{"no straight around the corner", "Qs Kh Ad 2c 3s 8d 9c", HighCard, []Rank{Ace, King, Queen, Nine, Eight}, "Ad Kh Qs 9c 8d"},
+		{"three of a kind", "8s 8h 8d Ac Ks 2d 4c", ThreeOfAKind, []Rank{Eight, Ace, King}, "8s 8h 8d Ac Ks"},
+		{"two pair", "Ks Kh 6d 6c Qs 2d 4c", TwoPair, []Rank{King, Six, Queen}, "Ks Kh 6d 6c Qs"},
+		{"two pair from three pairs", "Ks Kh 6d 6c 9s 9d 4c", TwoPair, []Rank{King, Nine, Six}, "Ks Kh 9s 9d 6d"},
+		{"two pair from three pairs with a higher single card", "Ks Kh 6d 6c 4s 4d Ac", TwoPair, []Rank{King, Six, Ace}, "Ks Kh 6d 6c Ac"},
+		{"one pair", "Ts Th Ad 8c 6s 3d 2c", OnePair, []Rank{Ten, Ace, Eight, Six}, "Ts Th Ad 8c 6s"},
+		{"high card", "As Jh 9d 7c 5s 3d 2c", HighCard, []Rank{Ace, Jack, Nine, Seven, Five}, "As Jh 9d 7c 5s"},
+		{"five cards", "As Jh 9d 7c 5s", HighCard, []Rank{Ace, Jack, Nine, Seven, Five}, "As Jh 9d 7c 5s"},
+		{"six cards", "As Ah 9d 7c 5s 4s", OnePair, []Rank{Ace, Nine, Seven, Five}, "As Ah 9d 7c 5s"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := EvaluateHand(mustParseCards(t, c.cards))
+			assertNoError(t, err)
+
+			if got.Category != c.category {
+				t.Errorf("got %v want %v", got.Category, c.category)
+			}
+			if !reflect.DeepEqual(got.Ranks, c.ranks) {
+				t.Errorf("got ranks %v want %v", got.Ranks, c.ranks)
+			}
+			if best := mustParseCards(t, c.best); !reflect.DeepEqual(got.Cards, best) {
+				t.Errorf("got best five %v want %v", got.Cards, best)
+			}
+		})
+	}
+
+	t.Run("rejects the wrong number of cards", func(t *testing.T) {
+		for _, cards := range []string{"As Ks Qs Js", "As Ks Qs Js Ts 9s 8s 7s"} {
+			if _, err := EvaluateHand(mustParseCards(t, cards)); err == nil {
+				t.Errorf("expected an error evaluating %q", cards)
+			}
+		}
+	})
+
+	t.Run("rejects repeated cards", func(t *testing.T) {
+		if _, err := EvaluateHand(mustParseCards(t, "As As Qs Js Ts")); err == nil {
+			t.Error("expected an error evaluating a repeated card")
+		}
+	})
+}
+
+func TestHandValueCompare(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{"straight flush beats four of a kind", "9h 8h 7h 6h 5h", "As Ah Ad Ac Ks", 1},
+		{"four of a kind beats a full house", "2s 2h 2d 2c 3s", "As Ah Ad Kc Ks", 1},
+		{"full house beats a flush", "2s 2h 2d 3c 3s", "As Ks Qs Js 9s", 1},
+		{"flush beats a straight", "7s 5s 4s 3s 2s", "As Kh Qd Jc Ts", 1},
+		{"straight beats three of a kind", "5s 4h 3d 2c As", "Ks Kh Kd Qc Js", 1},
+		{"three of a kind beats two pair", "2s 2h 2d 4c 3s", "As Ah Kd Kc Qs", 1},
+		{"two pair beats one pair", "3s 3h 2d 2c 4s", "As Ah Kd Qc Js", 1},
+		{"one pair beats high card", "2s 2h 3d 4c 5s", "As Kh Qd Jc 9s", 1},
+		{"higher straight flush wins", "Ts 9s 8s 7s 6s", "9h 8h 7h 6h 5h", 1},
+		{"higher quads win", "3s 3h 3d 3c 2s", "2s 2h 2d 2c As", 1},
+		{"quads kicker decides", "9s 9h 9d 9c As", "9s 9h 9d 9c Ks", 1},
+		{"full house trips decide before the pair", "3s 3h 3d 2c 2s", "2s 2h 2d Ac As", 1},
+		{"full house pair decides", "Ks Kh Kd 3c 3s", "Ks Kh Kd 2c 2s", 1},
+		{"flush decided by the last card", "As Ks 9s 6s 4s", "Ah Kh 9h 6h 3h", 1},
+		{"wheel loses to six high", "As 2h 3d 4c 5s", "2s 3h 4d 5c 6s", -1},
+		{"trips kicker decides", "7s 7h 7d Ac 2s", "7s 7h 7d Kc Qs", 1},
+		{"second kicker decides trips", "7s 7h 7d Ac 3s", "7s 7h 7d Ac 2s", 1},
+		{"top pair of two pair decides", "As Ah 2d 2c 3s", "Ks Kh Qd Qc Js", 1},
+		{"bottom pair of two pair decides", "As Ah 3d 3c 2s", "As Ah 2d 2c Ks", 1},
+		{"two pair kicker decides", "As Ah 3d 3c Ks", "As Ah 3d 3c Qs", 1},
+		{"higher pair wins", "3s 3h 2d 4c 5s", "2s 2h Ad Kc Qs", 1},
+		{"pair third kicker decides", "Ts Th Ad 8c 6s", "Ts Th Ad 8c 5s", 1},
+		{"high card last card decides", "As Jh 9d 7c 5s", "As Jh 9d 7c 4s", 1},
+		{"suits never matter", "As Jh 9d 7c 5s", "Ah Jd 9c 7s 5h", 0},
+		{"identical straights split", "9s 8h 7d 6c 5s", "9h 8d 7c 6s 5h", 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a, err := EvaluateHand(mustParseCards(t, c.a))
+			assertNoError(t, err)
+			b, err := EvaluateHand(mustParseCards(t, c.b))
+			assertNoError(t, err)
+
+			if got := a.Compare(b); got != c.want {
+				t.Errorf("%v compared to %v got %d want %d", a, b, got, c.want)
+			}
+			if got := b.Compare(a); got != -c.want {
+				t.Errorf("%v compared to %v got %d want %d", b, a, got, -c.want)
+			}
+		})
+	}
+}
+
+// TestEvaluateEveryFiveCardHand checks the evaluator against the well known
+// number of each category among all 2,598,960 five card hands.
+func TestEvaluateEveryFiveCardHand(t *testing.T) {
+	if testing.Short() {
+		t.Skip("evaluates every five card hand")
+	}
+
+	want := map[HandCategory]int{
+		StraightFlush: 40,
+		FourOfAKind:   624,
+		FullHouse:     3744,
+		Flush:         5108,
+		Straight:      10200,
+		ThreeOfAKind:  54912,
+		TwoPair:       123552,
+		OnePair:       1098240,
+		HighCard:      1302540,
+	}
+
+	got := map[HandCategory]int{}
+	five := make([]Card, 5)
+	forEachFive(NewDeck().cards, five, 0, 0, func() {
+		got[evaluateFive(five).Category]++
+	})
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
+
+func mustParseCards(t testing.TB, s string) []Card {
+	t.Helper()
+	cards, err := ParseCards(s)
+	if err != nil {
+		t.Fatalf("could not parse cards %q, %v", s, err)
+	}
+	return cards
+}
@@ -4,32 +4,130 @@ import (
 	"sync"
 )
 
-// TODO: make it concurrent safe
+// InMemoryPlayerStore keeps players and games in memory, it is safe for concurrent use.
 type InMemoryPlayerStore struct {
-	mu    sync.RWMutex
-	store map[string]int
+	mu           sync.RWMutex
+	profiles     map[string]Profile
+	store        map[string]int
+	chips        map[string]int
+	games        []GameRecord
+	transactions []ChipTransaction
 }
 
-func (i *InMemoryPlayerStore) GetLeague() League {
-	var league []Player
-	for name, wins := range i.store {
-		league = append(league, Player{name, wins})
+func (i *InMemoryPlayerStore) GetLeague() (League, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	league := League{}
+	for name, profile := range i.profiles {
+		league = append(league, Player{Name: name, Wins: i.store[name], Chips: i.chips[name], Profile: profile})
 	}
-	return league
+	league.Sort()
+	return league, nil
 }
 
 func NewInMemoryPlayerStore() *InMemoryPlayerStore {
-	return &InMemoryPlayerStore{sync.RWMutex{}, map[string]int{}}
+	return &InMemoryPlayerStore{profiles: map[string]Profile{}, store: map[string]int{}, chips: map[string]int{}}
+}
+
+func (i *InMemoryPlayerStore) RegisterPlayer(name string, profile Profile) error {
+	if err := ValidatePlayerName(name); err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if _, ok := i.profiles[name]; ok {
+		return &PlayerError{name, ErrPlayerExists}
+	}
+	i.profiles[name] = profile
+	return nil
+}
+
+func (i *InMemoryPlayerStore) UpdatePlayer(name string, profile Profile) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if _, ok := i.profiles[name]; !ok {
+		return &PlayerError{name, ErrPlayerNotFound}
+	}
+	i.profiles[name] = profile
+	return nil
+}
+
+func (i *InMemoryPlayerStore) DeletePlayer(name string) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if _, ok := i.profiles[name]; !ok {
+		return &PlayerError{name, ErrPlayerNotFound}
+	}
+	delete(i.profiles, name)
+	delete(i.store, name)
+	delete(i.chips, name)
+	return nil
+}
+
+func (i *InMemoryPlayerStore) GetPlayer(name string) (Player, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	profile, ok := i.profiles[name]
+	if !ok {
+		return Player{}, &PlayerError{name, ErrPlayerNotFound}
+	}
+	return Player{Name: name, Wins: i.store[name], Chips: i.chips[name], Profile: profile}, nil
 }
 
-func (i *InMemoryPlayerStore) RecordWin(name string) {
+func (i *InMemoryPlayerStore) RecordWin(name string) error {
 	i.mu.Lock()
 	defer i.mu.Unlock()
+	if _, ok := i.profiles[name]; !ok {
+		return &PlayerError{name, ErrPlayerNotFound}
+	}
 	i.store[name]++
+	return nil
+}
+
+func (i *InMemoryPlayerStore) GetPlayerScore(name string) (int, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return i.store[name], nil
+}
+
+func (i *InMemoryPlayerStore) RecordGame(game GameRecord) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.games = append(i.games, game)
+	return nil
+}
+
+func (i *InMemoryPlayerStore) GetGames() ([]GameRecord, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return append([]GameRecord{}, i.games...), nil
+}
+
+func (i *InMemoryPlayerStore) GetPlayerGames(name string) ([]GameRecord, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+	return playerGames(i.games, name), nil
+}
+
+func (i *InMemoryPlayerStore) RecordChips(transaction ChipTransaction) error {
+	if err := transaction.Validate(); err != nil {
+		return err
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if _, ok := i.profiles[transaction.Player]; !ok {
+		return &PlayerError{transaction.Player, ErrPlayerNotFound}
+	}
+	i.transactions = append(i.transactions, transaction)
+	i.chips[transaction.Player] += transaction.Net()
+	return nil
 }
 
-func (i *InMemoryPlayerStore) GetPlayerScore(name string) int {
+func (i *InMemoryPlayerStore) GetChipTransactions(name string) ([]ChipTransaction, error) {
 	i.mu.RLock()
 	defer i.mu.RUnlock()
-	return i.store[name]
+	return playerChipTransactions(i.transactions, name), nil
 }
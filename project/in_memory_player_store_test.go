@@ -11,6 +11,10 @@ func TestInMemoryPlayerStoreConcurrently(t *testing.T) {
 		memoryPlayerStore := NewInMemoryPlayerStore()
 		wantedStore := 1000
 
+		for i := 0; i < wantedStore; i++ {
+			assertNoError(t, memoryPlayerStore.RegisterPlayer(fmt.Sprint(i), Profile{}))
+		}
+
 		var wg sync.WaitGroup
 		wg.Add(wantedStore)
 
@@ -34,7 +38,8 @@ func TestInMemoryPlayerStoreConcurrently(t *testing.T) {
 		wantedStore := 1000
 
 		for i := 0; i < wantedStore; i++ {
-			memoryPlayerStore.RecordWin(fmt.Sprint(i))
+			assertNoError(t, memoryPlayerStore.RegisterPlayer(fmt.Sprint(i), Profile{}))
+			assertNoError(t, memoryPlayerStore.RecordWin(fmt.Sprint(i)))
 		}
 
 		var wg sync.WaitGroup
@@ -43,7 +48,7 @@ func TestInMemoryPlayerStoreConcurrently(t *testing.T) {
 		for i := 0; i < wantedStore; i++ {
 			i := i
 			go func() {
-				memoryPlayerStore.GetPlayerScore(fmt.Sprint(i))
+				_, _ = memoryPlayerStore.GetPlayerScore(fmt.Sprint(i))
 				wg.Done()
 			}()
 		}
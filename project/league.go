@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 )
 
 // League ..
@@ -28,3 +29,113 @@ func (l League) Find(name string) *Player {
 	}
 	return nil
 }
+
+// Sort orders the league by wins, most first, and players with the same wins by name.
+func (l League) Sort() {
+	sort.Slice(l, func(i, j int) bool {
+		if l[i].Wins != l[j].Wins {
+			return l[i].Wins > l[j].Wins
+		}
+		return l[i].Name < l[j].Name
+	})
+}
+
+// SortByName orders the league by name, A to Z.
+func (l League) SortByName() {
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].Name < l[j].Name
+	})
+}
+
+// Reverse turns the order of the league around.
+func (l League) Reverse() {
+	for i, j := 0, len(l)-1; i < j; i, j = i+1, j-1 {
+		l[i], l[j] = l[j], l[i]
+	}
+}
+
+// SortByChips orders the league by net chips, most first, then as Sort does.
+func (l League) SortByChips() {
+	sort.Slice(l, func(i, j int) bool {
+		if l[i].Chips != l[j].Chips {
+			return l[i].Chips > l[j].Chips
+		}
+		if l[i].Wins != l[j].Wins {
+			return l[i].Wins > l[j].Wins
+		}
+		return l[i].Name < l[j].Name
+	})
+}
+
+// SortByRating orders the league by rating, highest first, then as Sort does.
+func (l League) SortByRating() {
+	sort.Slice(l, func(i, j int) bool {
+		if l[i].Rating != l[j].Rating {
+			return l[i].Rating > l[j].Rating
+		}
+		if l[i].Wins != l[j].Wins {
+			return l[i].Wins > l[j].Wins
+		}
+		return l[i].Name < l[j].Name
+	})
+}
+
+// addChips adds the net chips of transaction to its player.
+func (l League) addChips(transaction ChipTransaction) error {
+	player := l.Find(transaction.Player)
+	if player == nil {
+		return &PlayerError{transaction.Player, ErrPlayerNotFound}
+	}
+	player.Chips += transaction.Net()
+	return nil
+}
+
+// register adds name to the league with profile, unless they are already in it.
+func (l League) register(name string, profile Profile) (League, error) {
+	if err := ValidatePlayerName(name); err != nil {
+		return l, err
+	}
+	if l.Find(name) != nil {
+		return l, &PlayerError{name, ErrPlayerExists}
+	}
+	return append(l, Player{Name: name, Profile: profile}), nil
+}
+
+// update replaces the profile of name.
+func (l League) update(name string, profile Profile) error {
+	player := l.Find(name)
+	if player == nil {
+		return &PlayerError{name, ErrPlayerNotFound}
+	}
+	player.Profile = profile
+	return nil
+}
+
+// remove takes name off the league.
+func (l League) remove(name string) (League, error) {
+	for i, p := range l {
+		if p.Name == name {
+			return append(l[:i:i], l[i+1:]...), nil
+		}
+	}
+	return l, &PlayerError{name, ErrPlayerNotFound}
+}
+
+// addWin gives name a win.
+func (l League) addWin(name string) error {
+	player := l.Find(name)
+	if player == nil {
+		return &PlayerError{name, ErrPlayerNotFound}
+	}
+	player.Wins++
+	return nil
+}
+
+// get returns a copy of name's entry.
+func (l League) get(name string) (Player, error) {
+	player := l.Find(name)
+	if player == nil {
+		return Player{}, &PlayerError{name, ErrPlayerNotFound}
+	}
+	return *player, nil
+}
@@ -0,0 +1,164 @@
+package poker
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// The formats a league can be exported in.
+const (
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+)
+
+// LeagueFormats are the formats a league can be exported in, by the content type of each.
+var LeagueFormats = map[string]string{
+	FormatJSON:     jsonContentType,
+	FormatCSV:      "text/csv; charset=utf-8",
+	FormatHTML:     "text/html; charset=utf-8",
+	FormatMarkdown: "text/markdown; charset=utf-8",
+}
+
+// ErrUnknownFormat is returned for a league format that isn't one of LeagueFormats.
+var ErrUnknownFormat = fmt.Errorf("unknown league format, use %s, %s, %s or %s", FormatJSON, FormatCSV, FormatHTML, FormatMarkdown)
+
+// leagueColumns head the columns of the tables a league is exported as.
+var leagueColumns = []string{"Rank", "Name", "Wins", "Chips", "Rating", "Display name", "Nickname"}
+
+// leagueHTMLTemplate is the page a league is exported as in FormatHTML,
+// built into the binary so export works from any directory.
+//
+//go:embed league.html
+var leagueHTMLTemplate string
+
+var leagueHTML = template.Must(template.New("league").Parse(leagueHTMLTemplate))
+
+// WriteLeague writes league to w in format, one of LeagueFormats. The tables
+// are ranked in the order of league.
+func WriteLeague(w io.Writer, league League, format string) error {
+	return WriteLeaguePage(w, league, 0, format)
+}
+
+// WriteLeaguePage writes a page of the league to w in format, the first
+// player on it ranked offset+1.
+func WriteLeaguePage(w io.Writer, league League, offset int, format string) error {
+	switch format {
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(league)
+	case FormatCSV:
+		out := csv.NewWriter(w)
+		if err := out.Write(leagueColumns); err != nil {
+			return err
+		}
+		if err := out.WriteAll(leagueRows(league, offset)); err != nil {
+			return err
+		}
+		return out.Error()
+	case FormatHTML:
+		return leagueHTML.Execute(w, struct {
+			Columns []string
+			Rows    [][]string
+		}{leagueColumns, leagueRows(league, offset)})
+	case FormatMarkdown:
+		return writeMarkdownTable(w, leagueColumns, leagueRows(league, offset))
+	}
+	return fmt.Errorf("%w, got %q", ErrUnknownFormat, format)
+}
+
+// leagueRows are the rows of the table league is exported as, the first
+// player ranked offset+1.
+func leagueRows(league League, offset int) [][]string {
+	rows := make([][]string, 0, len(league))
+	for i, player := range league {
+		rating := ""
+		if player.Rating != 0 {
+			rating = strconv.FormatFloat(player.Rating, 'f', -1, 64)
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(offset + i + 1),
+			player.Name,
+			strconv.Itoa(player.Wins),
+			strconv.Itoa(player.Chips),
+			rating,
+			player.DisplayName,
+			player.Nickname,
+		})
+	}
+	return rows
+}
+
+func writeMarkdownTable(w io.Writer, columns []string, rows [][]string) error {
+	line := func(cells []string) string {
+		escaped := make([]string, len(cells))
+		for i, cell := range cells {
+			escaped[i] = strings.ReplaceAll(cell, "|", `\|`)
+		}
+		return "| " + strings.Join(escaped, " | ") + " |\n"
+	}
+	rule := make([]string, len(columns))
+	for i := range rule {
+		rule[i] = "---"
+	}
+
+	table := line(columns) + line(rule)
+	for _, row := range rows {
+		table += line(row)
+	}
+	_, err := io.WriteString(w, table)
+	return err
+}
+
+// NegotiateLeagueFormat picks the format to send a league in from an Accept
+// header, preferring the media types the client likes most. An empty header
+// gets JSON and false is returned when no format is acceptable.
+func NegotiateLeagueFormat(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return FormatJSON, true
+	}
+
+	type mediaRange struct {
+		mediaType string
+		q         float64
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, mediaRange{mediaType, q})
+		}
+	}
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+
+	for _, r := range ranges {
+		switch r.mediaType {
+		case "*/*", "application/*", "application/json":
+			return FormatJSON, true
+		case "text/*", "text/html":
+			return FormatHTML, true
+		case "text/csv":
+			return FormatCSV, true
+		case "text/markdown":
+			return FormatMarkdown, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,90 @@
+package poker
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWriteLeague(t *testing.T) {
+	league := League{
+		{Name: "Cleo", Wins: 32, Chips: 150, Rating: 1516.5, Profile: Profile{DisplayName: "Cleo <3"}},
+		{Name: "Chris", Wins: 20, Profile: Profile{Nickname: "a|b"}},
+	}
+
+	cases := map[string]string{
+		FormatJSON: `[{"Name":"Cleo","Wins":32,"Chips":150,"Rating":1516.5,"DisplayName":"Cleo \u003c3"},{"Name":"Chris","Wins":20,"Chips":0,"Nickname":"a|b"}]` + "\n",
+		FormatCSV: "Rank,Name,Wins,Chips,Rating,Display name,Nickname\n" +
+			"1,Cleo,32,150,1516.5,Cleo <3,\n" +
+			"2,Chris,20,0,,,a|b\n",
+		FormatMarkdown: "| Rank | Name | Wins | Chips | Rating | Display name | Nickname |\n" +
+			"| --- | --- | --- | --- | --- | --- | --- |\n" +
+			"| 1 | Cleo | 32 | 150 | 1516.5 | Cleo <3 |  |\n" +
+			"| 2 | Chris | 20 | 0 |  |  | a\\|b |\n",
+	}
+	for format, want := range cases {
+		t.Run(format, func(t *testing.T) {
+			var got bytes.Buffer
+			assertNoError(t, WriteLeague(&got, league, format))
+			if got.String() != want {
+				t.Errorf("got\n%s\nwant\n%s", got.String(), want)
+			}
+		})
+	}
+
+	t.Run(FormatHTML, func(t *testing.T) {
+		var got bytes.Buffer
+		assertNoError(t, WriteLeague(&got, league, FormatHTML))
+
+		for _, want := range []string{"<th>Display name</th>", "<td>Cleo &lt;3</td>", "<td>1516.5</td>", "<td>a|b</td>"} {
+			if !strings.Contains(got.String(), want) {
+				t.Errorf("expected %q in\n%s", want, got.String())
+			}
+		}
+	})
+
+	t.Run("pages are ranked from their offset", func(t *testing.T) {
+		var csv, html bytes.Buffer
+		assertNoError(t, WriteLeaguePage(&csv, league[1:], 20, FormatCSV))
+		assertNoError(t, WriteLeaguePage(&html, league[1:], 20, FormatHTML))
+
+		if want := "21,Chris,20,0,,,a|b\n"; !strings.HasSuffix(csv.String(), want) {
+			t.Errorf("got\n%s\nwant it to end %q", csv.String(), want)
+		}
+		if want := "<td>21</td>"; !strings.Contains(html.String(), want) {
+			t.Errorf("expected %q in\n%s", want, html.String())
+		}
+	})
+
+	t.Run("unknown formats are an error", func(t *testing.T) {
+		if err := WriteLeague(&bytes.Buffer{}, league, "xml"); !errors.Is(err, ErrUnknownFormat) {
+			t.Errorf("got %v want %v", err, ErrUnknownFormat)
+		}
+	})
+}
+
+func TestNegotiateLeagueFormat(t *testing.T) {
+	cases := map[string]string{
+		"":                 FormatJSON,
+		"application/json": FormatJSON,
+		"*/*":              FormatJSON,
+		"text/csv":         FormatCSV,
+		"text/html,application/xhtml+xml,*/*;q=0.8": FormatHTML,
+		"text/markdown; charset=utf-8":              FormatMarkdown,
+		"application/json;q=0.5, text/csv":          FormatCSV,
+		"image/png, text/*;q=0.1":                   FormatHTML,
+	}
+	for accept, want := range cases {
+		got, ok := NegotiateLeagueFormat(accept)
+		if !ok || got != want {
+			t.Errorf("got %q, %v for %q want %q", got, ok, accept, want)
+		}
+	}
+
+	for _, accept := range []string{"image/png", "text/csv;q=0"} {
+		if got, ok := NegotiateLeagueFormat(accept); ok {
+			t.Errorf("got %q for %q want nothing acceptable", got, accept)
+		}
+	}
+}
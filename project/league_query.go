@@ -0,0 +1,138 @@
+package poker
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// MaxLeagueLimit is the most players a page of the league can have.
+const MaxLeagueLimit = 100
+
+// leagueQuery is how /league was asked to filter, rank and page the league.
+type leagueQuery struct {
+	season string
+	// sort is wins, name, chips or rating, ranking names A to Z and
+	// everything else highest first unless ascending says otherwise.
+	sort      string
+	ascending bool
+	prefix    string
+	offset    int
+	// limit is the size of a page, 0 for the whole league.
+	limit int
+}
+
+// parseLeagueQuery reads ?season=&sort=&order=&q=&offset=&limit=.
+func parseLeagueQuery(values url.Values) (leagueQuery, error) {
+	q := leagueQuery{
+		season: values.Get("season"),
+		sort:   values.Get("sort"),
+		prefix: values.Get("q"),
+	}
+
+	switch q.sort {
+	case "":
+		q.sort = "wins"
+	case "wins", "name", "chips", "rating":
+	default:
+		return q, fmt.Errorf("unknown league sort %q", q.sort)
+	}
+	if q.season != "" && q.sort != "wins" && q.sort != "name" {
+		return q, fmt.Errorf("season standings are only ranked by wins or name")
+	}
+
+	switch order := values.Get("order"); order {
+	case "":
+		q.ascending = q.sort == "name"
+	case "asc", "desc":
+		q.ascending = order == "asc"
+	default:
+		return q, fmt.Errorf("unknown league order %q, use asc or desc", order)
+	}
+
+	var err error
+	if q.offset, err = queryInt(values, "offset", 0); err != nil {
+		return q, err
+	}
+	if q.limit, err = queryInt(values, "limit", 1); err != nil {
+		return q, err
+	}
+	if q.limit > MaxLeagueLimit {
+		return q, fmt.Errorf("limit can't be more than %d", MaxLeagueLimit)
+	}
+	return q, nil
+}
+
+// queryInt reads the whole number key from values, 0 when it isn't given.
+func queryInt(values url.Values, key string, least int) (int, error) {
+	value := values.Get(key)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < least {
+		return 0, fmt.Errorf("%s should be a whole number of at least %d, got %q", key, least, value)
+	}
+	return n, nil
+}
+
+// page filters and ranks league and cuts out the page asked for, total is
+// how many players matched the filter.
+func (q leagueQuery) page(league League) (page League, total int) {
+	matched := League{}
+	for _, player := range league {
+		if strings.HasPrefix(strings.ToLower(player.Name), strings.ToLower(q.prefix)) {
+			matched = append(matched, player)
+		}
+	}
+
+	switch q.sort {
+	case "wins":
+		matched.Sort()
+	case "name":
+		matched.SortByName()
+	case "chips":
+		matched.SortByChips()
+	case "rating":
+		matched.SortByRating()
+	}
+	if q.ascending != (q.sort == "name") {
+		matched.Reverse()
+	}
+
+	total = len(matched)
+	start, end := min(q.offset, total), total
+	if q.limit > 0 {
+		end = min(start+q.limit, total)
+	}
+	return matched[start:end], total
+}
+
+// links are the Link header values for the pages either side of this one,
+// and the first and last, when the league is paged.
+func (q leagueQuery) links(u *url.URL, total int) []string {
+	if q.limit == 0 {
+		return nil
+	}
+
+	link := func(offset int, rel string) string {
+		query := u.Query()
+		query.Set("offset", strconv.Itoa(offset))
+		page := url.URL{Path: u.Path, RawQuery: query.Encode()}
+		return fmt.Sprintf(`<%s>; rel="%s"`, page.String(), rel)
+	}
+
+	last := 0
+	if total > 0 {
+		last = (total - 1) / q.limit * q.limit
+	}
+	links := []string{link(0, "first")}
+	if q.offset > 0 {
+		links = append(links, link(max(q.offset-q.limit, 0), "prev"))
+	}
+	if q.offset+q.limit < total {
+		links = append(links, link(q.offset+q.limit, "next"))
+	}
+	return append(links, link(last, "last"))
+}
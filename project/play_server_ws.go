@@ -1,38 +1,120 @@
 package poker
 
 import (
-	"github.com/gorilla/websocket"
-	"log"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Errors returned when a client bets for a player it didn't join as.
+var (
+	ErrNoSeat    = errors.New("join the game as a player, with ?player=Name, to act in it")
+	ErrWrongSeat = errors.New("clients can only act for the player they joined as")
 )
 
+// playerServerWS is a client's websocket connection. A client that joins
+// with ?player=Name sits in that seat, only sees its own hole cards and can
+// only act for that player.
 type playerServerWS struct {
 	*websocket.Conn
+	seat    string
+	writeMu sync.Mutex
 }
 
-func newPlayerServerWS(w http.ResponseWriter, r *http.Request) *playerServerWS {
+func newPlayerServerWS(w http.ResponseWriter, r *http.Request) (*playerServerWS, error) {
 	conn, err := wsUpgrader.Upgrade(w, r, nil)
 
 	if err != nil {
-		log.Printf("problem upgrading connection to WebSockets %v\n", err)
+		return nil, fmt.Errorf("problem upgrading connection to WebSockets %v", err)
 	}
-	return &playerServerWS{conn}
+	return &playerServerWS{Conn: conn, seat: r.URL.Query().Get("player")}, nil
 }
 
-func (w *playerServerWS) Write(p []byte) (n int, err error) {
-	err = w.WriteMessage(websocket.TextMessage, p)
+// Send writes msg as JSON, it is safe to call from the alerts' goroutines.
+func (w *playerServerWS) Send(msg WSMessage) error {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+	return w.WriteJSON(msg)
+}
 
-	if err != nil {
+// Write sends p as a text message.
+func (w *playerServerWS) Write(p []byte) (n int, err error) {
+	if err := w.Send(WSMessage{Type: WSText, Text: string(p)}); err != nil {
 		return 0, err
 	}
-
 	return len(p), nil
 }
 
-func (w *playerServerWS) WaitForMsg() string {
-	_, msg, err := w.ReadMessage()
-	if err != nil {
-		log.Printf("error reading from websocket %v\n", err)
+// AnnounceStarted sends a started message with the ID of the game the client started.
+func (w *playerServerWS) AnnounceStarted(id string) error {
+	return w.Send(WSMessage{Type: WSStarted, Game: id})
+}
+
+// AnnounceBlind sends a blind message.
+func (w *playerServerWS) AnnounceBlind(amount int) error {
+	return w.Send(WSMessage{Type: WSBlind, Amount: amount})
+}
+
+// AnnounceWarning sends a warning message, to the nearest second.
+func (w *playerServerWS) AnnounceWarning(amount int, in time.Duration) error {
+	return w.Send(WSMessage{Type: WSWarning, Amount: amount, Seconds: int(in.Round(time.Second) / time.Second)})
+}
+
+// AnnounceHand sends a hand message with the hand as the client's seat sees it.
+func (w *playerServerWS) AnnounceHand(hand Hand) error {
+	view := hand.SeenBy(w.seat)
+	return w.Send(WSMessage{Type: WSHand, Hand: &view})
+}
+
+// AnnounceShowdown sends a results message.
+func (w *playerServerWS) AnnounceShowdown(results []HandResult, winners []string) error {
+	return w.Send(WSMessage{Type: WSResults, Results: results, Winners: winners})
+}
+
+// AnnounceBetting sends a betting message.
+func (w *playerServerWS) AnnounceBetting(state BettingState) error {
+	return w.Send(WSMessage{Type: WSBetting, Betting: &state})
+}
+
+// action is the betting action msg takes for the client's seat.
+func (w *playerServerWS) action(msg WSMessage) (Action, error) {
+	switch {
+	case w.seat == "":
+		return Action{}, ErrNoSeat
+	case msg.Player != "" && msg.Player != w.seat:
+		return Action{}, ErrWrongSeat
+	}
+	return Action{Player: w.seat, Type: msg.Action, Amount: msg.Amount}, nil
+}
+
+// SendError sends an error message.
+func (w *playerServerWS) SendError(err error) {
+	_ = w.Send(WSMessage{Type: WSError, Error: err.Error()})
+}
+
+// WaitForMsg blocks until the client sends a valid message of one of the
+// wanted types, replying with an error to anything else. It errors once the
+// client has gone away.
+func (w *playerServerWS) WaitForMsg(want ...string) (WSMessage, error) {
+	for {
+		_, data, err := w.ReadMessage()
+		if err != nil {
+			return WSMessage{}, fmt.Errorf("error reading from websocket %v", err)
+		}
+
+		msg, err := parseWSMessage(data)
+		if err == nil && !contains(want, msg.Type) {
+			err = fmt.Errorf("expected a %q message but got %q", strings.Join(want, `" or "`), msg.Type)
+		}
+		if err != nil {
+			w.SendError(err)
+			continue
+		}
+		return msg, nil
 	}
-	return string(msg)
 }
@@ -0,0 +1,64 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxPlayerNameLength is the longest a player's name can be, in characters.
+const MaxPlayerNameLength = 32
+
+// Reasons a player can't be registered, changed or given a win, wrapped in a PlayerError.
+var (
+	ErrPlayerNotFound    = errors.New("player is not registered")
+	ErrPlayerExists      = errors.New("player is already registered")
+	ErrInvalidPlayerName = errors.New("player names are 1 to 32 letters, digits, '-', '_', '.' or '''")
+)
+
+// PlayerError is returned for a player the store can't act on, Err is one of the reasons above.
+type PlayerError struct {
+	Name string
+	Err  error
+}
+
+func (e *PlayerError) Error() string {
+	return fmt.Sprintf("%q %v", e.Name, e.Err)
+}
+
+func (e *PlayerError) Unwrap() error {
+	return e.Err
+}
+
+// Profile is how a registered player is shown, all of it optional.
+// Avatar is the URL of a picture of them.
+type Profile struct {
+	DisplayName string `json:",omitempty"`
+	Nickname    string `json:",omitempty"`
+	Avatar      string `json:",omitempty"`
+}
+
+// PlayerRoster keeps the players registered to play. Only registered players
+// can be given wins.
+type PlayerRoster interface {
+	RegisterPlayer(name string, profile Profile) error
+	UpdatePlayer(name string, profile Profile) error
+	// DeletePlayer takes a player off the league, the games they played are kept.
+	DeletePlayer(name string) error
+	GetPlayer(name string) (Player, error)
+}
+
+// ValidatePlayerName checks name can be registered. Names are used in URLs
+// and typed in CLI commands, so they can't have spaces or slashes.
+func ValidatePlayerName(name string) error {
+	if name == "" || utf8.RuneCountInString(name) > MaxPlayerNameLength {
+		return &PlayerError{name, ErrInvalidPlayerName}
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' && r != '.' && r != '\'' {
+			return &PlayerError{name, ErrInvalidPlayerName}
+		}
+	}
+	return nil
+}
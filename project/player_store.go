@@ -0,0 +1,31 @@
+package poker
+
+import "strings"
+
+// OpenPlayerStore opens the store described by dsn, "sqlite:game.db" opens a
+// SQLite database, "events:game.events" an event log and anything else,
+// e.g. "game.db.json", a JSON database file.
+// The options only apply to JSON database files.
+func OpenPlayerStore(dsn string, options ...FileSystemOption) (PlayerStore, func(), error) {
+	if path := strings.TrimPrefix(dsn, "sqlite:"); path != dsn {
+		store, closeFunc, err := SQLitePlayerStoreFromFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, closeFunc, nil
+	}
+
+	if path := strings.TrimPrefix(dsn, "events:"); path != dsn {
+		store, closeFunc, err := EventLogPlayerStoreFromFile(path, DefaultSnapshotEvery)
+		if err != nil {
+			return nil, nil, err
+		}
+		return store, closeFunc, nil
+	}
+
+	store, closeFunc, err := FileSystemPlayerStoreFromFile(strings.TrimPrefix(dsn, "file:"), options...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return store, closeFunc, nil
+}
@@ -0,0 +1,121 @@
+package poker
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrBettingNotOver is returned when settling a hand that is still being bet on.
+var ErrBettingNotOver = errors.New("betting is not over for this hand")
+
+// Pot is chips that can only be won by the players eligible for it. The main
+// pot comes first, followed by a side pot for every player all in for less
+// than the players still betting.
+type Pot struct {
+	Amount   int      `json:"amount"`
+	Eligible []string `json:"eligible"`
+}
+
+// Pots returns the main pot and any side pots.
+func (b *Betting) Pots() []Pot {
+	var levels []int
+	for _, seat := range b.seats {
+		if !seat.Folded && seat.Committed > 0 {
+			levels = append(levels, seat.Committed)
+		}
+	}
+	sort.Ints(levels)
+
+	var pots []Pot
+	previous := 0
+	for _, level := range levels {
+		if level == previous {
+			continue
+		}
+
+		pot := Pot{}
+		for _, seat := range b.seats {
+			pot.Amount += min(seat.Committed, level) - min(seat.Committed, previous)
+			if !seat.Folded && seat.Committed >= level {
+				pot.Eligible = append(pot.Eligible, seat.Player)
+			}
+		}
+		previous = level
+		pots = append(pots, pot)
+	}
+
+	// chips folded above the biggest stake still in the hand go to the last pot.
+	for _, seat := range b.seats {
+		if extra := seat.Committed - previous; extra > 0 && len(pots) > 0 {
+			pots[len(pots)-1].Amount += extra
+		}
+	}
+	return pots
+}
+
+// Settle pays out every pot to the best hands among the players eligible for
+// it, given their showdown results. A pot only one player is eligible for is
+// theirs without a showdown. A split pot's odd chips go to the winners
+// closest to the left of the button. It returns what each player won.
+func (b *Betting) Settle(results []HandResult) (map[string]int, error) {
+	if !b.Over() {
+		return nil, ErrBettingNotOver
+	}
+	if b.settled {
+		return nil, errors.New("the hand has already been settled")
+	}
+
+	hands := map[string]HandResult{}
+	for _, result := range results {
+		hands[result.Player] = result
+	}
+
+	won := map[string]int{}
+	for _, pot := range b.Pots() {
+		winners := pot.Eligible
+		if len(pot.Eligible) > 1 {
+			var contenders []HandResult
+			for _, player := range pot.Eligible {
+				result, ok := hands[player]
+				if !ok {
+					return nil, fmt.Errorf("no showdown result for %q", player)
+				}
+				contenders = append(contenders, result)
+			}
+			winners = Winners(contenders)
+		}
+
+		share, oddChips := pot.Amount/len(winners), pot.Amount%len(winners)
+		for _, player := range b.leftOfButton(winners) {
+			won[player] += share
+			if oddChips > 0 {
+				won[player]++
+				oddChips--
+			}
+		}
+	}
+
+	for i := range b.seats {
+		b.seats[i].Stack += won[b.seats[i].Player]
+		b.seats[i].Bet, b.seats[i].Committed = 0, 0
+	}
+	b.settled = true
+	return won, nil
+}
+
+// leftOfButton orders players by seat, starting with the first seat left of the button.
+func (b *Betting) leftOfButton(players []string) []string {
+	in := map[string]bool{}
+	for _, player := range players {
+		in[player] = true
+	}
+
+	var ordered []string
+	for i, n := b.next(b.button), 0; n < len(b.seats); i, n = b.next(i), n+1 {
+		if in[b.seats[i].Player] {
+			ordered = append(ordered, b.seats[i].Player)
+		}
+	}
+	return ordered
+}
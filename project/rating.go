@@ -0,0 +1,250 @@
+package poker
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNotRated is returned for ratings asked of a store that doesn't rate its players.
+var ErrNotRated = errors.New("this league's players aren't rated")
+
+// InitialRating is the rating of a player who hasn't played a rated game.
+const InitialRating = 1500
+
+// RatingSystem rates players on the results of the games they play.
+type RatingSystem interface {
+	// Rate returns how much the rating of each player in game changes,
+	// given the ratings they went into it with.
+	Rate(game GameRecord, ratings map[string]float64) map[string]float64
+}
+
+// Elo rates players with the Elo system. Heads-up games are rated as plain
+// Elo, tournaments as if every player had played each of the others, beating
+// those they finished ahead of, with K shared out over their opponents.
+type Elo struct {
+	K float64
+}
+
+// DefaultElo is Elo with the K factor used by most chess federations.
+var DefaultElo = Elo{K: 32}
+
+// Rate ..
+func (e Elo) Rate(game GameRecord, ratings map[string]float64) map[string]float64 {
+	players := ratedPlayers(game)
+	changes := make(map[string]float64, len(players))
+	if len(players) < 2 {
+		return changes
+	}
+
+	k := e.K / float64(len(players)-1)
+	for _, player := range players {
+		for _, opponent := range players {
+			if player.name == opponent.name {
+				continue
+			}
+			expected := 1 / (1 + math.Pow(10, (ratings[opponent.name]-ratings[player.name])/400))
+			changes[player.name] += k * (player.score(opponent) - expected)
+		}
+	}
+	return changes
+}
+
+type ratedPlayer struct {
+	name  string
+	place int
+}
+
+// score is how player did against opponent, 1 for finishing ahead, 0 for
+// finishing behind and 0.5 when neither is known to have.
+func (player ratedPlayer) score(opponent ratedPlayer) float64 {
+	switch {
+	case player.place < opponent.place:
+		return 1
+	case player.place > opponent.place:
+		return 0
+	}
+	return 0.5
+}
+
+// ratedPlayers are the players in game and where they finished. Players whose
+// place wasn't recorded share the place after the last one that was.
+func ratedPlayers(game GameRecord) []ratedPlayer {
+	names := game.Participants
+	if game.Winner != "" && !contains(names, game.Winner) {
+		names = append([]string{game.Winner}, names...)
+	}
+
+	unplaced := len(game.FinishingOrder) + 1
+	if unplaced == 1 {
+		unplaced = 2
+	}
+
+	var players []ratedPlayer
+	for _, name := range names {
+		place := game.Position(name)
+		if place == 0 {
+			place = unplaced
+		}
+		players = append(players, ratedPlayer{name, place})
+	}
+	return players
+}
+
+// RatingHistory is implemented by stores that rate their players, like RatedPlayerStore.
+type RatingHistory interface {
+	GetRatingHistory(name string) ([]RatingChange, error)
+	RecomputeRatings() error
+}
+
+// RatingChange is a player's rating after a game and how much the game changed it.
+type RatingChange struct {
+	Game   string    `json:"game"`
+	At     time.Time `json:"at"`
+	Rating float64   `json:"rating"`
+	Change float64   `json:"change"`
+}
+
+// Ratings are the ratings of players worked out from the games they played.
+type Ratings struct {
+	system  RatingSystem
+	ratings map[string]float64
+	history map[string][]RatingChange
+}
+
+// NewRatings rates the players of games, in any order, with system.
+func NewRatings(system RatingSystem, games []GameRecord) *Ratings {
+	r := &Ratings{system: system, ratings: map[string]float64{}, history: map[string][]RatingChange{}}
+	for _, game := range chronological(games) {
+		r.Add(game)
+	}
+	return r
+}
+
+// Add rates the players of game, which should have ended after the games already added.
+func (r *Ratings) Add(game GameRecord) {
+	before := map[string]float64{}
+	for _, player := range ratedPlayers(game) {
+		before[player.name] = r.Rating(player.name)
+	}
+
+	changes := r.system.Rate(game, before)
+	names := make([]string, 0, len(changes))
+	for name := range changes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		rating := before[name] + changes[name]
+		r.ratings[name] = rating
+		r.history[name] = append(r.history[name], RatingChange{
+			Game:   game.ID,
+			At:     game.EndedAt,
+			Rating: round(rating, 1),
+			Change: round(changes[name], 1),
+		})
+	}
+}
+
+// Rating is name's current rating, InitialRating if they haven't been rated.
+func (r *Ratings) Rating(name string) float64 {
+	if rating, ok := r.ratings[name]; ok {
+		return rating
+	}
+	return InitialRating
+}
+
+// History is how name's rating changed, game by game, oldest first.
+func (r *Ratings) History(name string) []RatingChange {
+	return append([]RatingChange{}, r.history[name]...)
+}
+
+// RatedPlayerStore is a PlayerStore whose players are rated on the games
+// recorded in it, the ratings are updated as each game is recorded.
+type RatedPlayerStore struct {
+	PlayerStore
+	system RatingSystem
+
+	mu      sync.RWMutex
+	ratings *Ratings
+}
+
+// NewRatedPlayerStore rates the players of store with system, starting from the games already in it.
+func NewRatedPlayerStore(store PlayerStore, system RatingSystem) (*RatedPlayerStore, error) {
+	s := &RatedPlayerStore{PlayerStore: store, system: system}
+	if err := s.RecomputeRatings(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Unwrap returns the store the rated games are kept in.
+func (s *RatedPlayerStore) Unwrap() PlayerStore {
+	return s.PlayerStore
+}
+
+// RecomputeRatings rates every player again from the games in the store, for
+// when games were recorded elsewhere or the rating system has changed.
+func (s *RatedPlayerStore) RecomputeRatings() error {
+	games, err := s.PlayerStore.GetGames()
+	if err != nil {
+		return err
+	}
+	ratings := NewRatings(s.system, games)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ratings = ratings
+	return nil
+}
+
+// RecordGame records game and rates its players.
+func (s *RatedPlayerStore) RecordGame(game GameRecord) error {
+	if err := s.PlayerStore.RecordGame(game); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ratings.Add(game)
+	return nil
+}
+
+// GetLeague returns the league with each player's rating.
+func (s *RatedPlayerStore) GetLeague() (League, error) {
+	league, err := s.PlayerStore.GetLeague()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	league = append(League{}, league...)
+	for i := range league {
+		league[i].Rating = round(s.ratings.Rating(league[i].Name), 1)
+	}
+	return league, nil
+}
+
+// GetPlayer returns name with their rating.
+func (s *RatedPlayerStore) GetPlayer(name string) (Player, error) {
+	player, err := s.PlayerStore.GetPlayer(name)
+	if err != nil {
+		return Player{}, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	player.Rating = round(s.ratings.Rating(name), 1)
+	return player, nil
+}
+
+// GetRatingHistory is how name's rating changed, game by game, oldest first.
+func (s *RatedPlayerStore) GetRatingHistory(name string) ([]RatingChange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.ratings.History(name), nil
+}
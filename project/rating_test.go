@@ -0,0 +1,130 @@
+package poker
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestElo(t *testing.T) {
+	elo := Elo{K: 32}
+
+	t.Run("rates heads-up games as plain Elo", func(t *testing.T) {
+		game := GameRecord{Participants: []string{"Chris", "Cleo"}, FinishingOrder: []string{"Chris", "Cleo"}, Winner: "Chris"}
+
+		got := elo.Rate(game, map[string]float64{"Chris": 1500, "Cleo": 1500})
+		assertRatingChanges(t, got, map[string]float64{"Chris": 16, "Cleo": -16})
+
+		// beating a much stronger player is worth more than beating an equal.
+		got = elo.Rate(game, map[string]float64{"Chris": 1300, "Cleo": 1700})
+		assertRatingChanges(t, got, map[string]float64{"Chris": 29.1, "Cleo": -29.1})
+	})
+
+	t.Run("rates tournaments on the finishing order", func(t *testing.T) {
+		game := GameRecord{
+			Participants:   []string{"Chris", "Cleo", "Tiest"},
+			FinishingOrder: []string{"Tiest", "Chris", "Cleo"},
+			Winner:         "Tiest",
+		}
+
+		got := elo.Rate(game, map[string]float64{"Chris": 1500, "Cleo": 1500, "Tiest": 1500})
+		assertRatingChanges(t, got, map[string]float64{"Tiest": 16, "Chris": 0, "Cleo": -16})
+	})
+
+	t.Run("only the winner is known to finish ahead in games without a finishing order", func(t *testing.T) {
+		game := GameRecord{Participants: []string{"Chris", "Cleo", "Tiest"}, Winner: "Cleo"}
+
+		got := elo.Rate(game, map[string]float64{"Chris": 1500, "Cleo": 1500, "Tiest": 1500})
+		assertRatingChanges(t, got, map[string]float64{"Cleo": 16, "Chris": -8, "Tiest": -8})
+	})
+}
+
+func TestNewRatings(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 9, d, 22, 0, 0, 0, time.UTC) }
+	games := []GameRecord{
+		{ID: "2", EndedAt: day(2), Participants: []string{"Chris", "Cleo"}, FinishingOrder: []string{"Cleo", "Chris"}, Winner: "Cleo"},
+		{ID: "1", EndedAt: day(1), Participants: []string{"Chris", "Cleo"}, FinishingOrder: []string{"Chris", "Cleo"}, Winner: "Chris"},
+	}
+
+	ratings := NewRatings(Elo{K: 32}, games)
+
+	want := []RatingChange{
+		{Game: "1", At: day(1), Rating: 1516, Change: 16},
+		{Game: "2", At: day(2), Rating: 1498.5, Change: -17.5},
+	}
+	if got := ratings.History("Chris"); !reflect.DeepEqual(got, want) {
+		t.Errorf("got history %+v want %+v", got, want)
+	}
+	if got := round(ratings.Rating("Cleo"), 1); got != 1501.5 {
+		t.Errorf("got rating %v want 1501.5", got)
+	}
+	if got := ratings.Rating("Tiest"); got != InitialRating {
+		t.Errorf("got rating %v for a player yet to play want %v", got, InitialRating)
+	}
+}
+
+func TestRatedPlayerStore(t *testing.T) {
+	chrisWins := GameRecord{ID: "1", Participants: []string{"Chris", "Cleo"}, FinishingOrder: []string{"Chris", "Cleo"}, Winner: "Chris"}
+
+	t.Run("rates the games already in the store", func(t *testing.T) {
+		inner := newRegisteredPlayerStore(t, "Chris", "Cleo")
+		assertNoError(t, inner.RecordGame(chrisWins))
+
+		store, err := NewRatedPlayerStore(inner, Elo{K: 32})
+		assertNoError(t, err)
+
+		assertRatings(t, getLeague(t, store), map[string]float64{"Chris": 1516, "Cleo": 1484})
+	})
+
+	t.Run("rates games as they are recorded", func(t *testing.T) {
+		store, err := NewRatedPlayerStore(newRegisteredPlayerStore(t, "Chris", "Cleo", "Tiest"), Elo{K: 32})
+		assertNoError(t, err)
+
+		assertNoError(t, store.RecordGame(chrisWins))
+
+		assertRatings(t, getLeague(t, store), map[string]float64{"Chris": 1516, "Cleo": 1484, "Tiest": InitialRating})
+		player, err := store.GetPlayer("Chris")
+		assertNoError(t, err)
+		if player.Rating != 1516 {
+			t.Errorf("got rating %v want 1516", player.Rating)
+		}
+		history, err := store.GetRatingHistory("Cleo")
+		assertNoError(t, err)
+		if len(history) != 1 || history[0].Change != -16 {
+			t.Errorf("got history %+v", history)
+		}
+	})
+
+	t.Run("recomputes ratings from games recorded elsewhere", func(t *testing.T) {
+		inner := newRegisteredPlayerStore(t, "Chris", "Cleo")
+		store, err := NewRatedPlayerStore(inner, Elo{K: 32})
+		assertNoError(t, err)
+
+		assertNoError(t, inner.RecordGame(chrisWins))
+		assertRatings(t, getLeague(t, store), map[string]float64{"Chris": InitialRating, "Cleo": InitialRating})
+
+		assertNoError(t, store.RecomputeRatings())
+		assertRatings(t, getLeague(t, store), map[string]float64{"Chris": 1516, "Cleo": 1484})
+	})
+}
+
+func assertRatingChanges(t testing.TB, got, want map[string]float64) {
+	t.Helper()
+	for name := range got {
+		got[name] = round(got[name], 1)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got rating changes %v want %v", got, want)
+	}
+}
+
+func assertRatings(t testing.TB, league League, want map[string]float64) {
+	t.Helper()
+	got := map[string]float64{}
+	for _, player := range league {
+		got[player.Name] = player.Rating
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got ratings %v want %v", got, want)
+	}
+}
@@ -0,0 +1,345 @@
+package poker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"learn-go-with-tests/clock"
+)
+
+// Reasons a season can't be found or played in.
+var (
+	ErrSeasonNotFound = errors.New("season not found")
+	ErrSeasonClosed   = errors.New("season has finished and its standings are final")
+	ErrNoSeason       = errors.New("no season is being played")
+	ErrNoSeasons      = errors.New("this league doesn't have seasons")
+)
+
+// Season is a stretch of the league with its own standings, from Start up to but not including End.
+type Season struct {
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Contains reports whether t is in the season.
+func (s Season) Contains(t time.Time) bool {
+	return !t.Before(s.Start) && t.Before(s.End)
+}
+
+// Quarter is the calendar quarter t is in, named like 2026Q3.
+func Quarter(t time.Time) Season {
+	t = t.UTC()
+	q := (int(t.Month())-1)/3 + 1
+	start := time.Date(t.Year(), time.Month(3*q-2), 1, 0, 0, 0, 0, time.UTC)
+	return Season{Name: fmt.Sprintf("%dQ%d", t.Year(), q), Start: start, End: start.AddDate(0, 3, 0)}
+}
+
+// parseQuarter is the quarter named like 2026Q3.
+func parseQuarter(name string) (Season, bool) {
+	var year, q int
+	if _, err := fmt.Sscanf(name, "%4dQ%1d", &year, &q); err != nil || year < 1000 || q < 1 || q > 4 {
+		return Season{}, false
+	}
+	season := Quarter(time.Date(year, time.Month(3*q-2), 1, 0, 0, 0, 0, time.UTC))
+	return season, season.Name == name
+}
+
+// Seasons is the league's calendar, oldest season first. With no seasons
+// defined the league runs quarterly seasons.
+type Seasons []Season
+
+// NewSeasons checks every season has a name, ends after it starts and doesn't overlap another.
+func NewSeasons(seasons ...Season) (Seasons, error) {
+	s := append(Seasons{}, seasons...)
+	sort.Slice(s, func(i, j int) bool { return s[i].Start.Before(s[j].Start) })
+
+	names := map[string]bool{}
+	for i, season := range s {
+		if season.Name == "" || names[season.Name] {
+			return nil, fmt.Errorf("seasons need a unique name, got %q", season.Name)
+		}
+		names[season.Name] = true
+		if !season.Start.Before(season.End) {
+			return nil, fmt.Errorf("season %q ends before it starts", season.Name)
+		}
+		if i > 0 && season.Start.Before(s[i-1].End) {
+			return nil, fmt.Errorf("season %q overlaps %q", season.Name, s[i-1].Name)
+		}
+	}
+	return s, nil
+}
+
+// Get finds a season by name.
+func (s Seasons) Get(name string) (Season, error) {
+	if len(s) == 0 {
+		if season, ok := parseQuarter(name); ok {
+			return season, nil
+		}
+	}
+	for _, season := range s {
+		if season.Name == name {
+			return season, nil
+		}
+	}
+	return Season{}, fmt.Errorf("%w, %q", ErrSeasonNotFound, name)
+}
+
+// At finds the season t is in, if any.
+func (s Seasons) At(t time.Time) (Season, bool) {
+	if t.IsZero() {
+		return Season{}, false
+	}
+	if len(s) == 0 {
+		return Quarter(t), true
+	}
+	for _, season := range s {
+		if season.Contains(t) {
+			return season, true
+		}
+	}
+	return Season{}, false
+}
+
+// LoadSeasons reads a calendar of seasons from a .json, .yaml or .yml file,
+// a list of seasons with a name and start and end dates like 2026-07-01.
+func LoadSeasons(path string) (Seasons, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("problem reading seasons %s, %v", path, err)
+	}
+
+	var raw []struct {
+		Name  string `json:"name" yaml:"name"`
+		Start string `json:"start" yaml:"start"`
+		End   string `json:"end" yaml:"end"`
+	}
+	switch filepath.Ext(path) {
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("unsupported seasons file %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("problem parsing seasons %s, %v", path, err)
+	}
+
+	var seasons []Season
+	for _, r := range raw {
+		start, err := time.Parse("2006-01-02", r.Start)
+		if err != nil {
+			return nil, fmt.Errorf("problem parsing start of season %q, %v", r.Name, err)
+		}
+		end, err := time.Parse("2006-01-02", r.End)
+		if err != nil {
+			return nil, fmt.Errorf("problem parsing end of season %q, %v", r.Name, err)
+		}
+		seasons = append(seasons, Season{Name: r.Name, Start: start, End: end})
+	}
+	return NewSeasons(seasons...)
+}
+
+// NewSeasonLeague is the standings of season, the wins of everyone who played
+// a game that ended in it.
+func NewSeasonLeague(season Season, games []GameRecord) League {
+	league := League{}
+	for _, game := range games {
+		if !season.Contains(game.EndedAt) {
+			continue
+		}
+		for _, name := range append([]string{game.Winner}, game.Participants...) {
+			if name != "" && league.Find(name) == nil {
+				league = append(league, Player{Name: name})
+			}
+		}
+		if game.Winner != "" {
+			league.Find(game.Winner).Wins++
+		}
+	}
+	league.Sort()
+	return league
+}
+
+// SeasonStatus is a season and whether it has finished.
+type SeasonStatus struct {
+	Season
+	Finished bool `json:"finished"`
+}
+
+// SeasonLeagues is implemented by stores that split their league into seasons, like SeasonalPlayerStore.
+type SeasonLeagues interface {
+	// GetSeasons returns the seasons played so far, oldest first.
+	GetSeasons() ([]SeasonStatus, error)
+	GetSeasonLeague(name string) (League, error)
+}
+
+// SeasonalPlayerStore is a PlayerStore whose league is split into seasons,
+// each game's win counting towards the season it ended in and a win recorded
+// without a game towards the season being played. Once a season has finished
+// no more games or wins can be recorded in it, so its standings are final.
+// They are worked out from the games once and kept while the store is open.
+// Wins recorded without a game aren't games, so they are counted separately
+// and only while the store is open, the league keeps them for good.
+type SeasonalPlayerStore struct {
+	PlayerStore
+	seasons Seasons
+	clock   clock.Clock
+
+	mu      sync.Mutex
+	archive map[string]League
+	wins    map[string]League
+}
+
+// NewSeasonalPlayerStore splits the league of store into seasons.
+func NewSeasonalPlayerStore(store PlayerStore, seasons Seasons, clk clock.Clock) *SeasonalPlayerStore {
+	return &SeasonalPlayerStore{
+		PlayerStore: store,
+		seasons:     seasons,
+		clock:       clk,
+		archive:     map[string]League{},
+		wins:        map[string]League{},
+	}
+}
+
+// Unwrap returns the store the seasons are kept in.
+func (s *SeasonalPlayerStore) Unwrap() PlayerStore {
+	return s.PlayerStore
+}
+
+// RecordWin records a win for name in the league and the season being played.
+func (s *SeasonalPlayerStore) RecordWin(name string) error {
+	season, err := s.playing(s.clock.Now())
+	if err != nil {
+		return err
+	}
+	if err := s.PlayerStore.RecordWin(name); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	wins := s.wins[season.Name]
+	if wins.Find(name) == nil {
+		wins = append(wins, Player{Name: name})
+	}
+	wins.Find(name).Wins++
+	s.wins[season.Name] = wins
+	return nil
+}
+
+// RecordGameWin records the win of game's winner and game, against the
+// season the game ended in, which must be being played.
+func (s *SeasonalPlayerStore) RecordGameWin(game GameRecord) error {
+	if _, err := s.playing(game.EndedAt); err != nil {
+		return err
+	}
+
+	if err := s.PlayerStore.RecordWin(game.Winner); err != nil {
+		return err
+	}
+	if err := s.PlayerStore.RecordGame(game); err != nil {
+		// the win is in, only the game and its place in the season are lost.
+		log.Printf("problem recording game, %v\n", err)
+	}
+	return nil
+}
+
+// RecordGame records game against the season it ended in, which can't have finished.
+func (s *SeasonalPlayerStore) RecordGame(game GameRecord) error {
+	if season, ok := s.seasons.At(game.EndedAt); ok && s.finished(season) {
+		return fmt.Errorf("%w, %q", ErrSeasonClosed, season.Name)
+	}
+	return s.PlayerStore.RecordGame(game)
+}
+
+// GetSeasons ..
+func (s *SeasonalPlayerStore) GetSeasons() ([]SeasonStatus, error) {
+	now := s.clock.Now()
+	seasons := s.seasons
+	if len(seasons) == 0 {
+		// quarters run from the first one a game was played in.
+		games, err := s.PlayerStore.GetGames()
+		if err != nil {
+			return nil, err
+		}
+		first := now
+		for _, game := range games {
+			if !game.EndedAt.IsZero() && game.EndedAt.Before(first) {
+				first = game.EndedAt
+			}
+		}
+		for season := Quarter(first); !now.Before(season.Start); season = Quarter(season.End) {
+			seasons = append(seasons, season)
+		}
+	}
+
+	started := []SeasonStatus{}
+	for _, season := range seasons {
+		if !now.Before(season.Start) {
+			started = append(started, SeasonStatus{season, s.finished(season)})
+		}
+	}
+	return started, nil
+}
+
+// GetSeasonLeague returns the standings of the season called name.
+func (s *SeasonalPlayerStore) GetSeasonLeague(name string) (League, error) {
+	season, err := s.seasons.Get(name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	archived, ok := s.archive[name]
+	s.mu.Unlock()
+	if ok {
+		return append(League{}, archived...), nil
+	}
+
+	games, err := s.PlayerStore.GetGames()
+	if err != nil {
+		return nil, err
+	}
+	league := NewSeasonLeague(season, games)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, player := range s.wins[name] {
+		if league.Find(player.Name) == nil {
+			league = append(league, Player{Name: player.Name})
+		}
+		league.Find(player.Name).Wins += player.Wins
+	}
+	league.Sort()
+
+	if s.finished(season) {
+		s.archive[name] = append(League{}, league...)
+	}
+	return league, nil
+}
+
+// playing is the season at t, which must be being played.
+func (s *SeasonalPlayerStore) playing(t time.Time) (Season, error) {
+	season, ok := s.seasons.At(t)
+	if !ok {
+		return Season{}, ErrNoSeason
+	}
+	if s.finished(season) {
+		return Season{}, fmt.Errorf("%w, %q", ErrSeasonClosed, season.Name)
+	}
+	return season, nil
+}
+
+func (s *SeasonalPlayerStore) finished(season Season) bool {
+	return !s.clock.Now().Before(season.End)
+}
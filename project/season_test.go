@@ -0,0 +1,206 @@
+package poker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	"learn-go-with-tests/clock"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestSeasons(t *testing.T) {
+	t.Run("seasons are calendar quarters when none are defined", func(t *testing.T) {
+		var seasons Seasons
+
+		got, err := seasons.Get("2026Q3")
+		assertNoError(t, err)
+		want := Season{Name: "2026Q3", Start: date(2026, time.July, 1), End: date(2026, time.October, 1)}
+		if got != want {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+
+		if got, _ := seasons.At(date(2026, time.December, 31)); got.Name != "2026Q4" {
+			t.Errorf("got season %q want 2026Q4", got.Name)
+		}
+		for _, name := range []string{"2026Q5", "2026-Q3", "26Q3", "2026Q3x"} {
+			if _, err := seasons.Get(name); !errors.Is(err, ErrSeasonNotFound) {
+				t.Errorf("got %v for %q want %v", err, name, ErrSeasonNotFound)
+			}
+		}
+	})
+
+	t.Run("loads a calendar of seasons", func(t *testing.T) {
+		path := writeBlindStructureFile(t, "seasons.yaml", `
+- name: summer
+  start: "2026-06-01"
+  end: "2026-09-01"
+- name: spring
+  start: "2026-03-01"
+  end: "2026-06-01"
+`)
+
+		seasons, err := LoadSeasons(path)
+		assertNoError(t, err)
+
+		want := Seasons{
+			{Name: "spring", Start: date(2026, time.March, 1), End: date(2026, time.June, 1)},
+			{Name: "summer", Start: date(2026, time.June, 1), End: date(2026, time.September, 1)},
+		}
+		if !reflect.DeepEqual(seasons, want) {
+			t.Errorf("got %+v want %+v", seasons, want)
+		}
+		if _, ok := seasons.At(date(2026, time.October, 1)); ok {
+			t.Error("didn't expect a season after the last one")
+		}
+		if _, err := seasons.Get("2026Q3"); !errors.Is(err, ErrSeasonNotFound) {
+			t.Errorf("got %v want %v, quarters are only used without a calendar", err, ErrSeasonNotFound)
+		}
+	})
+
+	t.Run("rejects seasons that overlap or end before they start", func(t *testing.T) {
+		spring := Season{Name: "spring", Start: date(2026, time.March, 1), End: date(2026, time.June, 1)}
+		cases := map[string]Season{
+			"overlapping": {Name: "summer", Start: date(2026, time.May, 1), End: date(2026, time.September, 1)},
+			"backwards":   {Name: "summer", Start: date(2026, time.September, 1), End: date(2026, time.June, 1)},
+			"same name":   {Name: "spring", Start: date(2027, time.March, 1), End: date(2027, time.June, 1)},
+			"unnamed":     {Start: date(2026, time.June, 1), End: date(2026, time.September, 1)},
+		}
+		for name, season := range cases {
+			if _, err := NewSeasons(spring, season); err == nil {
+				t.Errorf("expected an error for %s seasons", name)
+			}
+		}
+	})
+}
+
+func TestNewSeasonLeague(t *testing.T) {
+	season := Quarter(date(2026, time.July, 1))
+	games := []GameRecord{
+		{EndedAt: date(2026, time.June, 30), Participants: []string{"Chris", "Cleo"}, Winner: "Chris"},
+		{EndedAt: date(2026, time.July, 1), Participants: []string{"Chris", "Cleo", "Tiest"}, Winner: "Cleo"},
+		{EndedAt: date(2026, time.September, 30), Participants: []string{"Chris", "Cleo"}, Winner: "Cleo"},
+		{EndedAt: date(2026, time.October, 1), Participants: []string{"Chris", "Cleo"}, Winner: "Chris"},
+	}
+
+	got := NewSeasonLeague(season, games)
+	assertLeague(t, got, League{{Name: "Cleo", Wins: 2}, {Name: "Chris"}, {Name: "Tiest"}})
+}
+
+func TestSeasonalPlayerStore(t *testing.T) {
+	newStore := func(t *testing.T) (*SeasonalPlayerStore, *clock.Fake) {
+		clk := clock.NewFake(date(2026, time.September, 30))
+		return NewSeasonalPlayerStore(newRegisteredPlayerStore(t, "Chris", "Cleo"), nil, clk), clk
+	}
+	game := func(ended time.Time, winner string) GameRecord {
+		return GameRecord{EndedAt: ended, Participants: []string{"Chris", "Cleo"}, Winner: winner}
+	}
+
+	t.Run("records games against the season they ended in", func(t *testing.T) {
+		store, _ := newStore(t)
+
+		assertNoError(t, store.RecordGame(game(date(2026, time.September, 29), "Chris")))
+
+		league, err := store.GetSeasonLeague("2026Q3")
+		assertNoError(t, err)
+		assertLeague(t, league, League{{Name: "Chris", Wins: 1}, {Name: "Cleo"}})
+	})
+
+	t.Run("archives finished seasons", func(t *testing.T) {
+		store, clk := newStore(t)
+		assertNoError(t, store.RecordGame(game(date(2026, time.September, 29), "Chris")))
+		clk.Advance(24 * time.Hour)
+
+		err := store.RecordGame(game(date(2026, time.September, 30), "Cleo"))
+		if !errors.Is(err, ErrSeasonClosed) {
+			t.Errorf("got %v want %v", err, ErrSeasonClosed)
+		}
+
+		archived, err := store.GetSeasonLeague("2026Q3")
+		assertNoError(t, err)
+		assertNoError(t, store.RecordGame(game(date(2026, time.October, 1), "Cleo")))
+		again, err := store.GetSeasonLeague("2026Q3")
+		assertNoError(t, err)
+		assertLeague(t, again, archived)
+	})
+
+	t.Run("records wins against the season being played", func(t *testing.T) {
+		store, _ := newStore(t)
+
+		assertNoError(t, store.RecordGame(game(date(2026, time.September, 29), "Cleo")))
+		assertNoError(t, store.RecordWin("Chris"))
+		assertNoError(t, store.RecordWin("Chris"))
+
+		league, err := store.GetSeasonLeague("2026Q3")
+		assertNoError(t, err)
+		assertLeague(t, league, League{{Name: "Chris", Wins: 2}, {Name: "Cleo", Wins: 1}})
+		assertScoreEquals(t, getScore(t, store, "Chris"), 2)
+		if games := getGames(t, store); len(games) != 1 {
+			t.Errorf("got %d games recorded want only the game played, wins aren't games", len(games))
+		}
+	})
+
+	t.Run("doesn't record wins between seasons", func(t *testing.T) {
+		seasons, err := NewSeasons(Season{Name: "Summer", Start: date(2026, time.July, 1), End: date(2026, time.September, 1)})
+		assertNoError(t, err)
+		store := NewSeasonalPlayerStore(newRegisteredPlayerStore(t, "Chris"), seasons, clock.NewFake(date(2026, time.September, 30)))
+
+		if err := store.RecordWin("Chris"); !errors.Is(err, ErrNoSeason) {
+			t.Errorf("got %v want %v", err, ErrNoSeason)
+		}
+		assertScoreEquals(t, getScore(t, store, "Chris"), 0)
+	})
+
+	t.Run("doesn't record the win of a game in a finished season", func(t *testing.T) {
+		store, clk := newStore(t)
+		clk.Advance(24 * time.Hour)
+
+		err := store.RecordGameWin(game(date(2026, time.September, 30), "Cleo"))
+		if !errors.Is(err, ErrSeasonClosed) {
+			t.Errorf("got %v want %v", err, ErrSeasonClosed)
+		}
+		assertScoreEquals(t, getScore(t, store, "Cleo"), 0)
+		if games := getGames(t, store); len(games) != 0 {
+			t.Errorf("got %d games recorded want none", len(games))
+		}
+	})
+
+	t.Run("a finished game counts once towards the season and the league", func(t *testing.T) {
+		store, clk := newStore(t)
+		game := NewTexasHoldem(dummyBlindAlerter, store, WithClock(clk))
+		assertNoError(t, game.Start(context.Background(), 2, "", io.Discard))
+		assertNoError(t, game.Finish("Cleo"))
+
+		league, err := store.GetSeasonLeague("2026Q3")
+		assertNoError(t, err)
+		assertLeague(t, league, League{{Name: "Cleo", Wins: 1}})
+		assertScoreEquals(t, getScore(t, store, "Cleo"), 1)
+		if games := getGames(t, store); len(games) != 1 {
+			t.Errorf("got %d games recorded want 1", len(games))
+		}
+	})
+
+	t.Run("lists the seasons since the first game", func(t *testing.T) {
+		store, clk := newStore(t)
+		// played before the store had seasons.
+		assertNoError(t, store.PlayerStore.RecordGame(game(date(2026, time.June, 1), "Chris")))
+		clk.Advance(24 * time.Hour)
+
+		got, err := store.GetSeasons()
+		assertNoError(t, err)
+		want := []SeasonStatus{
+			{Quarter(date(2026, time.April, 1)), true},
+			{Quarter(date(2026, time.July, 1)), true},
+			{Quarter(date(2026, time.October, 1)), false},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+	})
+}
@@ -1,12 +1,19 @@
 package poker
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"html/template"
+	"log"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
@@ -17,10 +24,16 @@ const (
 )
 
 // PlayerStore ..
+// Errors other than the ones documented are the store failing to read or
+// write its storage.
 type PlayerStore interface {
-	GetPlayerScore(name string) int
-	RecordWin(name string)
-	GetLeague() League
+	GetPlayerScore(name string) (int, error)
+	// RecordWin returns a *PlayerError for players who aren't registered.
+	RecordWin(name string) error
+	GetLeague() (League, error)
+	PlayerRoster
+	GameHistory
+	ChipLedger
 }
 
 // PlayerServer ..
@@ -28,17 +41,22 @@ type PlayerServer struct {
 	store PlayerStore
 	http.Handler
 	template *template.Template
-	game     Game
+	games    *GameRegistry
 }
 
 // Player ..
 type Player struct {
-	Name string
-	Wins int
+	Name  string
+	Wins  int
+	Chips int
+	// Rating is only set by stores that rate their players.
+	Rating float64 `json:",omitempty"`
+	Profile
 }
 
-// NewPlayerServer ..
-func NewPlayerServer(store PlayerStore, game Game) (*PlayerServer, error) {
+// NewPlayerServer returns a server playing each game it hosts with a game
+// from newGame, given the ID the game is played under.
+func NewPlayerServer(store PlayerStore, newGame func(id string) Game) (*PlayerServer, error) {
 	p := new(PlayerServer)
 
 	tmpl, err := template.ParseFiles(htmlTemplatePath)
@@ -46,61 +64,419 @@ func NewPlayerServer(store PlayerStore, game Game) (*PlayerServer, error) {
 		return nil, fmt.Errorf("problem opening %s %v", htmlTemplatePath, err)
 	}
 
-	p.game = game
+	history, err := store.GetGames()
+	if err != nil {
+		return nil, fmt.Errorf("problem reading the game history, %v", err)
+	}
+
+	p.games = NewGameRegistry(newGame, lastGameID(history))
 	p.template = tmpl
 	p.store = store
 
 	router := http.NewServeMux()
 	router.Handle("/league", http.HandlerFunc(p.leagueHandler))
+	router.Handle("/players", http.HandlerFunc(p.playersHandler))
 	router.Handle("/players/", http.HandlerFunc(p.playerHandler))
 	router.Handle("/game", http.HandlerFunc(p.playGame))
 	router.Handle("/ws", http.HandlerFunc(p.webSocket))
+	router.Handle("/games", http.HandlerFunc(p.gamesHandler))
+	router.Handle("/games/", http.HandlerFunc(p.gameHandler))
+	router.Handle("/games/history", http.HandlerFunc(p.historyHandler))
+	router.Handle("/stats/players/", http.HandlerFunc(p.playerStatsHandler))
+	router.Handle("/stats/h2h", http.HandlerFunc(p.headToHeadHandler))
+	router.Handle("/ratings/recompute", http.HandlerFunc(p.recomputeRatingsHandler))
+	router.Handle("/seasons", http.HandlerFunc(p.seasonsHandler))
 
 	p.Handler = router
 
 	return p, nil
 }
 
+// leagueHandler serves the all-time league ranked by wins, by name with
+// ?sort=name, by net chips with ?sort=chips or, when the store rates its
+// players, by rating with ?sort=rating. ?order=asc|desc turns the ranking
+// around, ?q= keeps the players whose names start with it and ?limit= and
+// ?offset= page it, with Link headers to the other pages. With ?season=2026Q3
+// it serves the standings of that season instead.
+// The league is sent as JSON, CSV, HTML or Markdown as the Accept header
+// asks, or as ?format= says.
 func (p *PlayerServer) leagueHandler(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("content-type", jsonContentType)
-	check(json.NewEncoder(w).Encode(p.store.GetLeague()))
+	query, err := parseLeagueQuery(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	format := r.URL.Query().Get("format")
+	if _, ok := LeagueFormats[format]; format != "" && !ok {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("%w, got %q", ErrUnknownFormat, format))
+		return
+	}
+	if format == "" {
+		var acceptable bool
+		if format, acceptable = NegotiateLeagueFormat(r.Header.Get("Accept")); !acceptable {
+			writeError(w, http.StatusNotAcceptable, ErrUnknownFormat)
+			return
+		}
+	}
+
+	if _, rated := p.ratings(); query.sort == "rating" && !rated {
+		storeError(w, ErrNotRated)
+		return
+	}
+
+	var league League
+	if query.season != "" {
+		league, err = p.seasonLeague(query.season)
+	} else {
+		league, err = p.store.GetLeague()
+	}
+	if err != nil {
+		storeError(w, err)
+		return
+	}
+
+	page, total := query.page(league)
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	if links := query.links(r.URL, total); links != nil {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+
+	var body bytes.Buffer
+	if err := WriteLeaguePage(&body, page, query.offset, format); err != nil {
+		status, errorBody := encodingFailed(err)
+		writeBody(w, status, errorBody)
+		return
+	}
+	w.Header().Set("Vary", "Accept")
+	writeWithETag(w, r, LeagueFormats[format], body.Bytes())
 }
 
 func (p *PlayerServer) getLeagueTable() []Player {
-	leagueTable := []Player{{"Chris", 20}}
+	leagueTable := []Player{{Name: "Chris", Wins: 20}}
 	return leagueTable
 }
 
 func (p *PlayerServer) playerHandler(w http.ResponseWriter, r *http.Request) {
 	player := strings.TrimPrefix(r.URL.Path, "/players/")
 
+	if name := strings.TrimSuffix(player, "/games"); name != player {
+		if allowMethods(w, r, http.MethodGet) {
+			p.showPlayerGames(w, name)
+		}
+		return
+	}
+
+	if name := strings.TrimSuffix(player, "/ratings"); name != player {
+		if allowMethods(w, r, http.MethodGet) {
+			p.showRatingHistory(w, name)
+		}
+		return
+	}
+
+	if name := strings.TrimSuffix(player, "/profile"); name != player {
+		if allowMethods(w, r, http.MethodGet) {
+			p.showPlayer(w, name)
+		}
+		return
+	}
+
+	if name := strings.TrimSuffix(player, "/bankroll"); name != player {
+		switch r.Method {
+		case http.MethodPost:
+			p.processChips(w, r, name)
+		case http.MethodGet:
+			p.showBankroll(w, name)
+		default:
+			allowMethods(w, r, http.MethodGet, http.MethodPost)
+		}
+		return
+	}
+
 	switch r.Method {
 	case http.MethodPost:
 		p.processWin(w, player)
 	case http.MethodGet:
 		p.showScore(w, player)
+	case http.MethodPut:
+		p.updatePlayer(w, r, player)
+	case http.MethodDelete:
+		p.deletePlayer(w, player)
+	default:
+		allowMethods(w, r, http.MethodGet, http.MethodPost, http.MethodPut, http.MethodDelete)
+	}
+}
+
+// registration is the body of a request to register a player.
+type registration struct {
+	Name string
+	Profile
+}
+
+// playersHandler registers the player sent as a registration.
+func (p *PlayerServer) playersHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+
+	var reg registration
+	if err := json.NewDecoder(r.Body).Decode(&reg); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("problem parsing player, %v", err))
+		return
+	}
+	if err := p.store.RegisterPlayer(reg.Name, reg.Profile); err != nil {
+		storeError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/players/"+url.PathEscape(reg.Name)+"/profile")
+	writeJSON(w, http.StatusCreated, Player{Name: reg.Name, Profile: reg.Profile})
+}
+
+func (p *PlayerServer) showPlayer(w http.ResponseWriter, name string) {
+	player, err := p.store.GetPlayer(name)
+	if err != nil {
+		storeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, player)
+}
+
+// updatePlayer replaces the profile of a registered player.
+func (p *PlayerServer) updatePlayer(w http.ResponseWriter, r *http.Request, name string) {
+	var profile Profile
+	if err := json.NewDecoder(r.Body).Decode(&profile); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("problem parsing profile, %v", err))
+		return
+	}
+	if err := p.store.UpdatePlayer(name, profile); err != nil {
+		storeError(w, err)
+		return
 	}
+	p.showPlayer(w, name)
+}
+
+func (p *PlayerServer) deletePlayer(w http.ResponseWriter, name string) {
+	if err := p.store.DeletePlayer(name); err != nil {
+		storeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func (p *PlayerServer) showScore(w http.ResponseWriter, player string) {
-	score := p.store.GetPlayerScore(player)
+	score, err := p.store.GetPlayerScore(player)
+	if err != nil {
+		storeError(w, err)
+		return
+	}
 
 	if score == 0 {
 		w.WriteHeader(http.StatusNotFound)
 	}
 
-	_, err := fmt.Fprint(w, score)
-	check(err)
+	if _, err := fmt.Fprint(w, score); err != nil {
+		log.Printf("problem writing response, %v\n", err)
+	}
 }
 
 func (p *PlayerServer) processWin(w http.ResponseWriter, player string) {
-	p.store.RecordWin(player)
+	if err := p.store.RecordWin(player); err != nil {
+		storeError(w, err)
+		return
+	}
 	w.WriteHeader(http.StatusAccepted)
-	return
+}
+
+func (p *PlayerServer) showPlayerGames(w http.ResponseWriter, player string) {
+	games, err := p.store.GetPlayerGames(player)
+	if err != nil {
+		storeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, games)
+}
+
+func (p *PlayerServer) showBankroll(w http.ResponseWriter, player string) {
+	// players who haven't bought in yet have a bankroll, players who don't exist don't.
+	if _, err := p.store.GetPlayer(player); err != nil {
+		storeError(w, err)
+		return
+	}
+	transactions, err := p.store.GetChipTransactions(player)
+	if err != nil {
+		storeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, NewBankroll(player, transactions))
+}
+
+// processChips records a buy-in, rebuy, add-on or payout sent as a ChipTransaction.
+func (p *PlayerServer) processChips(w http.ResponseWriter, r *http.Request, player string) {
+	var transaction ChipTransaction
+	if err := json.NewDecoder(r.Body).Decode(&transaction); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("problem parsing chip transaction, %v", err))
+		return
+	}
+	transaction.Player = player
+	if transaction.At.IsZero() {
+		transaction.At = time.Now().UTC()
+	}
+
+	if err := p.store.RecordChips(transaction); err != nil {
+		storeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (p *PlayerServer) historyHandler(w http.ResponseWriter, r *http.Request) {
+	games, err := p.store.GetGames()
+	if err != nil {
+		storeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, games)
+}
+
+// playerStatsHandler serves the PlayerStats of the player named in the path.
+func (p *PlayerServer) playerStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	name := strings.TrimPrefix(r.URL.Path, "/stats/players/")
+
+	games, err := p.store.GetPlayerGames(name)
+	if err != nil {
+		storeError(w, err)
+		return
+	}
+	// players who haven't played yet have stats, players who don't exist don't.
+	if len(games) == 0 {
+		if _, err := p.store.GetPlayer(name); err != nil {
+			storeError(w, err)
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, NewPlayerStats(name, games))
+}
+
+// headToHeadHandler serves the HeadToHead of the players given by ?a= and ?b=.
+func (p *PlayerServer) headToHeadHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	a, b := r.URL.Query().Get("a"), r.URL.Query().Get("b")
+	if a == "" || b == "" || a == b {
+		writeError(w, http.StatusBadRequest, errors.New("head to head needs two different players, ?a=X&b=Y"))
+		return
+	}
+
+	games, err := p.store.GetPlayerGames(a)
+	if err != nil {
+		storeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, NewHeadToHead(a, b, games))
+}
+
+// seasonLeague returns the standings of the season called name.
+func (p *PlayerServer) seasonLeague(name string) (League, error) {
+	var seasons SeasonLeagues
+	if !unwrapStore(p.store, func(s PlayerStore) (ok bool) { seasons, ok = s.(SeasonLeagues); return }) {
+		return nil, ErrNoSeasons
+	}
+	return seasons.GetSeasonLeague(name)
+}
+
+// seasonsHandler serves the seasons played so far and whether they've finished.
+func (p *PlayerServer) seasonsHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodGet) {
+		return
+	}
+	var seasons SeasonLeagues
+	if !unwrapStore(p.store, func(s PlayerStore) (ok bool) { seasons, ok = s.(SeasonLeagues); return }) {
+		storeError(w, ErrNoSeasons)
+		return
+	}
+
+	played, err := seasons.GetSeasons()
+	if err != nil {
+		storeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, played)
+}
+
+// ratings returns the store, or the store it wraps, that rates the players.
+func (p *PlayerServer) ratings() (RatingHistory, bool) {
+	var rated RatingHistory
+	ok := unwrapStore(p.store, func(s PlayerStore) (ok bool) { rated, ok = s.(RatingHistory); return })
+	return rated, ok
+}
+
+// unwrapStore calls found with store and then each store it wraps, until
+// found returns true. Stores that wrap another have an Unwrap method, as
+// errors that wrap another do.
+func unwrapStore(store PlayerStore, found func(PlayerStore) bool) bool {
+	for !found(store) {
+		wrapper, ok := store.(interface{ Unwrap() PlayerStore })
+		if !ok {
+			return false
+		}
+		store = wrapper.Unwrap()
+	}
+	return true
+}
+
+// showRatingHistory serves how a player's rating changed, game by game.
+func (p *PlayerServer) showRatingHistory(w http.ResponseWriter, name string) {
+	rated, ok := p.ratings()
+	if !ok {
+		storeError(w, ErrNotRated)
+		return
+	}
+	if _, err := p.store.GetPlayer(name); err != nil {
+		storeError(w, err)
+		return
+	}
+
+	history, err := rated.GetRatingHistory(name)
+	if err != nil {
+		storeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, history)
+}
+
+// recomputeRatingsHandler rates every player again from the game history.
+func (p *PlayerServer) recomputeRatingsHandler(w http.ResponseWriter, r *http.Request) {
+	if !allowMethods(w, r, http.MethodPost) {
+		return
+	}
+	rated, ok := p.ratings()
+	if !ok {
+		storeError(w, ErrNotRated)
+		return
+	}
+
+	if err := rated.RecomputeRatings(); err != nil {
+		storeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
 }
 
 func (p *PlayerServer) playGame(w http.ResponseWriter, r *http.Request) {
-	check(p.template.Execute(w, nil))
+	var page bytes.Buffer
+	if err := p.template.Execute(&page, nil); err != nil {
+		log.Printf("problem rendering %s, %v\n", htmlTemplatePath, err)
+		writeError(w, http.StatusInternalServerError, errors.New("problem rendering the game"))
+		return
+	}
+	if _, err := page.WriteTo(w); err != nil {
+		log.Printf("problem writing response, %v\n", err)
+	}
 }
 
 var wsUpgrader = websocket.Upgrader{
@@ -108,13 +484,199 @@ var wsUpgrader = websocket.Upgrader{
 	WriteBufferSize: 1024,
 }
 
+func (p *PlayerServer) gamesHandler(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, p.games.List())
+}
+
+func (p *PlayerServer) gameHandler(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/games/")
+
+	game, err := p.games.Get(id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, game)
+}
+
+// errorResponse is the body of every error the server replies with.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeError replies with status and err as an errorResponse.
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, errorResponse{err.Error()})
+}
+
+// storeError replies with the status for why the store couldn't do what
+// was asked. The store itself failing is logged and its details are kept
+// from the client.
+func storeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrPlayerNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, ErrPlayerExists):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, ErrInvalidPlayerName), errors.Is(err, ErrInvalidChipTransaction):
+		writeError(w, http.StatusBadRequest, err)
+	case errors.Is(err, ErrSeasonNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case errors.Is(err, ErrSeasonClosed), errors.Is(err, ErrNoSeason):
+		writeError(w, http.StatusConflict, err)
+	case errors.Is(err, ErrNotRated), errors.Is(err, ErrNoSeasons):
+		writeError(w, http.StatusNotImplemented, err)
+	default:
+		log.Printf("player store failed, %v\n", err)
+		writeError(w, http.StatusInternalServerError, errors.New("problem reaching the player store"))
+	}
+}
+
+// writeJSON replies with status and v as JSON. v is encoded before anything
+// is sent so a value that can't be encoded is still a 500.
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		status, body = encodingFailed(err)
+	}
+	writeBody(w, status, body)
+}
+
+// writeWithETag replies with body, tagged with an ETag of it and the headers
+// already set. Clients whose If-None-Match has the ETag already get a 304 Not
+// Modified without the body.
+func writeWithETag(w http.ResponseWriter, r *http.Request, contentType string, body []byte) {
+	hash := sha256.New()
+	for _, header := range []string{"Link", "X-Total-Count"} {
+		fmt.Fprintf(hash, "%s: %s\n", header, w.Header().Get(header))
+	}
+	hash.Write(body)
+	etag := `"` + hex.EncodeToString(hash.Sum(nil)[:16]) + `"`
+	w.Header().Set("ETag", etag)
+
+	for _, match := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		match = strings.TrimPrefix(strings.TrimSpace(match), "W/")
+		if match == etag || match == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	w.Header().Set("content-type", contentType)
+	if _, err := w.Write(body); err != nil {
+		log.Printf("problem writing response, %v\n", err)
+	}
+}
+
+// encodingFailed logs err and returns the 500 to reply with instead of a body that couldn't be encoded.
+func encodingFailed(err error) (int, []byte) {
+	log.Printf("problem encoding response, %v\n", err)
+	return http.StatusInternalServerError, []byte(`{"error":"problem encoding the response"}`)
+}
+
+// writeBody replies with status and body, which is JSON.
+func writeBody(w http.ResponseWriter, status int, body []byte) {
+	w.Header().Set("content-type", jsonContentType)
+	w.WriteHeader(status)
+	if _, err := w.Write(append(body, '\n')); err != nil {
+		log.Printf("problem writing response, %v\n", err)
+	}
+}
+
+// allowMethods reports whether r uses one of methods, replying with a 405 when it doesn't.
+func allowMethods(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+	w.Header().Set("Allow", strings.Join(methods, ", "))
+	writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("%s is not allowed, use %s", r.Method, strings.Join(methods, " or ")))
+	return false
+}
+
+// webSocket starts a new game, or joins the game given by the "game" query
+// parameter. Any of its clients can pause and resume it, and finish it with
+// the winner.
 func (p *PlayerServer) webSocket(w http.ResponseWriter, r *http.Request) {
-	ws := newPlayerServerWS(w, r)
+	ws, err := newPlayerServerWS(w, r)
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	defer ws.Close()
 
-	numberOfPlayersMsg := ws.WaitForMsg()
-	numberOfPlayers, _ := strconv.Atoi(numberOfPlayersMsg)
-	p.game.Start(numberOfPlayers, ws)
+	id := r.URL.Query().Get("game")
+	var done <-chan struct{}
+	if id == "" {
+		id, done, err = p.startGame(ws)
+	} else {
+		done, err = p.games.Join(id, ws)
+	}
+	if err != nil {
+		ws.SendError(err)
+		return
+	}
+	// a game is abandoned once all of its clients have gone away.
+	defer p.games.Leave(id, ws)
+
+	// unblock WaitForMsg once the game is over, whoever finished it.
+	left := make(chan struct{})
+	defer close(left)
+	go func() {
+		select {
+		case <-done:
+			_ = ws.Close()
+		case <-left:
+		}
+	}()
+
+	for {
+		msg, err := ws.WaitForMsg(WSFinish, WSPause, WSResume, WSDeal, WSShowdown, WSAction)
+		if err != nil {
+			return
+		}
 
-	winner := ws.WaitForMsg()
-	p.game.Finish(winner)
+		switch msg.Type {
+		case WSPause:
+			err = p.games.Pause(id)
+		case WSResume:
+			err = p.games.Resume(id)
+		case WSDeal:
+			err = p.games.Deal(id)
+		case WSShowdown:
+			err = p.games.Showdown(id)
+		case WSAction:
+			var action Action
+			if action, err = ws.action(msg); err == nil {
+				err = p.games.Act(id, action)
+			}
+		case WSFinish:
+			if err = p.games.Finish(id, msg.Winner); err == nil {
+				return
+			}
+		}
+		if err != nil {
+			ws.SendError(err)
+		}
+	}
+}
+
+// startGame starts a game from the client's start message, retrying until
+// the client asks for a game that can be started.
+func (p *PlayerServer) startGame(ws *playerServerWS) (string, <-chan struct{}, error) {
+	for {
+		msg, err := ws.WaitForMsg(WSStart)
+		if err != nil {
+			return "", nil, err
+		}
+
+		// the registry sends the started message ahead of the game's alerts.
+		id, done, err := p.games.Start(msg.Players, msg.BlindStructure, ws, msg.Seats...)
+		if err != nil {
+			ws.SendError(err)
+			continue
+		}
+		return id, done, nil
+	}
 }
@@ -16,6 +16,7 @@ func TestRecordingWinsAndRetrievingThem(t *testing.T) {
 	server, _ := NewPlayerServer(store, dummyGame)
 	player := "Pepper"
 
+	server.ServeHTTP(httptest.NewRecorder(), newRegisterPlayerRequest(player, Profile{}))
 	server.ServeHTTP(httptest.NewRecorder(), newPostWinRequest(player))
 	server.ServeHTTP(httptest.NewRecorder(), newPostWinRequest(player))
 	server.ServeHTTP(httptest.NewRecorder(), newPostWinRequest(player))
@@ -37,7 +38,7 @@ func TestRecordingWinsAndRetrievingThem(t *testing.T) {
 		assertStatus(t, response.Code, http.StatusOK)
 		got := getLeagueFromResponse(t, response.Body)
 		want := []Player{
-			{"Pepper", 3},
+			{Name: "Pepper", Wins: 3},
 		}
 		assertLeague(t, got, want)
 	})
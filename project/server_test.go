@@ -1,21 +1,26 @@
 package poker
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/gorilla/websocket"
+	"learn-go-with-tests/clock"
 )
 
 const tenMS = 10 * time.Millisecond
 
 var (
-	dummyGame = &GameSpy{}
+	dummyGame = func(string) Game { return &GameSpy{} }
 )
 
 func TestGETPlayers(t *testing.T) {
@@ -75,6 +80,181 @@ func TestStoreWins(t *testing.T) {
 		}
 	})
 
+	t.Run("it returns 404 for wins of players who aren't registered", func(t *testing.T) {
+		store := &StubPlayerStore{mu: &sync.RWMutex{}, registered: map[string]Profile{"Pepper": {}}}
+		server, _ := NewPlayerServer(store, dummyGame)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, newPostWinRequest("Manu wins"))
+
+		assertErrorResponse(t, response, http.StatusNotFound, `"Manu wins" player is not registered`)
+		if len(store.winCalls) != 0 {
+			t.Errorf("got %d calls to RecordWin want none", len(store.winCalls))
+		}
+	})
+}
+
+func TestPlayers(t *testing.T) {
+	chris := Profile{DisplayName: "Chris James", Nickname: "CJ", Avatar: "https://example.com/cj.png"}
+
+	newServer := func() (*PlayerServer, *StubPlayerStore) {
+		store := &StubPlayerStore{mu: &sync.RWMutex{}, registered: map[string]Profile{"Chris": chris}}
+		server, _ := NewPlayerServer(store, dummyGame)
+		return server, store
+	}
+	serve := func(server *PlayerServer, method, target, body string) *httptest.ResponseRecorder {
+		request, _ := http.NewRequest(method, target, strings.NewReader(body))
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+		return response
+	}
+
+	t.Run("it registers players on POST /players", func(t *testing.T) {
+		server, store := newServer()
+		profile := Profile{Nickname: "Pep"}
+
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, newRegisterPlayerRequest("Pepper", profile))
+
+		assertStatus(t, response.Code, http.StatusCreated)
+		assertContentType(t, response, jsonContentType)
+		if got := response.Header().Get("Location"); got != "/players/Pepper/profile" {
+			t.Errorf("got Location %q want %q", got, "/players/Pepper/profile")
+		}
+		assertPlayerResponse(t, response, Player{Name: "Pepper", Profile: profile})
+		if got := store.registered["Pepper"]; got != profile {
+			t.Errorf("got profile %+v registered want %+v", got, profile)
+		}
+	})
+
+	t.Run("it refuses registrations it can't make", func(t *testing.T) {
+		cases := []struct {
+			name string
+			body string
+			want int
+		}{
+			{"already registered", `{"Name": "Chris"}`, http.StatusConflict},
+			{"invalid name", `{"Name": "Manu wins"}`, http.StatusBadRequest},
+			{"no name", `{"Nickname": "Pep"}`, http.StatusBadRequest},
+			{"not JSON", `Pepper`, http.StatusBadRequest},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				server, _ := newServer()
+				response := serve(server, http.MethodPost, "/players", c.body)
+				assertStatus(t, response.Code, c.want)
+			})
+		}
+	})
+
+	t.Run("it only registers players with POST", func(t *testing.T) {
+		server, _ := newServer()
+		response := serve(server, http.MethodGet, "/players", "")
+		assertStatus(t, response.Code, http.StatusMethodNotAllowed)
+	})
+
+	t.Run("it returns a player's profile", func(t *testing.T) {
+		server, _ := newServer()
+
+		response := serve(server, http.MethodGet, "/players/Chris/profile", "")
+		assertStatus(t, response.Code, http.StatusOK)
+		assertPlayerResponse(t, response, Player{Name: "Chris", Profile: chris})
+
+		response = serve(server, http.MethodGet, "/players/Apollo/profile", "")
+		assertStatus(t, response.Code, http.StatusNotFound)
+	})
+
+	t.Run("it updates profiles on PUT", func(t *testing.T) {
+		server, _ := newServer()
+
+		response := serve(server, http.MethodPut, "/players/Chris", `{"Nickname": "Chrissy"}`)
+		assertStatus(t, response.Code, http.StatusOK)
+		assertPlayerResponse(t, response, Player{Name: "Chris", Profile: Profile{Nickname: "Chrissy"}})
+
+		response = serve(server, http.MethodPut, "/players/Apollo", `{"Nickname": "Pol"}`)
+		assertStatus(t, response.Code, http.StatusNotFound)
+	})
+
+	t.Run("it deletes players on DELETE", func(t *testing.T) {
+		server, store := newServer()
+
+		response := serve(server, http.MethodDelete, "/players/Chris", "")
+		assertStatus(t, response.Code, http.StatusNoContent)
+		if _, ok := store.registered["Chris"]; ok {
+			t.Error("expected Chris to be deleted")
+		}
+
+		response = serve(server, http.MethodDelete, "/players/Chris", "")
+		assertStatus(t, response.Code, http.StatusNotFound)
+	})
+}
+
+func assertPlayerResponse(t testing.TB, response *httptest.ResponseRecorder, want Player) {
+	t.Helper()
+	var got Player
+	if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+		t.Fatalf("could not parse player from %q, %v", response.Body, err)
+	}
+	if got != want {
+		t.Errorf("got player %+v want %+v", got, want)
+	}
+}
+
+func TestStoreFailures(t *testing.T) {
+	store := NewStubPlayerStore(&sync.RWMutex{}, nil, nil, nil)
+	server, _ := NewPlayerServer(store, dummyGame)
+	store.err = errors.New("disk on fire")
+
+	requests := []struct {
+		method, target, body string
+	}{
+		{http.MethodGet, "/league", ""},
+		{http.MethodGet, "/players/Pepper", ""},
+		{http.MethodPost, "/players/Pepper", ""},
+		{http.MethodPost, "/players", `{"Name": "Pepper"}`},
+		{http.MethodGet, "/players/Pepper/profile", ""},
+		{http.MethodPut, "/players/Pepper", `{"Nickname": "Pep"}`},
+		{http.MethodDelete, "/players/Pepper", ""},
+		{http.MethodGet, "/players/Pepper/games", ""},
+		{http.MethodGet, "/players/Pepper/bankroll", ""},
+		{http.MethodPost, "/players/Pepper/bankroll", `{"type": "buy_in", "amount": 100}`},
+		{http.MethodGet, "/games/history", ""},
+		{http.MethodGet, "/stats/players/Pepper", ""},
+		{http.MethodGet, "/stats/h2h?a=Pepper&b=Chris", ""},
+	}
+	for _, r := range requests {
+		t.Run(r.method+" "+r.target, func(t *testing.T) {
+			request, _ := http.NewRequest(r.method, r.target, strings.NewReader(r.body))
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+
+			got := assertErrorResponse(t, response, http.StatusInternalServerError, "")
+			if strings.Contains(got, "disk on fire") {
+				t.Errorf("got error %q, the store's own error should be kept from clients", got)
+			}
+		})
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	server, _ := NewPlayerServer(NewStubPlayerStore(&sync.RWMutex{}, nil, nil, nil), dummyGame)
+
+	for target, allow := range map[string]string{
+		"/players":                 "POST",
+		"/players/Pepper":          "GET, POST, PUT, DELETE",
+		"/players/Pepper/games":    "GET",
+		"/players/Pepper/profile":  "GET",
+		"/players/Pepper/bankroll": "GET, POST",
+	} {
+		request, _ := http.NewRequest(http.MethodPatch, target, nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertErrorResponse(t, response, http.StatusMethodNotAllowed, "")
+		if got := response.Header().Get("Allow"); got != allow {
+			t.Errorf("got Allow %q for %s want %q", got, target, allow)
+		}
+	}
 }
 
 func TestNewPlayerStoreConcurrently(t *testing.T) {
@@ -105,9 +285,9 @@ func TestNewPlayerStoreConcurrently(t *testing.T) {
 func TestLeague(t *testing.T) {
 	t.Run("it returns the league table in JSON", func(t *testing.T) {
 		wantedLeague := []Player{
-			{"Cleo", 32},
-			{"Chris", 20},
-			{"Tiest", 14},
+			{Name: "Cleo", Wins: 32},
+			{Name: "Chris", Wins: 20},
+			{Name: "Tiest", Wins: 14},
 		}
 
 		store := NewStubPlayerStore(&sync.RWMutex{}, nil, nil, wantedLeague)
@@ -123,6 +303,573 @@ func TestLeague(t *testing.T) {
 		assertLeague(t, got, wantedLeague)
 		assertContentType(t, response, jsonContentType)
 	})
+
+	t.Run("it ranks the league by net chips with ?sort=chips", func(t *testing.T) {
+		store := NewStubPlayerStore(&sync.RWMutex{}, nil, nil, []Player{
+			{Name: "Cleo", Wins: 32, Chips: -500},
+			{Name: "Chris", Wins: 20, Chips: 1500},
+			{Name: "Tiest", Wins: 14},
+		})
+		server, _ := NewPlayerServer(store, dummyGame)
+
+		request, _ := http.NewRequest(http.MethodGet, "/league?sort=chips", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		assertLeague(t, getLeagueFromResponse(t, response.Body), []Player{
+			{Name: "Chris", Wins: 20, Chips: 1500},
+			{Name: "Tiest", Wins: 14},
+			{Name: "Cleo", Wins: 32, Chips: -500},
+		})
+	})
+
+	t.Run("it ranks the league by rating with ?sort=rating", func(t *testing.T) {
+		inner := newRegisteredPlayerStore(t, "Chris", "Cleo", "Tiest")
+		assertNoError(t, inner.RecordWin("Chris"))
+		assertNoError(t, inner.RecordWin("Chris"))
+		assertNoError(t, inner.RecordGame(GameRecord{Participants: []string{"Cleo", "Tiest"}, Winner: "Cleo"}))
+		store, _ := NewRatedPlayerStore(inner, Elo{K: 32})
+		server, _ := NewPlayerServer(store, dummyGame)
+
+		request, _ := http.NewRequest(http.MethodGet, "/league?sort=rating", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		assertLeague(t, getLeagueFromResponse(t, response.Body), []Player{
+			{Name: "Cleo", Rating: 1516},
+			{Name: "Chris", Wins: 2, Rating: 1500},
+			{Name: "Tiest", Rating: 1484},
+		})
+	})
+
+	t.Run("it can't rank by rating when the store doesn't rate players", func(t *testing.T) {
+		server, _ := NewPlayerServer(NewStubPlayerStore(&sync.RWMutex{}, nil, nil, nil), dummyGame)
+
+		request, _ := http.NewRequest(http.MethodGet, "/league?sort=rating", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertErrorResponse(t, response, http.StatusNotImplemented, ErrNotRated.Error())
+	})
+
+	t.Run("it filters, ranks and pages the league", func(t *testing.T) {
+		store := NewStubPlayerStore(&sync.RWMutex{}, nil, nil, []Player{
+			{Name: "Cleo", Wins: 32},
+			{Name: "Chris", Wins: 20},
+			{Name: "Tiest", Wins: 14},
+			{Name: "Carla", Wins: 3},
+		})
+		server, _ := NewPlayerServer(store, dummyGame)
+
+		cases := map[string][]string{
+			"/league?q=c":                            {"Cleo", "Chris", "Carla"},
+			"/league?q=Chr":                          {"Chris"},
+			"/league?sort=name":                      {"Carla", "Chris", "Cleo", "Tiest"},
+			"/league?sort=name&order=desc":           {"Tiest", "Cleo", "Chris", "Carla"},
+			"/league?order=asc":                      {"Carla", "Tiest", "Chris", "Cleo"},
+			"/league?limit=2":                        {"Cleo", "Chris"},
+			"/league?limit=2&offset=2":               {"Tiest", "Carla"},
+			"/league?offset=10":                      {},
+			"/league?q=c&sort=name&limit=2&offset=1": {"Chris", "Cleo"},
+		}
+		for target, want := range cases {
+			request, _ := http.NewRequest(http.MethodGet, target, nil)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+
+			assertStatus(t, response.Code, http.StatusOK)
+			got := []string{}
+			for _, player := range getLeagueFromResponse(t, response.Body) {
+				got = append(got, player.Name)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v from %s want %v", got, target, want)
+			}
+		}
+	})
+
+	t.Run("it links to the other pages of the league", func(t *testing.T) {
+		var league []Player
+		for i := 0; i < 5; i++ {
+			league = append(league, Player{Name: fmt.Sprintf("Player%d", i), Wins: 10 - i})
+		}
+		server, _ := NewPlayerServer(NewStubPlayerStore(&sync.RWMutex{}, nil, nil, league), dummyGame)
+
+		request, _ := http.NewRequest(http.MethodGet, "/league?limit=2&offset=2&q=player", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		want := strings.Join([]string{
+			`</league?limit=2&offset=0&q=player>; rel="first"`,
+			`</league?limit=2&offset=0&q=player>; rel="prev"`,
+			`</league?limit=2&offset=4&q=player>; rel="next"`,
+			`</league?limit=2&offset=4&q=player>; rel="last"`,
+		}, ", ")
+		if got := response.Header().Get("Link"); got != want {
+			t.Errorf("got Link %q want %q", got, want)
+		}
+		if got := response.Header().Get("X-Total-Count"); got != "5" {
+			t.Errorf("got X-Total-Count %q want 5", got)
+		}
+	})
+
+	t.Run("it ranks a page of the league from its offset", func(t *testing.T) {
+		var league []Player
+		for i := 0; i < 5; i++ {
+			league = append(league, Player{Name: fmt.Sprintf("Player%d", i), Wins: 10 - i})
+		}
+		server, _ := NewPlayerServer(NewStubPlayerStore(&sync.RWMutex{}, nil, nil, league), dummyGame)
+
+		for format, want := range map[string]string{
+			FormatCSV:  "\n3,Player2,8,",
+			FormatHTML: "<td>3</td>",
+		} {
+			request, _ := http.NewRequest(http.MethodGet, "/league?limit=2&offset=2&format="+format, nil)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+
+			assertStatus(t, response.Code, http.StatusOK)
+			if !strings.Contains(response.Body.String(), want) {
+				t.Errorf("expected %q in the %s page\n%s", want, format, response.Body.String())
+			}
+		}
+	})
+
+	t.Run("it replies 304 Not Modified while the league hasn't changed", func(t *testing.T) {
+		store := NewStubPlayerStore(&sync.RWMutex{}, nil, nil, []Player{{Name: "Cleo", Wins: 32}})
+		server, _ := NewPlayerServer(store, dummyGame)
+
+		get := func(etag string) *httptest.ResponseRecorder {
+			request := newLeagueRequest()
+			request.Header.Set("If-None-Match", etag)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+			return response
+		}
+
+		etag := get("").Header().Get("ETag")
+		if etag == "" {
+			t.Fatal("expected the league to have an ETag")
+		}
+
+		response := get(`"stale", ` + etag)
+		assertStatus(t, response.Code, http.StatusNotModified)
+		if response.Body.Len() != 0 {
+			t.Errorf("got body %q want none", response.Body.String())
+		}
+
+		store.league = append(store.league, Player{Name: "Chris", Wins: 20})
+		response = get(etag)
+		assertStatus(t, response.Code, http.StatusOK)
+		if response.Header().Get("ETag") == etag {
+			t.Error("expected the ETag to change with the league")
+		}
+	})
+
+	t.Run("it rejects bad pages and orders", func(t *testing.T) {
+		server, _ := NewPlayerServer(NewStubPlayerStore(&sync.RWMutex{}, nil, nil, nil), dummyGame)
+
+		for _, target := range []string{"/league?limit=0", "/league?limit=1000", "/league?offset=-1", "/league?limit=ten", "/league?order=up"} {
+			request, _ := http.NewRequest(http.MethodGet, target, nil)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+
+			assertErrorResponse(t, response, http.StatusBadRequest, "")
+		}
+	})
+
+	t.Run("it sends the league in the format the client accepts", func(t *testing.T) {
+		store := NewStubPlayerStore(&sync.RWMutex{}, nil, nil, []Player{{Name: "Cleo", Wins: 32}})
+		server, _ := NewPlayerServer(store, dummyGame)
+
+		cases := []struct {
+			target, accept, contentType string
+		}{
+			{"/league", "text/csv", LeagueFormats[FormatCSV]},
+			{"/league", "text/html,*/*;q=0.8", LeagueFormats[FormatHTML]},
+			{"/league", "text/markdown", LeagueFormats[FormatMarkdown]},
+			{"/league?format=csv", "application/json", LeagueFormats[FormatCSV]},
+			{"/league?format=json", "text/html", jsonContentType},
+		}
+		for _, c := range cases {
+			request, _ := http.NewRequest(http.MethodGet, c.target, nil)
+			request.Header.Set("Accept", c.accept)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+
+			assertStatus(t, response.Code, http.StatusOK)
+			assertContentType(t, response, c.contentType)
+			if response.Header().Get("Vary") != "Accept" {
+				t.Errorf("expected the league to vary by Accept, got %v", response.Header())
+			}
+		}
+
+		request, _ := http.NewRequest(http.MethodGet, "/league?format=csv", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+		if want := "Rank,Name,Wins,Chips,Rating,Display name,Nickname\n1,Cleo,32,0,,,\n"; response.Body.String() != want {
+			t.Errorf("got %q want %q", response.Body.String(), want)
+		}
+	})
+
+	t.Run("it rejects formats it can't send", func(t *testing.T) {
+		server, _ := NewPlayerServer(NewStubPlayerStore(&sync.RWMutex{}, nil, nil, nil), dummyGame)
+
+		request, _ := http.NewRequest(http.MethodGet, "/league", nil)
+		request.Header.Set("Accept", "image/png")
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+		assertErrorResponse(t, response, http.StatusNotAcceptable, "")
+
+		request, _ = http.NewRequest(http.MethodGet, "/league?format=xml", nil)
+		response = httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+		assertErrorResponse(t, response, http.StatusBadRequest, "")
+	})
+
+	t.Run("it rejects an unknown sort", func(t *testing.T) {
+		server, _ := NewPlayerServer(NewStubPlayerStore(&sync.RWMutex{}, nil, nil, nil), dummyGame)
+
+		request, _ := http.NewRequest(http.MethodGet, "/league?sort=luck", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertErrorResponse(t, response, http.StatusBadRequest, `unknown league sort "luck"`)
+	})
+}
+
+func TestBankroll(t *testing.T) {
+	store := NewStubPlayerStore(&sync.RWMutex{}, nil, nil, nil)
+	assertNoError(t, store.RegisterPlayer("Pepper", Profile{}))
+	server, _ := NewPlayerServer(store, dummyGame)
+
+	t.Run("POST /players/{name}/bankroll records a chip transaction", func(t *testing.T) {
+		request := newPostChipsRequest("Pepper", `{"game": "1", "type": "buy_in", "amount": 100}`)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusAccepted)
+		got := getChips(t, store, "Pepper")
+		if len(got) != 1 {
+			t.Fatalf("got %d chip transactions want 1", len(got))
+		}
+		if got[0].Game != "1" || got[0].Type != ChipBuyIn || got[0].Amount != 100 || got[0].At.IsZero() {
+			t.Errorf("recorded %+v", got[0])
+		}
+	})
+
+	t.Run("POST /players/{name}/bankroll rejects invalid transactions", func(t *testing.T) {
+		for _, body := range []string{`{"type": "bribe", "amount": 100}`, `{"type": "payout", "amount": -5}`, `not json`} {
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, newPostChipsRequest("Pepper", body))
+
+			assertErrorResponse(t, response, http.StatusBadRequest, "")
+		}
+	})
+
+	t.Run("POST /players/{name}/bankroll returns 404 for players who aren't registered", func(t *testing.T) {
+		store := &StubPlayerStore{mu: &sync.RWMutex{}, registered: map[string]Profile{"Pepper": {}}}
+		server, _ := NewPlayerServer(store, dummyGame)
+		response := httptest.NewRecorder()
+
+		server.ServeHTTP(response, newPostChipsRequest("Manu", `{"game": "1", "type": "buy_in", "amount": 100}`))
+
+		assertErrorResponse(t, response, http.StatusNotFound, `"Manu" player is not registered`)
+	})
+
+	t.Run("GET /players/{name}/bankroll returns their bankroll", func(t *testing.T) {
+		server.ServeHTTP(httptest.NewRecorder(), newPostChipsRequest("Pepper", `{"game": "1", "type": "payout", "amount": 250}`))
+
+		request, _ := http.NewRequest(http.MethodGet, "/players/Pepper/bankroll", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		assertContentType(t, response, jsonContentType)
+
+		var got Bankroll
+		if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+			t.Fatalf("Unable to parse response from server into bankroll, '%v'", err)
+		}
+		stack := ChipStack{BuyIns: 100, Payouts: 250, Net: 150}
+		want := Bankroll{Player: "Pepper", ChipStack: stack, Games: []GameStack{{Game: "1", ChipStack: stack}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got bankroll %+v want %+v", got, want)
+		}
+	})
+
+	t.Run("GET /players/{name}/bankroll returns 404 for players who aren't registered", func(t *testing.T) {
+		store := &StubPlayerStore{mu: &sync.RWMutex{}, registered: map[string]Profile{"Pepper": {}}}
+		server, _ := NewPlayerServer(store, dummyGame)
+
+		request, _ := http.NewRequest(http.MethodGet, "/players/Manu/bankroll", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertErrorResponse(t, response, http.StatusNotFound, `"Manu" player is not registered`)
+	})
+}
+
+func newPostChipsRequest(name, body string) *http.Request {
+	req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/players/%s/bankroll", name), strings.NewReader(body))
+	return req
+}
+
+func TestGameHistory(t *testing.T) {
+	chrisWins := GameRecord{ID: "1", Participants: []string{"Chris", "Cleo"}, Winner: "Chris"}
+	cleoWins := GameRecord{ID: "2", Participants: []string{"Cleo", "Tiest"}, Winner: "Cleo"}
+	store := NewStubPlayerStore(&sync.RWMutex{}, nil, nil, nil)
+	store.games = []GameRecord{chrisWins, cleoWins}
+	server, _ := NewPlayerServer(store, dummyGame)
+
+	t.Run("GET /games/history returns every game", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/games/history", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		assertContentType(t, response, jsonContentType)
+		assertGames(t, getGamesFromResponse(t, response.Body), []GameRecord{chrisWins, cleoWins})
+	})
+
+	t.Run("GET /players/{name}/games returns the games they played", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/players/Tiest/games", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		assertGames(t, getGamesFromResponse(t, response.Body), []GameRecord{cleoWins})
+	})
+}
+
+func TestStats(t *testing.T) {
+	store := &StubPlayerStore{mu: &sync.RWMutex{}, registered: map[string]Profile{"Chris": {}, "Cleo": {}, "Tiest": {}}}
+	store.games = []GameRecord{
+		{Participants: []string{"Chris", "Cleo"}, FinishingOrder: []string{"Chris", "Cleo"}, Winner: "Chris"},
+		{Participants: []string{"Chris", "Cleo"}, FinishingOrder: []string{"Cleo", "Chris"}, Winner: "Cleo"},
+		{Participants: []string{"Chris", "Cleo"}, FinishingOrder: []string{"Chris", "Cleo"}, Winner: "Chris"},
+	}
+	server, _ := NewPlayerServer(store, dummyGame)
+
+	t.Run("GET /stats/players/{name} returns their stats", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/stats/players/Chris", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		assertContentType(t, response, jsonContentType)
+
+		var got PlayerStats
+		if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+			t.Fatalf("Unable to parse response from server into stats, '%v'", err)
+		}
+		want := NewPlayerStats("Chris", store.games)
+		if got != want {
+			t.Errorf("got stats %+v want %+v", got, want)
+		}
+	})
+
+	t.Run("GET /stats/players/{name} returns empty stats for players yet to play", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/stats/players/Tiest", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+	})
+
+	t.Run("GET /stats/players/{name} returns 404 for unknown players", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/stats/players/Nobody", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertErrorResponse(t, response, http.StatusNotFound, "")
+	})
+
+	t.Run("GET /stats/h2h returns how two players did against each other", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/stats/h2h?a=Chris&b=Cleo", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+
+		var got HeadToHead
+		if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+			t.Fatalf("Unable to parse response from server into head to head, '%v'", err)
+		}
+		want := HeadToHead{A: "Chris", B: "Cleo", Games: 3, AWins: 2, BWins: 1, AFinishedAhead: 2, BFinishedAhead: 1}
+		if got != want {
+			t.Errorf("got head to head %+v want %+v", got, want)
+		}
+	})
+
+	t.Run("GET /stats/h2h needs two different players", func(t *testing.T) {
+		for _, target := range []string{"/stats/h2h", "/stats/h2h?a=Chris", "/stats/h2h?a=Chris&b=Chris"} {
+			request, _ := http.NewRequest(http.MethodGet, target, nil)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+
+			assertErrorResponse(t, response, http.StatusBadRequest, "")
+		}
+	})
+}
+
+func TestRatings(t *testing.T) {
+	inner := newRegisteredPlayerStore(t, "Chris", "Cleo")
+	store, _ := NewRatedPlayerStore(inner, Elo{K: 32})
+	server, _ := NewPlayerServer(store, dummyGame)
+	chrisWins := GameRecord{ID: "1", Participants: []string{"Chris", "Cleo"}, Winner: "Chris"}
+
+	getHistory := func(t *testing.T, name string) (history []RatingChange) {
+		t.Helper()
+		request, _ := http.NewRequest(http.MethodGet, "/players/"+name+"/ratings", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		if err := json.NewDecoder(response.Body).Decode(&history); err != nil {
+			t.Fatalf("Unable to parse response from server into rating history, '%v'", err)
+		}
+		return
+	}
+
+	t.Run("GET /players/{name}/ratings returns how their rating changed", func(t *testing.T) {
+		assertNoError(t, store.RecordGame(chrisWins))
+
+		want := []RatingChange{{Game: "1", Rating: 1516, Change: 16}}
+		if got := getHistory(t, "Chris"); !reflect.DeepEqual(got, want) {
+			t.Errorf("got history %+v want %+v", got, want)
+		}
+	})
+
+	t.Run("GET /players/{name}/ratings returns 404 for unknown players", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/players/Nobody/ratings", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertErrorResponse(t, response, http.StatusNotFound, "")
+	})
+
+	t.Run("POST /ratings/recompute rates every game in the history again", func(t *testing.T) {
+		assertNoError(t, inner.RecordGame(GameRecord{ID: "2", Participants: []string{"Chris", "Cleo"}, Winner: "Chris"}))
+
+		request, _ := http.NewRequest(http.MethodPost, "/ratings/recompute", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusNoContent)
+		if got := getHistory(t, "Chris"); len(got) != 2 {
+			t.Errorf("got %d rating changes want 2", len(got))
+		}
+	})
+
+	t.Run("ratings aren't served when the store doesn't rate players", func(t *testing.T) {
+		server, _ := NewPlayerServer(NewStubPlayerStore(&sync.RWMutex{}, nil, nil, nil), dummyGame)
+
+		for _, request := range []*http.Request{
+			httptest.NewRequest(http.MethodGet, "/players/Chris/ratings", nil),
+			httptest.NewRequest(http.MethodPost, "/ratings/recompute", nil),
+		} {
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+
+			assertErrorResponse(t, response, http.StatusNotImplemented, ErrNotRated.Error())
+		}
+	})
+}
+
+func TestSeasonLeagues(t *testing.T) {
+	inner := newRegisteredPlayerStore(t, "Chris", "Cleo")
+	rated, _ := NewRatedPlayerStore(inner, Elo{K: 32})
+	store := NewSeasonalPlayerStore(rated, nil, clock.NewFake(time.Date(2026, 10, 2, 20, 0, 0, 0, time.UTC)))
+	assertNoError(t, inner.RecordGame(GameRecord{EndedAt: time.Date(2026, 9, 1, 22, 0, 0, 0, time.UTC), Participants: []string{"Chris", "Cleo"}, Winner: "Cleo"}))
+	assertNoError(t, store.RecordGame(GameRecord{EndedAt: time.Date(2026, 10, 1, 22, 0, 0, 0, time.UTC), Participants: []string{"Chris", "Cleo"}, Winner: "Chris"}))
+	server, _ := NewPlayerServer(store, dummyGame)
+
+	t.Run("GET /league?season= returns the standings of the season", func(t *testing.T) {
+		for season, want := range map[string][]Player{
+			"2026Q3": {{Name: "Cleo", Wins: 1}, {Name: "Chris"}},
+			"2026Q4": {{Name: "Chris", Wins: 1}, {Name: "Cleo"}},
+			"2025Q1": {},
+		} {
+			request, _ := http.NewRequest(http.MethodGet, "/league?season="+season, nil)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+
+			assertStatus(t, response.Code, http.StatusOK)
+			assertLeague(t, getLeagueFromResponse(t, response.Body), want)
+		}
+	})
+
+	t.Run("GET /league?season= rejects unknown seasons and other rankings", func(t *testing.T) {
+		cases := map[string]int{
+			"/league?season=summer":            http.StatusNotFound,
+			"/league?season=2026Q3&sort=chips": http.StatusBadRequest,
+		}
+		for target, status := range cases {
+			request, _ := http.NewRequest(http.MethodGet, target, nil)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+
+			assertErrorResponse(t, response, status, "")
+		}
+	})
+
+	t.Run("GET /seasons lists the seasons and which have finished", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/seasons", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		var got []SeasonStatus
+		if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+			t.Fatalf("Unable to parse response from server into seasons, '%v'", err)
+		}
+		if len(got) != 2 || got[0].Name != "2026Q3" || !got[0].Finished || got[1].Name != "2026Q4" || got[1].Finished {
+			t.Errorf("got seasons %+v", got)
+		}
+	})
+
+	t.Run("ratings are found in the store the seasons wrap", func(t *testing.T) {
+		request, _ := http.NewRequest(http.MethodGet, "/league?sort=rating", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+	})
+
+	t.Run("seasons aren't served when the store doesn't have them", func(t *testing.T) {
+		server, _ := NewPlayerServer(NewStubPlayerStore(&sync.RWMutex{}, nil, nil, nil), dummyGame)
+
+		for _, target := range []string{"/league?season=2026Q3", "/seasons"} {
+			request, _ := http.NewRequest(http.MethodGet, target, nil)
+			response := httptest.NewRecorder()
+			server.ServeHTTP(response, request)
+
+			assertErrorResponse(t, response, http.StatusNotImplemented, ErrNoSeasons.Error())
+		}
+	})
+
+	t.Run("POST /players/{name} counts the win in the season being played", func(t *testing.T) {
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, newPostWinRequest("Cleo"))
+		assertStatus(t, response.Code, http.StatusAccepted)
+
+		request, _ := http.NewRequest(http.MethodGet, "/league?season=2026Q4", nil)
+		response = httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+		assertLeague(t, getLeagueFromResponse(t, response.Body), []Player{{Name: "Chris", Wins: 1}, {Name: "Cleo", Wins: 1}})
+	})
+}
+
+func getGamesFromResponse(t testing.TB, body io.Reader) (games []GameRecord) {
+	t.Helper()
+	if err := json.NewDecoder(body).Decode(&games); err != nil {
+		t.Fatalf("Unable to parse response from server into games, '%v'", err)
+	}
+	return
 }
 
 func TestGame(t *testing.T) {
@@ -149,17 +896,414 @@ func TestGame(t *testing.T) {
 		defer server.Close()
 		defer ws.Close()
 
-		writeWSMessage(t, ws, "3")
-		writeWSMessage(t, ws, winner)
+		writeWSJSON(t, ws, WSMessage{Type: WSStart, Players: 3})
+		writeWSJSON(t, ws, WSMessage{Type: WSFinish, Winner: winner})
 
 		// time.Sleep(tenMS)
 		assertGameStartedWith(t, game, 3)
 		assertGameFinishCalledWith(t, game, winner)
 		within(t, tenMS, func() {
-			assertWebsocketGotMsg(t, ws, wantedBlindAlert)
+			assertWebsocketGotMsg(t, ws, WSMessage{Type: WSStarted, Game: "1"})
+			assertWebsocketGotMsg(t, ws, WSMessage{Type: WSText, Text: wantedBlindAlert})
+		})
+	})
+
+	t.Run("closing the websocket cancels the game", func(t *testing.T) {
+		game := &GameSpy{}
+		server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+		defer server.Close()
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+
+		writeWSJSON(t, ws, WSMessage{Type: WSStart, Players: 3})
+		assertGameStartedWith(t, game, 3)
+
+		_ = ws.Close()
+
+		passed := retryUntil(500*time.Millisecond, func() bool {
+			return game.StartCtx != nil && game.StartCtx.Err() != nil
 		})
+		if !passed {
+			t.Error("expected the game's context to be cancelled")
+		}
+		assertGameNotFinished(t, game)
 	})
 
+	t.Run("a second client joins a game by ID and declares the winner", func(t *testing.T) {
+		game := &GameSpy{BlindAlert: []byte("Blind is 100")}
+		server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+		defer server.Close()
+		wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+		host := mustDialWS(t, wsURL)
+		defer host.Close()
+		writeWSJSON(t, host, WSMessage{Type: WSStart, Players: 3})
+		within(t, 100*time.Millisecond, func() {
+			assertWebsocketGotMsg(t, host, WSMessage{Type: WSStarted, Game: "1"})
+			assertWebsocketGotMsg(t, host, WSMessage{Type: WSText, Text: "Blind is 100"})
+		})
+
+		watcher := mustDialWS(t, wsURL+"?game=1")
+		defer watcher.Close()
+		assertGameClients(t, server.URL, "1", 2)
+
+		writeWSJSON(t, watcher, WSMessage{Type: WSFinish, Winner: "Manu"})
+		assertGameFinishCalledWith(t, game, "Manu")
+	})
+
+	t.Run("invalid messages get an error reply and the game can still start", func(t *testing.T) {
+		game := &GameSpy{}
+		server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+		defer server.Close()
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSMessage(t, ws, "3")
+		assertWebsocketGotType(t, ws, WSError)
+
+		writeWSJSON(t, ws, WSMessage{Type: WSStart, Players: 0})
+		assertWebsocketGotMsg(t, ws, WSMessage{Type: WSError, Error: BadPlayerInputErrMsg})
+
+		writeWSJSON(t, ws, WSMessage{Type: WSFinish, Winner: "Manu"})
+		assertWebsocketGotType(t, ws, WSError)
+		assertGameNotStarted(t, game)
+
+		writeWSJSON(t, ws, WSMessage{Type: WSStart, Players: 4, BlindStructure: "turbo"})
+		assertGameStartedWith(t, game, 4)
+	})
+
+	t.Run("clients pause and resume the game", func(t *testing.T) {
+		game := &GameSpy{}
+		server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+		defer server.Close()
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSJSON(t, ws, WSMessage{Type: WSStart, Players: 3})
+		within(t, 100*time.Millisecond, func() {
+			assertWebsocketGotMsg(t, ws, WSMessage{Type: WSStarted, Game: "1"})
+			assertWebsocketGotType(t, ws, WSText)
+		})
+
+		writeWSJSON(t, ws, WSMessage{Type: WSPause})
+		writeWSJSON(t, ws, WSMessage{Type: WSResume})
+		writeWSJSON(t, ws, WSMessage{Type: WSFinish, Winner: "Manu"})
+
+		assertGameFinishCalledWith(t, game, "Manu")
+		assertGameCommands(t, game, "pause", "resume")
+	})
+
+	t.Run("clients are told when the game can't be paused", func(t *testing.T) {
+		game := &GameSpy{CommandError: ErrGamePaused}
+		server := httptest.NewServer(mustMakePlayerServer(t, dummyPlayerStore, game))
+		defer server.Close()
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSJSON(t, ws, WSMessage{Type: WSStart, Players: 3})
+		within(t, 100*time.Millisecond, func() {
+			assertWebsocketGotType(t, ws, WSStarted)
+			assertWebsocketGotType(t, ws, WSText)
+		})
+
+		writeWSJSON(t, ws, WSMessage{Type: WSPause})
+		within(t, 100*time.Millisecond, func() {
+			assertWebsocketGotMsg(t, ws, WSMessage{Type: WSError, Error: ErrGamePaused.Error()})
+		})
+	})
+
+	t.Run("blind alerts arrive as blind messages", func(t *testing.T) {
+		server := httptest.NewServer(mustMakePlayerServerWith(t, dummyPlayerStore, func(string) Game {
+			return NewTexasHoldem(BlinderAlerterFunc(Alerter), dummyPlayerStore)
+		}))
+		defer server.Close()
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSJSON(t, ws, WSMessage{Type: WSStart, Players: 5})
+
+		// the client is told which game it started before the first blind.
+		within(t, 500*time.Millisecond, func() {
+			assertWebsocketGotMsg(t, ws, WSMessage{Type: WSStarted, Game: "1"})
+			assertWebsocketGotMsg(t, ws, WSMessage{Type: WSBlind, Amount: 100})
+		})
+	})
+
+	t.Run("a finished game is recorded under the ID it was played as", func(t *testing.T) {
+		store := newRegisteredPlayerStore(t, "Manu")
+		assertNoError(t, store.RecordGame(GameRecord{ID: "7", Winner: "Manu"}))
+		server := httptest.NewServer(mustMakePlayerServerWith(t, store, func(id string) Game {
+			return NewTexasHoldem(dummyBlindAlerter, store, WithGameID(id))
+		}))
+		defer server.Close()
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSJSON(t, ws, WSMessage{Type: WSStart, Players: 3})
+		within(t, 500*time.Millisecond, func() {
+			assertWebsocketGotMsg(t, ws, WSMessage{Type: WSStarted, Game: "8"})
+		})
+		writeWSJSON(t, ws, WSMessage{Type: WSFinish, Winner: "Manu"})
+
+		passed := retryUntil(500*time.Millisecond, func() bool {
+			games, _ := store.GetGames()
+			return len(games) == 2
+		})
+		if !passed {
+			t.Fatal("expected the game to be recorded")
+		}
+		if got := getGames(t, store)[1]; got.ID != "8" {
+			t.Errorf("got game recorded as %q want %q", got.ID, "8")
+		}
+	})
+
+	t.Run("a finished game rates everyone seated at it", func(t *testing.T) {
+		rated, err := NewRatedPlayerStore(newRegisteredPlayerStore(t, "Chris", "Cleo", "Manu"), Elo{K: 32})
+		assertNoError(t, err)
+		server := httptest.NewServer(mustMakePlayerServerWith(t, rated, func(id string) Game {
+			return NewTexasHoldem(dummyBlindAlerter, rated, WithGameID(id))
+		}))
+		defer server.Close()
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSJSON(t, ws, WSMessage{Type: WSStart, Seats: []string{"Chris", "Cleo", "Manu"}})
+		within(t, 500*time.Millisecond, func() {
+			assertWebsocketGotMsg(t, ws, WSMessage{Type: WSStarted, Game: "1"})
+		})
+		writeWSJSON(t, ws, WSMessage{Type: WSFinish, Winner: "Manu"})
+
+		passed := retryUntil(500*time.Millisecond, func() bool {
+			games, _ := rated.GetGames()
+			return len(games) == 1
+		})
+		if !passed {
+			t.Fatal("expected the game to be recorded")
+		}
+
+		response, err := http.Get(server.URL + "/league?sort=rating")
+		assertNoError(t, err)
+		defer response.Body.Close()
+		league := getLeagueFromResponse(t, response.Body)
+		if len(league) != 3 || league[0].Name != "Manu" {
+			t.Fatalf("got league %+v want Manu rated first of the three", league)
+		}
+		for _, player := range league[1:] {
+			if player.Rating >= InitialRating {
+				t.Errorf("got %s rated %v want them below %v after losing to Manu", player.Name, player.Rating, InitialRating)
+			}
+		}
+		if league[0].Rating <= InitialRating {
+			t.Errorf("got Manu rated %v want above %v after winning", league[0].Rating, InitialRating)
+		}
+	})
+
+	t.Run("clients deal hands, bet on them and show them down", func(t *testing.T) {
+		store := newRegisteredPlayerStore(t, "Chris", "Cleo")
+		server := httptest.NewServer(mustMakePlayerServerWith(t, store, func(id string) Game {
+			return NewTexasHoldem(dummyBlindAlerter, store, WithDeck(NewDeck))
+		}))
+		defer server.Close()
+		wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+		chris := mustDialWS(t, wsURL+"?player=Chris")
+		defer chris.Close()
+		writeWSJSON(t, chris, WSMessage{Type: WSStart, Seats: []string{"Chris", "Cleo"}})
+		within(t, 500*time.Millisecond, func() {
+			assertWebsocketGotMsg(t, chris, WSMessage{Type: WSStarted, Game: "1"})
+		})
+		cleo := mustDialWS(t, wsURL+"?game=1&player=Cleo")
+		defer cleo.Close()
+
+		// readBoth reads n messages sent to both players, returning the last of them.
+		readBoth := func(t *testing.T, n int) (last WSMessage) {
+			t.Helper()
+			within(t, 500*time.Millisecond, func() {
+				for _, ws := range []*websocket.Conn{chris, cleo} {
+					for i := 0; i < n; i++ {
+						last = readWSJSON(t, ws)
+					}
+				}
+			})
+			return last
+		}
+
+		writeWSJSON(t, chris, WSMessage{Type: WSShowdown})
+		within(t, 500*time.Millisecond, func() {
+			assertWebsocketGotMsg(t, chris, WSMessage{Type: WSError, Error: ErrNoHand.Error()})
+		})
+
+		writeWSJSON(t, chris, WSMessage{Type: WSDeal})
+		if got := readBoth(t, 2); got.Type != WSBetting || got.Betting == nil || got.Betting.ToAct != "Chris" || got.Betting.ToCall != 100 {
+			t.Errorf("got %+v want Chris to act, 100 to call", got)
+		}
+
+		writeWSJSON(t, cleo, WSMessage{Type: WSAction, Action: ActionCheck})
+		within(t, 500*time.Millisecond, func() {
+			err := &IllegalActionError{Action{Player: "Cleo", Type: ActionCheck}, ErrNotYourTurn}
+			assertWebsocketGotMsg(t, cleo, WSMessage{Type: WSError, Error: err.Error()})
+		})
+
+		writeWSJSON(t, chris, WSMessage{Type: WSAction, Action: ActionAllIn})
+		readBoth(t, 1)
+		writeWSJSON(t, cleo, WSMessage{Type: WSAction, Action: ActionCall})
+		if got := readBoth(t, 1); got.Type != WSBetting || got.Betting.ToAct != "" || len(got.Betting.Pots) != 1 || got.Betting.Pots[0].Amount != 20000 {
+			t.Errorf("got %+v want the betting over with everything in the pot", got)
+		}
+
+		for i := 0; i < 3; i++ {
+			writeWSJSON(t, chris, WSMessage{Type: WSDeal})
+			readBoth(t, 2)
+		}
+
+		writeWSJSON(t, chris, WSMessage{Type: WSShowdown})
+		got := readBoth(t, 1)
+		if got.Type != WSResults || len(got.Results) != 2 || !reflect.DeepEqual(got.Winners, []string{"Chris", "Cleo"}) {
+			t.Fatalf("got %+v want Chris and Cleo to split the pot", got)
+		}
+		if got := formatCards(got.Results[1].Hole); got != "3c 5c" {
+			t.Errorf("got Cleo showing %s want 3c 5c", got)
+		}
+	})
+
+	t.Run("clients can only act for the player they join as", func(t *testing.T) {
+		store := newRegisteredPlayerStore(t, "Chris", "Cleo")
+		server := httptest.NewServer(mustMakePlayerServerWith(t, store, func(id string) Game {
+			return NewTexasHoldem(dummyBlindAlerter, store, WithDeck(NewDeck))
+		}))
+		defer server.Close()
+		wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+		cleo := mustDialWS(t, wsURL+"?player=Cleo")
+		defer cleo.Close()
+		writeWSJSON(t, cleo, WSMessage{Type: WSStart, Seats: []string{"Chris", "Cleo"}})
+		within(t, 500*time.Millisecond, func() {
+			assertWebsocketGotMsg(t, cleo, WSMessage{Type: WSStarted, Game: "1"})
+		})
+		watcher := mustDialWS(t, wsURL+"?game=1")
+		defer watcher.Close()
+
+		writeWSJSON(t, cleo, WSMessage{Type: WSDeal})
+		within(t, 500*time.Millisecond, func() {
+			for i := 0; i < 2; i++ {
+				readWSJSON(t, cleo)
+				readWSJSON(t, watcher)
+			}
+		})
+
+		writeWSJSON(t, cleo, WSMessage{Type: WSAction, Player: "Chris", Action: ActionFold})
+		within(t, 500*time.Millisecond, func() {
+			assertWebsocketGotMsg(t, cleo, WSMessage{Type: WSError, Error: ErrWrongSeat.Error()})
+		})
+		writeWSJSON(t, watcher, WSMessage{Type: WSAction, Player: "Chris", Action: ActionFold})
+		within(t, 500*time.Millisecond, func() {
+			assertWebsocketGotMsg(t, watcher, WSMessage{Type: WSError, Error: ErrNoSeat.Error()})
+		})
+	})
+
+	t.Run("clients only see the hole cards of the seat they join as", func(t *testing.T) {
+		store := newRegisteredPlayerStore(t, "Chris", "Cleo")
+		server := httptest.NewServer(mustMakePlayerServerWith(t, store, func(id string) Game {
+			return NewTexasHoldem(dummyBlindAlerter, store, WithDeck(NewDeck))
+		}))
+		defer server.Close()
+		wsURL := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+
+		chris := mustDialWS(t, wsURL+"?player=Chris")
+		defer chris.Close()
+		writeWSJSON(t, chris, WSMessage{Type: WSStart, Seats: []string{"Chris", "Cleo"}})
+		within(t, 500*time.Millisecond, func() {
+			assertWebsocketGotMsg(t, chris, WSMessage{Type: WSStarted, Game: "1"})
+		})
+		cleo := mustDialWS(t, wsURL+"?game=1&player=Cleo")
+		defer cleo.Close()
+		watcher := mustDialWS(t, wsURL+"?game=1")
+		defer watcher.Close()
+
+		writeWSJSON(t, chris, WSMessage{Type: WSDeal})
+		for _, client := range []struct {
+			ws   *websocket.Conn
+			hole string
+		}{{chris, "2c 4c"}, {cleo, "3c 5c"}, {watcher, ""}} {
+			within(t, 500*time.Millisecond, func() {
+				got := readWSJSON(t, client.ws)
+				if got.Type != WSHand || got.Hand == nil {
+					t.Fatalf("got %+v want the hand dealt", got)
+				}
+				if hole := formatCards(got.Hand.Hole); hole != client.hole {
+					t.Errorf("got hole cards %q want %q", hole, client.hole)
+				}
+				if !reflect.DeepEqual(got.Hand.HoleCards, map[string]int{"Chris": 2, "Cleo": 2}) {
+					t.Errorf("got hole card counts %v want 2 each", got.Hand.HoleCards)
+				}
+			})
+		}
+	})
+
+	t.Run("warnings arrive as warning messages", func(t *testing.T) {
+		fake := clock.NewFake(time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC))
+		server := httptest.NewServer(mustMakePlayerServerWith(t, dummyPlayerStore, func(string) Game {
+			return NewTexasHoldem(NewAlerter(fake), dummyPlayerStore, WithClock(fake))
+		}))
+		defer server.Close()
+		ws := mustDialWS(t, "ws"+strings.TrimPrefix(server.URL, "http")+"/ws")
+		defer ws.Close()
+
+		writeWSJSON(t, ws, WSMessage{Type: WSStart, Players: 5})
+		within(t, 500*time.Millisecond, func() {
+			assertWebsocketGotMsg(t, ws, WSMessage{Type: WSStarted, Game: "1"})
+		})
+
+		fake.Advance(9 * time.Minute)
+		within(t, 500*time.Millisecond, func() {
+			assertWebsocketGotMsg(t, ws, WSMessage{Type: WSBlind, Amount: 100})
+			assertWebsocketGotMsg(t, ws, WSMessage{Type: WSWarning, Amount: 200, Seconds: 60})
+		})
+	})
+}
+
+func TestGames(t *testing.T) {
+	t.Run("GET /games lists no games before any start", func(t *testing.T) {
+		server, _ := NewPlayerServer(dummyPlayerStore, dummyGame)
+
+		request, _ := http.NewRequest(http.MethodGet, "/games", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertStatus(t, response.Code, http.StatusOK)
+		assertContentType(t, response, jsonContentType)
+		assertResponseBody(t, response.Body.String(), "[]\n")
+	})
+
+	t.Run("GET /games/{id} returns 404 for unknown games", func(t *testing.T) {
+		server, _ := NewPlayerServer(dummyPlayerStore, dummyGame)
+
+		request, _ := http.NewRequest(http.MethodGet, "/games/42", nil)
+		response := httptest.NewRecorder()
+		server.ServeHTTP(response, request)
+
+		assertErrorResponse(t, response, http.StatusNotFound, ErrGameNotFound.Error())
+	})
+}
+
+func assertGameClients(t testing.TB, serverURL, id string, want int) {
+	t.Helper()
+
+	var got GameInfo
+	passed := retryUntil(500*time.Millisecond, func() bool {
+		response, err := http.Get(serverURL + "/games/" + id)
+		if err != nil {
+			return false
+		}
+		defer response.Body.Close()
+		if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+			return false
+		}
+		return got.Clients == want
+	})
+
+	if !passed {
+		t.Errorf("got %d clients in game %s want %d", got.Clients, id, want)
+	}
 }
 
 func within(t testing.TB, d time.Duration, assert func()) {
@@ -179,19 +1323,44 @@ func within(t testing.TB, d time.Duration, assert func()) {
 	}
 }
 
-func assertWebsocketGotMsg(t *testing.T, ws *websocket.Conn, want string) {
-	_, msg, _ := ws.ReadMessage()
-	if string(msg) != want {
-		t.Errorf(`got "%s", want "%s"`, string(msg), want)
+func assertWebsocketGotMsg(t *testing.T, ws *websocket.Conn, want WSMessage) {
+	t.Helper()
+	assertWSMessage(t, readWSJSON(t, ws), want)
+}
+
+func assertWebsocketGotType(t *testing.T, ws *websocket.Conn, want string) {
+	t.Helper()
+	if got := readWSJSON(t, ws); got.Type != want {
+		t.Errorf("got message %+v, want type %q", got, want)
 	}
 }
 
+func assertWSMessage(t testing.TB, got, want WSMessage) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func readWSJSON(t testing.TB, ws *websocket.Conn) WSMessage {
+	t.Helper()
+	var msg WSMessage
+	if err := ws.ReadJSON(&msg); err != nil {
+		t.Errorf("could not read message from ws connection %v", err)
+	}
+	return msg
+}
+
 func NewGameRequest() (*http.Request, error) {
 	return http.NewRequest(http.MethodGet, "/game", nil)
 }
 
 func mustMakePlayerServer(t *testing.T, store PlayerStore, game *GameSpy) *PlayerServer {
-	server, err := NewPlayerServer(store, dummyGame)
+	return mustMakePlayerServerWith(t, store, func(string) Game { return game })
+}
+
+func mustMakePlayerServerWith(t *testing.T, store PlayerStore, newGame func(id string) Game) *PlayerServer {
+	server, err := NewPlayerServer(store, newGame)
 	if err != nil {
 		t.Fatal("problem creating player server", err)
 	}
@@ -208,6 +1377,13 @@ func mustDialWS(t *testing.T, url string) *websocket.Conn {
 	return ws
 }
 
+func writeWSJSON(t testing.TB, conn *websocket.Conn, msg WSMessage) {
+	t.Helper()
+	if err := conn.WriteJSON(msg); err != nil {
+		t.Fatalf("could not send message over ws connection %v", err)
+	}
+}
+
 func writeWSMessage(t testing.TB, conn *websocket.Conn, message string) {
 	t.Helper()
 	if err := conn.WriteMessage(websocket.TextMessage, []byte(message)); err != nil {
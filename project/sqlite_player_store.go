@@ -0,0 +1,417 @@
+package poker
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	// registers the pure Go "sqlite" database/sql driver.
+	_ "modernc.org/sqlite"
+)
+
+// sqliteMigrations are applied in order, the schema version is kept in PRAGMA user_version.
+var sqliteMigrations = []string{
+	`CREATE TABLE players (
+		name TEXT PRIMARY KEY,
+		wins INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX players_by_wins ON players (wins DESC, name);`,
+
+	`CREATE TABLE games (
+		id TEXT PRIMARY KEY,
+		started_at TEXT NOT NULL,
+		ended_at TEXT NOT NULL,
+		blind_structure TEXT NOT NULL,
+		winner TEXT NOT NULL,
+		finishing_order TEXT NOT NULL
+	);
+	CREATE INDEX games_by_end ON games (ended_at);
+	CREATE TABLE game_players (
+		game_id TEXT NOT NULL REFERENCES games (id),
+		seat INTEGER NOT NULL,
+		name TEXT NOT NULL,
+		PRIMARY KEY (game_id, seat)
+	);
+	CREATE INDEX game_players_by_name ON game_players (name);`,
+
+	`ALTER TABLE players ADD COLUMN chips INTEGER NOT NULL DEFAULT 0;
+	CREATE TABLE chip_transactions (
+		player TEXT NOT NULL,
+		game_id TEXT NOT NULL,
+		type TEXT NOT NULL,
+		amount INTEGER NOT NULL,
+		at TEXT NOT NULL
+	);
+	CREATE INDEX chip_transactions_by_player ON chip_transactions (player);`,
+
+	`ALTER TABLE games ADD COLUMN eliminations TEXT NOT NULL DEFAULT 'null';
+	ALTER TABLE games ADD COLUMN rebuys TEXT NOT NULL DEFAULT 'null';`,
+
+	`ALTER TABLE players ADD COLUMN display_name TEXT NOT NULL DEFAULT '';
+	ALTER TABLE players ADD COLUMN nickname TEXT NOT NULL DEFAULT '';
+	ALTER TABLE players ADD COLUMN avatar TEXT NOT NULL DEFAULT '';`,
+}
+
+// SQLitePlayerStore stores players and games in a SQLite database.
+type SQLitePlayerStore struct {
+	db *sql.DB
+}
+
+// NewSQLitePlayerStore migrates db to the latest schema and returns a store using it.
+func NewSQLitePlayerStore(db *sql.DB) (*SQLitePlayerStore, error) {
+	if err := migrateSQLite(db); err != nil {
+		return nil, fmt.Errorf("problem migrating sqlite database, %v", err)
+	}
+	return &SQLitePlayerStore{db}, nil
+}
+
+// SQLitePlayerStoreFromFile opens the SQLite database at path, creating it if needed.
+func SQLitePlayerStoreFromFile(path string) (*SQLitePlayerStore, func(), error) {
+	db, err := sql.Open("sqlite", path+"?_pragma=busy_timeout(5000)&_pragma=foreign_keys(1)")
+	if err != nil {
+		return nil, nil, fmt.Errorf("problem opening %s %v", path, err)
+	}
+	// a single connection serialises writers and keeps in memory databases alive.
+	db.SetMaxOpenConns(1)
+
+	closeFunc := func() {
+		check(db.Close())
+	}
+
+	store, err := NewSQLitePlayerStore(db)
+	if err != nil {
+		_ = db.Close()
+		return nil, nil, fmt.Errorf("problem creating sqlite player store, %v", err)
+	}
+	return store, closeFunc, nil
+}
+
+func migrateSQLite(db *sql.DB) error {
+	var version int
+	if err := db.QueryRow(`PRAGMA user_version`).Scan(&version); err != nil {
+		return err
+	}
+
+	for i := version; i < len(sqliteMigrations); i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(sqliteMigrations[i]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %d, %v", i+1, err)
+		}
+		if _, err := tx.Exec(fmt.Sprintf(`PRAGMA user_version = %d`, i+1)); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetLeague ..
+func (s *SQLitePlayerStore) GetLeague() (League, error) {
+	rows, err := s.db.Query(`SELECT name, wins, chips, display_name, nickname, avatar
+		FROM players ORDER BY wins DESC, name`)
+	if err != nil {
+		return nil, fmt.Errorf("problem querying league, %v", err)
+	}
+	defer rows.Close()
+
+	var league League
+	for rows.Next() {
+		var p Player
+		if err := rows.Scan(&p.Name, &p.Wins, &p.Chips, &p.DisplayName, &p.Nickname, &p.Avatar); err != nil {
+			return nil, fmt.Errorf("problem reading league, %v", err)
+		}
+		league = append(league, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("problem reading league, %v", err)
+	}
+	return league, nil
+}
+
+// RegisterPlayer ..
+func (s *SQLitePlayerStore) RegisterPlayer(name string, profile Profile) error {
+	if err := ValidatePlayerName(name); err != nil {
+		return err
+	}
+	result, err := s.db.Exec(`INSERT INTO players (name, display_name, nickname, avatar) VALUES (?, ?, ?, ?)
+		ON CONFLICT (name) DO NOTHING`, name, profile.DisplayName, profile.Nickname, profile.Avatar)
+	return playerChanged(result, err, name, ErrPlayerExists)
+}
+
+// UpdatePlayer ..
+func (s *SQLitePlayerStore) UpdatePlayer(name string, profile Profile) error {
+	result, err := s.db.Exec(`UPDATE players SET display_name = ?, nickname = ?, avatar = ? WHERE name = ?`,
+		profile.DisplayName, profile.Nickname, profile.Avatar, name)
+	return playerChanged(result, err, name, ErrPlayerNotFound)
+}
+
+// DeletePlayer ..
+func (s *SQLitePlayerStore) DeletePlayer(name string) error {
+	result, err := s.db.Exec(`DELETE FROM players WHERE name = ?`, name)
+	return playerChanged(result, err, name, ErrPlayerNotFound)
+}
+
+// GetPlayer ..
+func (s *SQLitePlayerStore) GetPlayer(name string) (Player, error) {
+	p := Player{Name: name}
+	err := s.db.QueryRow(`SELECT wins, chips, display_name, nickname, avatar FROM players WHERE name = ?`, name).
+		Scan(&p.Wins, &p.Chips, &p.DisplayName, &p.Nickname, &p.Avatar)
+	if err == sql.ErrNoRows {
+		return Player{}, &PlayerError{name, ErrPlayerNotFound}
+	}
+	if err != nil {
+		return Player{}, fmt.Errorf("problem querying player %q, %v", name, err)
+	}
+	return p, nil
+}
+
+// playerChanged returns the error of a statement about name, or a
+// PlayerError for why it changed no rows.
+func playerChanged(result sql.Result, err error, name string, why error) error {
+	if err != nil {
+		return fmt.Errorf("problem changing player %q, %v", name, err)
+	}
+	changed, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("problem changing player %q, %v", name, err)
+	}
+	if changed == 0 {
+		return &PlayerError{name, why}
+	}
+	return nil
+}
+
+// GetPlayerScore ..
+func (s *SQLitePlayerStore) GetPlayerScore(name string) (int, error) {
+	var wins int
+	err := s.db.QueryRow(`SELECT wins FROM players WHERE name = ?`, name).Scan(&wins)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("problem querying score of %q, %v", name, err)
+	}
+	return wins, nil
+}
+
+// RecordWin ..
+func (s *SQLitePlayerStore) RecordWin(name string) error {
+	result, err := s.db.Exec(`UPDATE players SET wins = wins + 1 WHERE name = ?`, name)
+	return playerChanged(result, err, name, ErrPlayerNotFound)
+}
+
+// RecordGame ..
+func (s *SQLitePlayerStore) RecordGame(game GameRecord) error {
+	finishingOrder, err := json.Marshal(game.FinishingOrder)
+	if err != nil {
+		return fmt.Errorf("problem encoding finishing order, %v", err)
+	}
+	eliminations, err := json.Marshal(game.Eliminations)
+	if err != nil {
+		return fmt.Errorf("problem encoding eliminations, %v", err)
+	}
+	rebuys, err := json.Marshal(game.Rebuys)
+	if err != nil {
+		return fmt.Errorf("problem encoding rebuys, %v", err)
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("problem recording game, %v", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	_, err = tx.Exec(`INSERT INTO games (id, started_at, ended_at, blind_structure, winner, finishing_order, eliminations, rebuys)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		game.ID, formatSQLiteTime(game.StartedAt), formatSQLiteTime(game.EndedAt),
+		game.BlindStructure, game.Winner, string(finishingOrder), string(eliminations), string(rebuys))
+	if err != nil {
+		return fmt.Errorf("problem recording game, %v", err)
+	}
+
+	for seat, name := range game.Participants {
+		if _, err := tx.Exec(`INSERT INTO game_players (game_id, seat, name) VALUES (?, ?, ?)`, game.ID, seat, name); err != nil {
+			return fmt.Errorf("problem recording game, %v", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("problem recording game, %v", err)
+	}
+	return nil
+}
+
+// GetGames ..
+func (s *SQLitePlayerStore) GetGames() ([]GameRecord, error) {
+	return s.queryGames(`SELECT id, started_at, ended_at, blind_structure, winner, finishing_order, eliminations, rebuys
+		FROM games ORDER BY ended_at, rowid`)
+}
+
+// GetPlayerGames ..
+func (s *SQLitePlayerStore) GetPlayerGames(name string) ([]GameRecord, error) {
+	return s.queryGames(`SELECT id, started_at, ended_at, blind_structure, winner, finishing_order, eliminations, rebuys
+		FROM games
+		WHERE winner = ?1 OR id IN (SELECT game_id FROM game_players WHERE name = ?1)
+		ORDER BY ended_at, rowid`, name)
+}
+
+func (s *SQLitePlayerStore) queryGames(query string, args ...interface{}) ([]GameRecord, error) {
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("problem querying games, %v", err)
+	}
+	defer rows.Close()
+
+	games := []GameRecord{}
+	for rows.Next() {
+		game, err := scanGame(rows)
+		if err != nil {
+			return nil, fmt.Errorf("problem reading games, %v", err)
+		}
+		games = append(games, game)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("problem reading games, %v", err)
+	}
+	// the single connection has to be free to query the participants.
+	if err := rows.Close(); err != nil {
+		return nil, fmt.Errorf("problem reading games, %v", err)
+	}
+
+	for i := range games {
+		if games[i].Participants, err = s.participants(games[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return games, nil
+}
+
+func scanGame(rows *sql.Rows) (GameRecord, error) {
+	var (
+		game               GameRecord
+		startedAt, endedAt string
+		finishingOrder     string
+		eliminations       string
+		rebuys             string
+		err                error
+	)
+	if err := rows.Scan(&game.ID, &startedAt, &endedAt, &game.BlindStructure, &game.Winner,
+		&finishingOrder, &eliminations, &rebuys); err != nil {
+		return GameRecord{}, err
+	}
+	if game.StartedAt, err = parseSQLiteTime(startedAt); err != nil {
+		return GameRecord{}, err
+	}
+	if game.EndedAt, err = parseSQLiteTime(endedAt); err != nil {
+		return GameRecord{}, err
+	}
+	if err := json.Unmarshal([]byte(finishingOrder), &game.FinishingOrder); err != nil {
+		return GameRecord{}, err
+	}
+	if err := json.Unmarshal([]byte(eliminations), &game.Eliminations); err != nil {
+		return GameRecord{}, err
+	}
+	if err := json.Unmarshal([]byte(rebuys), &game.Rebuys); err != nil {
+		return GameRecord{}, err
+	}
+	return game, nil
+}
+
+func (s *SQLitePlayerStore) participants(gameID string) ([]string, error) {
+	rows, err := s.db.Query(`SELECT name FROM game_players WHERE game_id = ? ORDER BY seat`, gameID)
+	if err != nil {
+		return nil, fmt.Errorf("problem querying players of game %s, %v", gameID, err)
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("problem reading players of game %s, %v", gameID, err)
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("problem reading players of game %s, %v", gameID, err)
+	}
+	return names, nil
+}
+
+// RecordChips ..
+func (s *SQLitePlayerStore) RecordChips(transaction ChipTransaction) error {
+	if err := transaction.Validate(); err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("problem recording chips, %v", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	result, err := tx.Exec(`UPDATE players SET chips = chips + ? WHERE name = ?`, transaction.Net(), transaction.Player)
+	if err := playerChanged(result, err, transaction.Player, ErrPlayerNotFound); err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT INTO chip_transactions (player, game_id, type, amount, at) VALUES (?, ?, ?, ?, ?)`,
+		transaction.Player, transaction.Game, transaction.Type, transaction.Amount, formatSQLiteTime(transaction.At))
+	if err != nil {
+		return fmt.Errorf("problem recording chips, %v", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("problem recording chips, %v", err)
+	}
+	return nil
+}
+
+// GetChipTransactions ..
+func (s *SQLitePlayerStore) GetChipTransactions(name string) ([]ChipTransaction, error) {
+	rows, err := s.db.Query(`SELECT player, game_id, type, amount, at
+		FROM chip_transactions WHERE player = ? ORDER BY rowid`, name)
+	if err != nil {
+		return nil, fmt.Errorf("problem querying chips of %q, %v", name, err)
+	}
+	defer rows.Close()
+
+	transactions := []ChipTransaction{}
+	for rows.Next() {
+		var (
+			transaction ChipTransaction
+			at          string
+		)
+		if err := rows.Scan(&transaction.Player, &transaction.Game, &transaction.Type, &transaction.Amount, &at); err != nil {
+			return nil, fmt.Errorf("problem reading chips of %q, %v", name, err)
+		}
+		if transaction.At, err = parseSQLiteTime(at); err != nil {
+			return nil, fmt.Errorf("problem reading chips of %q, %v", name, err)
+		}
+		transactions = append(transactions, transaction)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("problem reading chips of %q, %v", name, err)
+	}
+	return transactions, nil
+}
+
+func formatSQLiteTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseSQLiteTime(s string) (time.Time, error) {
+	return time.Parse(time.RFC3339Nano, s)
+}
@@ -0,0 +1,49 @@
+package poker
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLitePlayerStore(t *testing.T) {
+	t.Run("migrates an existing database only once", func(t *testing.T) {
+		path := createTempSQLiteFile(t)
+
+		store := mustOpenSQLitePlayerStore(t, path)
+		assertNoError(t, store.RegisterPlayer("Chris", Profile{}))
+		assertNoError(t, store.RecordWin("Chris"))
+
+		reopened := mustOpenSQLitePlayerStore(t, path)
+		assertScoreEquals(t, getScore(t, reopened, "Chris"), 1)
+
+		var version int
+		assertNoError(t, reopened.db.QueryRow(`PRAGMA user_version`).Scan(&version))
+		if version != len(sqliteMigrations) {
+			t.Errorf("got schema version %d want %d", version, len(sqliteMigrations))
+		}
+	})
+}
+
+func createTempSQLiteFile(t testing.TB) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "sqlite")
+	if err != nil {
+		t.Fatalf("could not create temp dir %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	return filepath.Join(dir, "game.db")
+}
+
+func mustOpenSQLitePlayerStore(t testing.TB, path string) *SQLitePlayerStore {
+	t.Helper()
+
+	store, closeFunc, err := SQLitePlayerStoreFromFile(path)
+	assertNoError(t, err)
+	t.Cleanup(closeFunc)
+	return store
+}
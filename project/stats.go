@@ -0,0 +1,116 @@
+package poker
+
+import (
+	"math"
+	"sort"
+)
+
+// PlayerStats are a player's results, worked out from the games they played
+// rather than the league's win counter.
+type PlayerStats struct {
+	Player        string  `json:"player"`
+	GamesPlayed   int     `json:"games_played"`
+	Wins          int     `json:"wins"`
+	WinPercentage float64 `json:"win_percentage"`
+	CurrentStreak int     `json:"current_win_streak"`
+	LongestStreak int     `json:"longest_win_streak"`
+	// AverageFinish is the mean place they finished in, over the games
+	// that recorded it, or 0 when none did.
+	AverageFinish float64 `json:"average_finish"`
+}
+
+// NewPlayerStats works out the stats of name from games, in any order.
+// Games name didn't play are ignored.
+func NewPlayerStats(name string, games []GameRecord) PlayerStats {
+	stats := PlayerStats{Player: name}
+	places, placed := 0, 0
+
+	for _, game := range chronological(games) {
+		if !game.Played(name) {
+			continue
+		}
+		stats.GamesPlayed++
+
+		if game.Winner == name {
+			stats.Wins++
+			stats.CurrentStreak++
+			stats.LongestStreak = max(stats.LongestStreak, stats.CurrentStreak)
+		} else {
+			stats.CurrentStreak = 0
+		}
+
+		if position := game.Position(name); position > 0 {
+			places += position
+			placed++
+		}
+	}
+
+	if stats.GamesPlayed > 0 {
+		stats.WinPercentage = round(100*float64(stats.Wins)/float64(stats.GamesPlayed), 1)
+	}
+	if placed > 0 {
+		stats.AverageFinish = round(float64(places)/float64(placed), 2)
+	}
+	return stats
+}
+
+// HeadToHead is how two players did against each other in the games they both played.
+type HeadToHead struct {
+	A     string `json:"a"`
+	B     string `json:"b"`
+	Games int    `json:"games"`
+	AWins int    `json:"a_wins"`
+	BWins int    `json:"b_wins"`
+	// AFinishedAhead is the games A finished above B, whoever won them.
+	AFinishedAhead int `json:"a_finished_ahead"`
+	BFinishedAhead int `json:"b_finished_ahead"`
+}
+
+// NewHeadToHead compares a and b over the games in games they both played.
+func NewHeadToHead(a, b string, games []GameRecord) HeadToHead {
+	h2h := HeadToHead{A: a, B: b}
+
+	for _, game := range games {
+		if !game.Played(a) || !game.Played(b) {
+			continue
+		}
+		h2h.Games++
+
+		switch game.Winner {
+		case a:
+			h2h.AWins++
+		case b:
+			h2h.BWins++
+		}
+
+		// the winner finished ahead of everyone, even when nobody else's place was recorded.
+		positionA, positionB := game.Position(a), game.Position(b)
+		switch {
+		case positionA == 1:
+			h2h.AFinishedAhead++
+		case positionB == 1:
+			h2h.BFinishedAhead++
+		case positionA == 0 || positionB == 0:
+		case positionA < positionB:
+			h2h.AFinishedAhead++
+		case positionB < positionA:
+			h2h.BFinishedAhead++
+		}
+	}
+	return h2h
+}
+
+// chronological returns a copy of games in the order they ended.
+func chronological(games []GameRecord) []GameRecord {
+	sorted := append([]GameRecord{}, games...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].EndedAt.Before(sorted[j].EndedAt)
+	})
+	return sorted
+}
+
+// round rounds x to places decimal places.
+func round(x float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(x*scale) / scale
+}
@@ -0,0 +1,89 @@
+package poker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewPlayerStats(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 9, d, 22, 0, 0, 0, time.UTC) }
+	game := func(d int, order ...string) GameRecord {
+		return GameRecord{EndedAt: day(d), Participants: order, FinishingOrder: order, Winner: order[0]}
+	}
+
+	t.Run("works out streaks in the order the games ended", func(t *testing.T) {
+		games := []GameRecord{
+			game(5, "Chris", "Cleo"),
+			game(1, "Chris", "Cleo", "Tiest"),
+			game(3, "Cleo", "Chris"),
+			game(2, "Chris", "Tiest"),
+			game(4, "Chris", "Tiest", "Cleo"),
+			game(6, "Tiest", "Cleo"),
+		}
+
+		got := NewPlayerStats("Chris", games)
+		want := PlayerStats{
+			Player:        "Chris",
+			GamesPlayed:   5,
+			Wins:          4,
+			WinPercentage: 80,
+			CurrentStreak: 2,
+			LongestStreak: 2,
+			AverageFinish: 1.2,
+		}
+		if got != want {
+			t.Errorf("got %+v want %+v", got, want)
+		}
+	})
+
+	t.Run("rounds the win percentage and average finish", func(t *testing.T) {
+		games := []GameRecord{
+			game(1, "Cleo", "Chris", "Tiest"),
+			game(2, "Chris", "Cleo", "Tiest"),
+			game(3, "Tiest", "Cleo", "Chris"),
+		}
+
+		got := NewPlayerStats("Chris", games)
+		if got.WinPercentage != 33.3 || got.AverageFinish != 2 {
+			t.Errorf("got %+v", got)
+		}
+		if got.CurrentStreak != 0 || got.LongestStreak != 1 {
+			t.Errorf("got streaks %d and %d want 0 and 1", got.CurrentStreak, got.LongestStreak)
+		}
+	})
+
+	t.Run("only averages the finishes that were recorded", func(t *testing.T) {
+		games := []GameRecord{
+			{EndedAt: day(1), Participants: []string{"Chris", "Cleo"}, Winner: "Cleo"},
+			{EndedAt: day(2), Participants: []string{"Chris", "Cleo"}, Winner: "Chris"},
+			game(3, "Cleo", "Tiest", "Chris"),
+		}
+
+		got := NewPlayerStats("Chris", games)
+		if got.GamesPlayed != 3 || got.Wins != 1 || got.AverageFinish != 2 {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("players without games have empty stats", func(t *testing.T) {
+		got := NewPlayerStats("Chris", nil)
+		if got != (PlayerStats{Player: "Chris"}) {
+			t.Errorf("got %+v", got)
+		}
+	})
+}
+
+func TestNewHeadToHead(t *testing.T) {
+	games := []GameRecord{
+		{Participants: []string{"Chris", "Cleo", "Tiest"}, FinishingOrder: []string{"Chris", "Tiest", "Cleo"}, Winner: "Chris"},
+		{Participants: []string{"Chris", "Cleo", "Tiest"}, FinishingOrder: []string{"Tiest", "Cleo", "Chris"}, Winner: "Tiest"},
+		{Participants: []string{"Chris", "Cleo"}, Winner: "Cleo"},
+		{Participants: []string{"Chris", "Tiest"}, FinishingOrder: []string{"Chris", "Tiest"}, Winner: "Chris"},
+	}
+
+	got := NewHeadToHead("Chris", "Cleo", games)
+	want := HeadToHead{A: "Chris", B: "Cleo", Games: 3, AWins: 1, BWins: 1, AFinishedAhead: 1, BFinishedAhead: 2}
+	if got != want {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package poker_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	poker "learn-go-with-tests/project"
+	"learn-go-with-tests/project/storetest"
+)
+
+func TestInMemoryPlayerStoreContract(t *testing.T) {
+	storetest.RunPlayerStoreContract(t, func(t *testing.T) (poker.PlayerStore, func() poker.PlayerStore) {
+		return poker.NewInMemoryPlayerStore(), nil
+	})
+}
+
+func TestFileSystemPlayerStoreContract(t *testing.T) {
+	storetest.RunPlayerStoreContract(t, func(t *testing.T) (poker.PlayerStore, func() poker.PlayerStore) {
+		path := filepath.Join(tempDir(t), "game.db.json")
+		reopen := func() poker.PlayerStore {
+			store, closeFunc, err := poker.FileSystemPlayerStoreFromFile(path)
+			assertOpened(t, path, err)
+			t.Cleanup(closeFunc)
+			return store
+		}
+		return reopen(), reopen
+	})
+}
+
+func TestSQLitePlayerStoreContract(t *testing.T) {
+	storetest.RunPlayerStoreContract(t, func(t *testing.T) (poker.PlayerStore, func() poker.PlayerStore) {
+		path := filepath.Join(tempDir(t), "game.db")
+		reopen := func() poker.PlayerStore {
+			store, closeFunc, err := poker.SQLitePlayerStoreFromFile(path)
+			assertOpened(t, path, err)
+			t.Cleanup(closeFunc)
+			return store
+		}
+		return reopen(), reopen
+	})
+}
+
+func TestEventLogPlayerStoreContract(t *testing.T) {
+	storetest.RunPlayerStoreContract(t, func(t *testing.T) (poker.PlayerStore, func() poker.PlayerStore) {
+		path := filepath.Join(tempDir(t), "game.events")
+		reopen := func() poker.PlayerStore {
+			// compact often so the contract covers replaying snapshots as well as logs.
+			store, closeFunc, err := poker.EventLogPlayerStoreFromFile(path, 3)
+			assertOpened(t, path, err)
+			t.Cleanup(closeFunc)
+			return store
+		}
+		return reopen(), reopen
+	})
+}
+
+func assertOpened(t testing.TB, path string, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("could not open store at %s, %v", path, err)
+	}
+}
+
+func tempDir(t testing.TB) string {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatalf("could not create temp dir %v", err)
+	}
+	t.Cleanup(func() {
+		_ = os.RemoveAll(dir)
+	})
+	return dir
+}
@@ -0,0 +1,412 @@
+// Package storetest checks that a poker.PlayerStore behaves the way the
+// rest of the poker package expects every store to behave.
+package storetest
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	poker "learn-go-with-tests/project"
+)
+
+// Factory returns a new, empty store and a func that opens the same storage
+// again as a new store. Stores that don't persist return a nil reopen func.
+type Factory func(t *testing.T) (store poker.PlayerStore, reopen func() poker.PlayerStore)
+
+// RunPlayerStoreContract runs the PlayerStore contract against stores from newStore.
+func RunPlayerStoreContract(t *testing.T, newStore Factory) {
+	t.Run("a new store has an empty league", func(t *testing.T) {
+		store, _ := newStore(t)
+
+		if league := getLeague(t, store); len(league) != 0 {
+			t.Errorf("got league %v want it empty", league)
+		}
+		if games := getGames(t, store); len(games) != 0 {
+			t.Errorf("got games %v want none", games)
+		}
+	})
+
+	t.Run("league is ordered by wins, then by name", func(t *testing.T) {
+		store, _ := newStore(t)
+		recordWins(t, store, "Cleo", 10)
+		recordWins(t, store, "Chris", 33)
+		recordWins(t, store, "Pepper", 10)
+		recordWins(t, store, "Apollo", 10)
+
+		assertLeague(t, getLeague(t, store), poker.League{
+			{Name: "Chris", Wins: 33},
+			{Name: "Apollo", Wins: 10},
+			{Name: "Cleo", Wins: 10},
+			{Name: "Pepper", Wins: 10},
+		})
+	})
+
+	t.Run("unknown players have no wins and are not in the league", func(t *testing.T) {
+		store, _ := newStore(t)
+		recordWins(t, store, "Chris", 1)
+
+		assertScore(t, getScore(t, store, "Apollo"), 0)
+		assertLeague(t, getLeague(t, store), poker.League{{Name: "Chris", Wins: 1}})
+		assertGames(t, getPlayerGames(t, store, "Apollo"), []poker.GameRecord{})
+	})
+
+	t.Run("records wins for new and existing players", func(t *testing.T) {
+		store, _ := newStore(t)
+		recordWins(t, store, "Chris", 33)
+		assertNoError(t, store.RegisterPlayer("Pepper", poker.Profile{}))
+
+		assertNoError(t, store.RecordWin("Chris"))
+		assertNoError(t, store.RecordWin("Pepper"))
+
+		assertScore(t, getScore(t, store, "Chris"), 34)
+		assertScore(t, getScore(t, store, "Pepper"), 1)
+	})
+
+	t.Run("doesn't record wins for players who aren't registered", func(t *testing.T) {
+		store, _ := newStore(t)
+		recordWins(t, store, "Chris", 1)
+
+		assertPlayerError(t, store.RecordWin("Apollo"), "Apollo", poker.ErrPlayerNotFound)
+
+		assertScore(t, getScore(t, store, "Apollo"), 0)
+		assertLeague(t, getLeague(t, store), poker.League{{Name: "Chris", Wins: 1}})
+	})
+
+	t.Run("registers players with their profile", func(t *testing.T) {
+		store, _ := newStore(t)
+		profile := poker.Profile{DisplayName: "Chris James", Nickname: "CJ", Avatar: "https://example.com/cj.png"}
+
+		assertNoError(t, store.RegisterPlayer("Chris", profile))
+		assertNoError(t, store.RegisterPlayer("Cleo", poker.Profile{}))
+
+		assertPlayer(t, store, "Chris", poker.Player{Name: "Chris", Profile: profile})
+		assertLeague(t, getLeague(t, store), poker.League{
+			{Name: "Chris", Profile: profile},
+			{Name: "Cleo"},
+		})
+	})
+
+	t.Run("doesn't register a player twice", func(t *testing.T) {
+		store, _ := newStore(t)
+		recordWins(t, store, "Chris", 2)
+
+		assertPlayerError(t, store.RegisterPlayer("Chris", poker.Profile{Nickname: "CJ"}), "Chris", poker.ErrPlayerExists)
+		assertPlayer(t, store, "Chris", poker.Player{Name: "Chris", Wins: 2})
+	})
+
+	t.Run("doesn't register invalid names", func(t *testing.T) {
+		store, _ := newStore(t)
+
+		for _, name := range []string{"", "Manu wins", "a/b", strings.Repeat("x", poker.MaxPlayerNameLength+1)} {
+			assertPlayerError(t, store.RegisterPlayer(name, poker.Profile{}), name, poker.ErrInvalidPlayerName)
+		}
+		if league := getLeague(t, store); len(league) != 0 {
+			t.Errorf("got league %v want it empty", league)
+		}
+	})
+
+	t.Run("updates the profiles of registered players", func(t *testing.T) {
+		store, _ := newStore(t)
+		recordWins(t, store, "Chris", 3)
+		profile := poker.Profile{DisplayName: "Chris James"}
+
+		assertNoError(t, store.UpdatePlayer("Chris", profile))
+		assertPlayerError(t, store.UpdatePlayer("Apollo", profile), "Apollo", poker.ErrPlayerNotFound)
+
+		assertPlayer(t, store, "Chris", poker.Player{Name: "Chris", Wins: 3, Profile: profile})
+		assertLeague(t, getLeague(t, store), poker.League{{Name: "Chris", Wins: 3, Profile: profile}})
+	})
+
+	t.Run("deletes players but keeps their games", func(t *testing.T) {
+		store, _ := newStore(t)
+		first, _ := exampleGames()
+		recordWins(t, store, "Chris", 3)
+		recordWins(t, store, "Cleo", 1)
+		assertNoError(t, store.RecordGame(first))
+
+		assertNoError(t, store.DeletePlayer("Chris"))
+		assertPlayerError(t, store.DeletePlayer("Chris"), "Chris", poker.ErrPlayerNotFound)
+
+		_, err := store.GetPlayer("Chris")
+		assertPlayerError(t, err, "Chris", poker.ErrPlayerNotFound)
+		assertScore(t, getScore(t, store, "Chris"), 0)
+		assertLeague(t, getLeague(t, store), poker.League{{Name: "Cleo", Wins: 1}})
+		assertGames(t, getPlayerGames(t, store, "Chris"), []poker.GameRecord{first})
+	})
+
+	t.Run("records wins concurrently", func(t *testing.T) {
+		store, _ := newStore(t)
+		const players, winsEach = 10, 10
+
+		for i := 0; i < players; i++ {
+			assertNoError(t, store.RegisterPlayer(fmt.Sprintf("player-%d", i), poker.Profile{}))
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(players * winsEach)
+		for i := 0; i < players*winsEach; i++ {
+			name := fmt.Sprintf("player-%d", i%players)
+			go func() {
+				defer wg.Done()
+				if err := store.RecordWin(name); err != nil {
+					t.Error(err)
+				}
+				if _, err := store.GetPlayerScore(name); err != nil {
+					t.Error(err)
+				}
+				if _, err := store.GetLeague(); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		league := getLeague(t, store)
+		if len(league) != players {
+			t.Fatalf("got %d players in the league want %d", len(league), players)
+		}
+		for _, p := range league {
+			assertScore(t, p.Wins, winsEach)
+		}
+	})
+
+	t.Run("records games and finds the games a player took part in", func(t *testing.T) {
+		store, _ := newStore(t)
+		first, second := exampleGames()
+		assertNoError(t, store.RecordGame(first))
+		assertNoError(t, store.RecordGame(second))
+
+		assertGames(t, getGames(t, store), []poker.GameRecord{first, second})
+		assertGames(t, getPlayerGames(t, store, "Chris"), []poker.GameRecord{first})
+		assertGames(t, getPlayerGames(t, store, "Cleo"), []poker.GameRecord{first, second})
+	})
+
+	t.Run("records chip transactions and ranks the league by net chips", func(t *testing.T) {
+		store, _ := newStore(t)
+		recordWins(t, store, "Chris", 2)
+		recordWins(t, store, "Pepper", 0)
+		buyIn, rebuy, payout := exampleChips()
+		assertNoError(t, store.RecordChips(buyIn))
+		assertNoError(t, store.RecordChips(rebuy))
+		assertNoError(t, store.RecordChips(payout))
+
+		assertChips(t, getChips(t, store, "Chris"), []poker.ChipTransaction{buyIn, rebuy})
+		assertChips(t, getChips(t, store, "Apollo"), []poker.ChipTransaction{})
+		assertScore(t, getScore(t, store, "Pepper"), 0)
+
+		league := getLeague(t, store)
+		assertLeague(t, league, poker.League{
+			{Name: "Chris", Wins: 2, Chips: -200},
+			{Name: "Pepper", Chips: 300},
+		})
+		league.SortByChips()
+		assertLeague(t, league, poker.League{
+			{Name: "Pepper", Chips: 300},
+			{Name: "Chris", Wins: 2, Chips: -200},
+		})
+	})
+
+	t.Run("doesn't record invalid chip transactions", func(t *testing.T) {
+		store, _ := newStore(t)
+		buyIn, _, _ := exampleChips()
+		buyIn.Amount = 0
+
+		if err := store.RecordChips(buyIn); !errors.Is(err, poker.ErrInvalidChipTransaction) {
+			t.Errorf("got error %v want %v", err, poker.ErrInvalidChipTransaction)
+		}
+		assertChips(t, getChips(t, store, "Chris"), []poker.ChipTransaction{})
+		if league := getLeague(t, store); len(league) != 0 {
+			t.Errorf("got league %v want it empty", league)
+		}
+	})
+
+	t.Run("doesn't record chips for players who aren't registered", func(t *testing.T) {
+		store, _ := newStore(t)
+		buyIn, _, _ := exampleChips()
+
+		assertPlayerError(t, store.RecordChips(buyIn), "Chris", poker.ErrPlayerNotFound)
+		assertChips(t, getChips(t, store, "Chris"), []poker.ChipTransaction{})
+		if league := getLeague(t, store); len(league) != 0 {
+			t.Errorf("got league %v want it empty", league)
+		}
+	})
+
+	t.Run("keeps wins and games after reopening", func(t *testing.T) {
+		store, reopen := newStore(t)
+		if reopen == nil {
+			t.Skip("store does not persist")
+		}
+
+		first, second := exampleGames()
+		profile := poker.Profile{DisplayName: "Chris James", Nickname: "CJ"}
+		assertNoError(t, store.RegisterPlayer("Chris", profile))
+		assertNoError(t, store.RegisterPlayer("Apollo", poker.Profile{}))
+		assertNoError(t, store.DeletePlayer("Apollo"))
+		recordWins(t, store, "Chris", 2)
+		assertNoError(t, store.RecordGame(first))
+		recordWins(t, store, "Pepper", 1)
+		assertNoError(t, store.RecordGame(second))
+		buyIn, rebuy, payout := exampleChips()
+		assertNoError(t, store.RecordChips(buyIn))
+		assertNoError(t, store.RecordChips(rebuy))
+		assertNoError(t, store.RecordChips(payout))
+
+		reopened := reopen()
+
+		assertLeague(t, getLeague(t, reopened), poker.League{
+			{Name: "Chris", Wins: 2, Chips: -200, Profile: profile},
+			{Name: "Pepper", Wins: 1, Chips: 300},
+		})
+		assertGames(t, getGames(t, reopened), []poker.GameRecord{first, second})
+		assertGames(t, getPlayerGames(t, reopened, "Pepper"), []poker.GameRecord{second})
+		assertChips(t, getChips(t, reopened, "Chris"), []poker.ChipTransaction{buyIn, rebuy})
+		assertChips(t, getChips(t, reopened, "Pepper"), []poker.ChipTransaction{payout})
+	})
+}
+
+// recordWins registers name if they aren't already and gives them wins.
+func recordWins(t testing.TB, store poker.PlayerStore, name string, wins int) {
+	t.Helper()
+	if _, err := store.GetPlayer(name); errors.Is(err, poker.ErrPlayerNotFound) {
+		assertNoError(t, store.RegisterPlayer(name, poker.Profile{}))
+	}
+	for i := 0; i < wins; i++ {
+		assertNoError(t, store.RecordWin(name))
+	}
+}
+
+func exampleGames() (first, second poker.GameRecord) {
+	first = poker.GameRecord{
+		ID:             "first",
+		StartedAt:      time.Date(2026, 7, 1, 20, 0, 0, 0, time.UTC),
+		EndedAt:        time.Date(2026, 7, 1, 23, 0, 0, 0, time.UTC),
+		Participants:   []string{"Chris", "Cleo"},
+		FinishingOrder: []string{"Chris", "Cleo"},
+		BlindStructure: "turbo",
+		Winner:         "Chris",
+		Eliminations: []poker.Elimination{
+			{Player: "Cleo", Position: 2, At: time.Date(2026, 7, 1, 22, 30, 0, 0, time.UTC)},
+		},
+		Rebuys: map[string]int{"Cleo": 2},
+	}
+	second = poker.GameRecord{
+		ID:             "second",
+		StartedAt:      time.Date(2026, 7, 8, 20, 0, 0, 0, time.UTC),
+		EndedAt:        time.Date(2026, 7, 8, 22, 0, 0, 0, time.UTC),
+		Participants:   []string{"Cleo", "Pepper"},
+		FinishingOrder: []string{"Pepper", "Cleo"},
+		BlindStructure: poker.DefaultBlindStructure,
+		Winner:         "Pepper",
+	}
+	return first, second
+}
+
+func exampleChips() (buyIn, rebuy, payout poker.ChipTransaction) {
+	at := time.Date(2026, 7, 1, 20, 0, 0, 0, time.UTC)
+	buyIn = poker.ChipTransaction{Player: "Chris", Game: "first", Type: poker.ChipBuyIn, Amount: 100, At: at}
+	rebuy = poker.ChipTransaction{Player: "Chris", Game: "first", Type: poker.ChipRebuy, Amount: 100, At: at.Add(time.Hour)}
+	payout = poker.ChipTransaction{Player: "Pepper", Game: "first", Type: poker.ChipPayout, Amount: 300, At: at.Add(3 * time.Hour)}
+	return buyIn, rebuy, payout
+}
+
+func assertLeague(t testing.TB, got, want poker.League) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got league %v want %v", got, want)
+	}
+}
+
+func assertGames(t testing.TB, got, want []poker.GameRecord) {
+	t.Helper()
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got games %+v want %+v", got, want)
+	}
+}
+
+func assertChips(t testing.TB, got, want []poker.ChipTransaction) {
+	t.Helper()
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got chip transactions %+v want %+v", got, want)
+	}
+}
+
+func assertPlayer(t testing.TB, store poker.PlayerStore, name string, want poker.Player) {
+	t.Helper()
+	got, err := store.GetPlayer(name)
+	assertNoError(t, err)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got player %+v want %+v", got, want)
+	}
+}
+
+// assertPlayerError checks err is a *poker.PlayerError about name for the reason want.
+func assertPlayerError(t testing.TB, err error, name string, want error) {
+	t.Helper()
+	var playerErr *poker.PlayerError
+	if !errors.As(err, &playerErr) {
+		t.Fatalf("got error %v want a *poker.PlayerError", err)
+	}
+	if playerErr.Name != name || !errors.Is(err, want) {
+		t.Errorf("got error %v want %q %v", err, name, want)
+	}
+}
+
+func getLeague(t testing.TB, store poker.PlayerStore) poker.League {
+	t.Helper()
+	league, err := store.GetLeague()
+	assertNoError(t, err)
+	return league
+}
+
+func getScore(t testing.TB, store poker.PlayerStore, name string) int {
+	t.Helper()
+	wins, err := store.GetPlayerScore(name)
+	assertNoError(t, err)
+	return wins
+}
+
+func getGames(t testing.TB, store poker.PlayerStore) []poker.GameRecord {
+	t.Helper()
+	games, err := store.GetGames()
+	assertNoError(t, err)
+	return games
+}
+
+func getPlayerGames(t testing.TB, store poker.PlayerStore, name string) []poker.GameRecord {
+	t.Helper()
+	games, err := store.GetPlayerGames(name)
+	assertNoError(t, err)
+	return games
+}
+
+func getChips(t testing.TB, store poker.PlayerStore, name string) []poker.ChipTransaction {
+	t.Helper()
+	transactions, err := store.GetChipTransactions(name)
+	assertNoError(t, err)
+	return transactions
+}
+
+func assertNoError(t testing.TB, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("didn't expect an error but got one, %v", err)
+	}
+}
+
+func assertScore(t testing.TB, got, want int) {
+	t.Helper()
+	if got != want {
+		t.Errorf("got score %d want %d", got, want)
+	}
+}
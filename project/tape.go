@@ -1,15 +1,130 @@
 package poker
 
 import (
+	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 )
 
+// tape replaces the whole of the file at path on every Write. The new contents
+// are written to a temporary file, synced and renamed over path, so a crash
+// leaves either the old or the new contents but never a partial write.
 type tape struct {
-	file *os.File
+	path string
+	// backups is how many previous versions of the file to keep, as path.bak.1
+	// (the newest) to path.bak.{backups}.
+	backups int
 }
 
 func (t *tape) Write(p []byte) (n int, err error) {
-	err = t.file.Truncate(0)
-	_, err = t.file.Seek(0, 0)
-	return t.file.Write(p)
+	dir, base := filepath.Split(t.path)
+	if dir == "" {
+		dir = "."
+	}
+
+	tmp, err := ioutil.TempFile(dir, base+".tmp-*")
+	if err != nil {
+		return 0, fmt.Errorf("problem creating temp file for %s, %v", t.path, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	// the temp file is only readable by its owner, path keeps the mode it had.
+	if err = tmp.Chmod(t.mode()); err != nil {
+		_ = tmp.Close()
+		return 0, err
+	}
+	if n, err = tmp.Write(p); err != nil {
+		_ = tmp.Close()
+		return 0, err
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return 0, err
+	}
+	if err = tmp.Close(); err != nil {
+		return 0, err
+	}
+
+	if err = t.rotateBackups(); err != nil {
+		return 0, fmt.Errorf("problem backing up %s, %v", t.path, err)
+	}
+
+	if err = os.Rename(tmp.Name(), t.path); err != nil {
+		return 0, err
+	}
+	syncDir(dir)
+
+	return n, nil
+}
+
+// mode is the permissions of the file at path, or those a new file is
+// usually created with when there isn't one yet.
+func (t *tape) mode() os.FileMode {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		return 0644
+	}
+	return info.Mode().Perm()
+}
+
+// rotateBackups shifts every backup along by one and makes the current file the newest.
+func (t *tape) rotateBackups() error {
+	if t.backups <= 0 {
+		return nil
+	}
+
+	for i := t.backups - 1; i >= 1; i-- {
+		err := os.Rename(backupPath(t.path, i), backupPath(t.path, i+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	newest := backupPath(t.path, 1)
+	if err := os.Remove(newest); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	// a hard link keeps the current contents without a moment where path is missing.
+	if err := os.Link(t.path, newest); err != nil && !os.IsNotExist(err) {
+		return copyFile(t.path, newest)
+	}
+	return nil
+}
+
+func backupPath(path string, n int) string {
+	return fmt.Sprintf("%s.bak.%d", path, n)
+}
+
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(to)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// syncDir makes a rename in dir durable, it is best effort as not every platform supports it.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	_ = d.Sync()
+	_ = d.Close()
 }
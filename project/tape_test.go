@@ -2,24 +2,91 @@ package poker
 
 import (
 	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 )
 
 func TestTape_Write(t *testing.T) {
-	file, clean := createTempFile(t, "12345")
-	defer clean()
+	t.Run("replaces the contents of the file", func(t *testing.T) {
+		file, clean := createTempFile(t, "12345")
+		defer clean()
 
-	tape := &tape{file}
+		tape := &tape{path: file.Name()}
 
-	_, _ = tape.Write([]byte("abc"))
+		_, err := tape.Write([]byte("abc"))
+		assertNoError(t, err)
 
-	_, _ = file.Seek(0, 0)
-	newFileContents, _ := ioutil.ReadAll(file)
+		assertFileContents(t, file.Name(), "abc")
+		assertNoTempFiles(t, file.Name())
+	})
 
-	got := string(newFileContents)
-	want := "abc"
+	t.Run("keeps the permissions of the file", func(t *testing.T) {
+		file, clean := createTempFile(t, "12345")
+		defer clean()
+		assertNoError(t, os.Chmod(file.Name(), 0640))
 
-	if got != want {
-		t.Errorf("got %q want %q", got, want)
+		tape := &tape{path: file.Name()}
+
+		_, err := tape.Write([]byte("abc"))
+		assertNoError(t, err)
+
+		info, err := os.Stat(file.Name())
+		assertNoError(t, err)
+		if got := info.Mode().Perm(); got != 0640 {
+			t.Errorf("got mode %v want %v", got, os.FileMode(0640))
+		}
+	})
+
+	t.Run("keeps rotating backups of the previous contents", func(t *testing.T) {
+		file, clean := createTempFile(t, "1")
+		defer clean()
+		defer removeBackups(file.Name())
+
+		tape := &tape{path: file.Name(), backups: 2}
+
+		for _, contents := range []string{"2", "3", "4"} {
+			_, err := tape.Write([]byte(contents))
+			assertNoError(t, err)
+		}
+
+		assertFileContents(t, file.Name(), "4")
+		assertFileContents(t, backupPath(file.Name(), 1), "3")
+		assertFileContents(t, backupPath(file.Name(), 2), "2")
+		assertNoFile(t, backupPath(file.Name(), 3))
+	})
+}
+
+func assertFileContents(t testing.TB, path, want string) {
+	t.Helper()
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read %s %v", path, err)
+	}
+	if got := string(contents); got != want {
+		t.Errorf("got %q in %s want %q", got, path, want)
+	}
+}
+
+func assertNoFile(t testing.TB, path string) {
+	t.Helper()
+	if _, err := ioutil.ReadFile(path); err == nil {
+		t.Errorf("expected %s not to exist", path)
+	}
+}
+
+func assertNoTempFiles(t testing.TB, path string) {
+	t.Helper()
+	leftovers, _ := filepath.Glob(path + ".tmp-*")
+	if len(leftovers) != 0 {
+		t.Errorf("expected no temp files but found %v", leftovers)
+	}
+}
+
+func removeBackups(path string) {
+	backups, _ := filepath.Glob(path + ".bak.*")
+	corrupt, _ := filepath.Glob(path + ".corrupt-*")
+	for _, f := range append(backups, corrupt...) {
+		_ = os.Remove(f)
 	}
 }
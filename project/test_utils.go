@@ -1,6 +1,7 @@
 package poker
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -35,6 +36,12 @@ func newPostWinRequest(name string) *http.Request {
 	return req
 }
 
+func newRegisterPlayerRequest(name string, profile Profile) *http.Request {
+	body, _ := json.Marshal(registration{Name: name, Profile: profile})
+	req, _ := http.NewRequest(http.MethodPost, "/players", bytes.NewReader(body))
+	return req
+}
+
 func getLeagueFromResponse(t testing.TB, body io.Reader) (league []Player) {
 	t.Helper()
 	err := json.NewDecoder(body).Decode(&league)
@@ -53,6 +60,13 @@ func assertLeague(t testing.TB, got, want []Player) {
 	}
 }
 
+func assertGames(t testing.TB, got, want []GameRecord) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v want %+v", got, want)
+	}
+}
+
 func newLeagueRequest() *http.Request {
 	req, _ := http.NewRequest(http.MethodGet, "/league", nil)
 	return req
@@ -86,11 +100,67 @@ func createTempFile(t testing.TB, initialData string) (*os.File, func()) {
 	removeFile := func() {
 		_ = tmpFile.Close()
 		_ = os.Remove(tmpFile.Name())
+		_ = os.Remove(tmpFile.Name() + ".lock")
 	}
 
 	return tmpFile, removeFile
 }
 
+func getLeague(t testing.TB, store PlayerStore) League {
+	t.Helper()
+	league, err := store.GetLeague()
+	assertNoError(t, err)
+	return league
+}
+
+func getScore(t testing.TB, store PlayerStore, name string) int {
+	t.Helper()
+	wins, err := store.GetPlayerScore(name)
+	assertNoError(t, err)
+	return wins
+}
+
+func getGames(t testing.TB, store GameHistory) []GameRecord {
+	t.Helper()
+	games, err := store.GetGames()
+	assertNoError(t, err)
+	return games
+}
+
+func getChips(t testing.TB, store ChipLedger, name string) []ChipTransaction {
+	t.Helper()
+	transactions, err := store.GetChipTransactions(name)
+	assertNoError(t, err)
+	return transactions
+}
+
+// newRegisteredPlayerStore returns an InMemoryPlayerStore with players registered.
+func newRegisteredPlayerStore(t testing.TB, players ...string) *InMemoryPlayerStore {
+	t.Helper()
+	store := NewInMemoryPlayerStore()
+	for _, name := range players {
+		assertNoError(t, store.RegisterPlayer(name, Profile{}))
+	}
+	return store
+}
+
+// assertErrorResponse checks response is an errorResponse with status and,
+// unless want is empty, the error want. It returns the error.
+func assertErrorResponse(t testing.TB, response *httptest.ResponseRecorder, status int, want string) string {
+	t.Helper()
+	assertStatus(t, response.Code, status)
+	assertContentType(t, response, jsonContentType)
+
+	var got errorResponse
+	if err := json.NewDecoder(response.Body).Decode(&got); err != nil {
+		t.Fatalf("could not parse error response from %q, %v", response.Body, err)
+	}
+	if got.Error == "" || (want != "" && got.Error != want) {
+		t.Errorf("got error %q want %q", got.Error, want)
+	}
+	return got.Error
+}
+
 func assertNoError(t testing.TB, err error) {
 	t.Helper()
 	if err != nil {
@@ -1,6 +1,7 @@
 package poker
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"sync"
@@ -13,6 +14,13 @@ type StubPlayerStore struct {
 	scores   map[string]int
 	winCalls []string
 	league   []Player
+	games    []GameRecord
+	chips    []ChipTransaction
+	// registered are the players on the roster, when nil every player
+	// is taken to be registered.
+	registered map[string]Profile
+	// err, when set, is returned by every call as if the storage had failed.
+	err error
 }
 
 func NewStubPlayerStore(
@@ -21,28 +29,148 @@ func NewStubPlayerStore(
 	winCalls []string,
 	league []Player) *StubPlayerStore {
 	return &StubPlayerStore{
-		mu,
-		scores,
-		winCalls,
-		league,
+		mu:       mu,
+		scores:   scores,
+		winCalls: winCalls,
+		league:   league,
 	}
 }
 
-func (s *StubPlayerStore) GetLeague() League {
-	return s.league
+func (s *StubPlayerStore) GetLeague() (League, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.league, nil
 }
 
-func (s *StubPlayerStore) GetPlayerScore(name string) int {
+func (s *StubPlayerStore) GetPlayerScore(name string) (int, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
+	if s.err != nil {
+		return 0, s.err
+	}
 	score := s.scores[name]
-	return score
+	return score, nil
 }
 
-func (s *StubPlayerStore) RecordWin(name string) {
+func (s *StubPlayerStore) RecordWin(name string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if _, ok := s.registered[name]; s.registered != nil && !ok {
+		return &PlayerError{name, ErrPlayerNotFound}
+	}
 	s.winCalls = append(s.winCalls, name)
+	return nil
+}
+
+func (s *StubPlayerStore) RegisterPlayer(name string, profile Profile) error {
+	if err := ValidatePlayerName(name); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if _, ok := s.registered[name]; ok {
+		return &PlayerError{name, ErrPlayerExists}
+	}
+	if s.registered == nil {
+		s.registered = map[string]Profile{}
+	}
+	s.registered[name] = profile
+	return nil
+}
+
+func (s *StubPlayerStore) UpdatePlayer(name string, profile Profile) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if _, ok := s.registered[name]; !ok {
+		return &PlayerError{name, ErrPlayerNotFound}
+	}
+	s.registered[name] = profile
+	return nil
+}
+
+func (s *StubPlayerStore) DeletePlayer(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if _, ok := s.registered[name]; !ok {
+		return &PlayerError{name, ErrPlayerNotFound}
+	}
+	delete(s.registered, name)
+	return nil
+}
+
+func (s *StubPlayerStore) GetPlayer(name string) (Player, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.err != nil {
+		return Player{}, s.err
+	}
+	profile, ok := s.registered[name]
+	if !ok {
+		return Player{}, &PlayerError{name, ErrPlayerNotFound}
+	}
+	return Player{Name: name, Wins: s.scores[name], Profile: profile}, nil
+}
+
+func (s *StubPlayerStore) RecordGame(game GameRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.games = append(s.games, game)
+	return nil
+}
+
+func (s *StubPlayerStore) GetGames() ([]GameRecord, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.games, nil
+}
+
+func (s *StubPlayerStore) GetPlayerGames(name string) ([]GameRecord, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return playerGames(s.games, name), nil
+}
+
+func (s *StubPlayerStore) RecordChips(transaction ChipTransaction) error {
+	if err := transaction.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	if _, ok := s.registered[transaction.Player]; s.registered != nil && !ok {
+		return &PlayerError{transaction.Player, ErrPlayerNotFound}
+	}
+	s.chips = append(s.chips, transaction)
+	return nil
+}
+
+func (s *StubPlayerStore) GetChipTransactions(name string) ([]ChipTransaction, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	return playerChipTransactions(s.chips, name), nil
 }
 
 func AssertPlayerWin(t testing.TB, store *StubPlayerStore, winner string) {
@@ -62,14 +190,28 @@ type ScheduledAlert struct {
 	amount int
 }
 
+// ScheduledWarning is a warning the blinds go up to amount in another in.
+type ScheduledWarning struct {
+	at     time.Duration
+	amount int
+	in     time.Duration
+}
+
 type SpyBlindAlerter struct {
-	alerts []ScheduledAlert
+	alerts   []ScheduledAlert
+	warnings []ScheduledWarning
+	ctx      context.Context
 }
 
 func (s ScheduledAlert) String() string {
 	return fmt.Sprintf("%d chips at %v", s.amount, s.at)
 }
 
-func (s *SpyBlindAlerter) ScheduleAlertAt(at time.Duration, amount int, to io.Writer) {
+func (s *SpyBlindAlerter) ScheduleAlertAt(ctx context.Context, at time.Duration, amount int, to io.Writer) {
+	s.ctx = ctx
 	s.alerts = append(s.alerts, ScheduledAlert{at, amount})
 }
+
+func (s *SpyBlindAlerter) ScheduleWarningAt(ctx context.Context, at time.Duration, amount int, in time.Duration, to io.Writer) {
+	s.warnings = append(s.warnings, ScheduledWarning{at, amount, in})
+}
@@ -1,37 +1,676 @@
 package poker
 
 import (
+	"context"
+	"fmt"
 	"io"
+	"log"
+	"sort"
+	"sync"
 	"time"
+
+	"learn-go-with-tests/clock"
 )
 
 // TeasHoldem manages a game of poker
 type TexasHoldem struct {
-	alerter BlindAlerter
-	store   PlayerStore
+	alerter    BlindAlerter
+	store      PlayerStore
+	structures BlindStructures
+	clock      clock.Clock
+	id         string
+	newDeck    func() *Deck
+	stack      int
+
+	mu              sync.Mutex
+	ctx             context.Context
+	cancel          context.CancelFunc
+	cancelAlerts    context.CancelFunc
+	alertsTo        io.Writer
+	structure       BlindStructure
+	numberOfPlayers int
+	startedAt       time.Time
+	pausedAt        time.Time
+	pausedFor       time.Duration
+	gameID          string
+	finished        bool
+	seated          []string
+	eliminations    []Elimination
+	rebuys          map[string]int
+	stacks          map[string]int
+	rebuyChips      map[string]int
+	hand            *Hand
+	betting         *Betting
+	button          int
 }
 
-// NewTexasHoldem returns a new game.
-func NewTexasHoldem(alerter BlindAlerter, store PlayerStore) *TexasHoldem {
-	return &TexasHoldem{
-		alerter: alerter,
-		store:   store,
+// DefaultStartingStack is the chips each player starts the tournament with.
+const DefaultStartingStack = 10000
+
+// TexasHoldemOption configures a TexasHoldem.
+type TexasHoldemOption func(*TexasHoldem)
+
+// WithBlindStructures lets the game be started with any of structures as
+// well as the standard one.
+func WithBlindStructures(structures ...BlindStructure) TexasHoldemOption {
+	return func(p *TexasHoldem) {
+		p.structures = NewBlindStructures(structures...)
 	}
 }
 
-// Start will schedule blind alerts dependant on the number of players.
-func (p *TexasHoldem) Start(numberOfPlayers int, alertsDestination io.Writer) {
-	blindIncrement := time.Duration(5+numberOfPlayers) * time.Minute
+// WithClock times the game on c rather than the real clock. The alerter
+// should schedule on the same clock.
+func WithClock(c clock.Clock) TexasHoldemOption {
+	return func(p *TexasHoldem) {
+		p.clock = c
+	}
+}
+
+// WithGameID records the game under id, the ID it is played under, rather
+// than a random one.
+func WithGameID(id string) TexasHoldemOption {
+	return func(p *TexasHoldem) {
+		p.id = id
+	}
+}
+
+// WithDeck deals every hand from a deck from newDeck rather than a randomly
+// shuffled one, e.g. NewShuffledDeck with a fixed seed to replay a deal.
+func WithDeck(newDeck func() *Deck) TexasHoldemOption {
+	return func(p *TexasHoldem) {
+		p.newDeck = newDeck
+	}
+}
+
+// WithStartingStack gives each player chips to start the tournament, and
+// to buy back in, with rather than DefaultStartingStack.
+func WithStartingStack(chips int) TexasHoldemOption {
+	return func(p *TexasHoldem) {
+		p.stack = chips
+	}
+}
+
+// NewTexasHoldem returns a new game, it can be started with the standard
+// blind structure unless it is given others.
+func NewTexasHoldem(alerter BlindAlerter, store PlayerStore, options ...TexasHoldemOption) *TexasHoldem {
+	p := &TexasHoldem{
+		alerter:    alerter,
+		store:      store,
+		structures: NewBlindStructures(),
+		clock:      clock.Real{},
+		newDeck:    NewRandomDeck,
+		stack:      DefaultStartingStack,
+	}
+	for _, option := range options {
+		option(p)
+	}
+	return p
+}
+
+// Start will schedule blind alerts from the named blind structure,
+// levels without a duration depend on the number of players.
+// Pending alerts are cancelled when ctx is done or the game finishes.
+func (p *TexasHoldem) Start(ctx context.Context, numberOfPlayers int, blindStructure string, alertsDestination io.Writer) error {
+	structure, err := p.structures.Get(blindStructure)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.cancel != nil {
+		p.cancel()
+	}
+	p.ctx = ctx
+	p.cancel = cancel
+	p.alertsTo = alertsDestination
+	p.structure = structure
+	p.numberOfPlayers = numberOfPlayers
+	p.startedAt = p.clock.Now()
+	p.pausedAt = time.Time{}
+	p.pausedFor = 0
+	p.gameID = p.id
+	if p.gameID == "" {
+		p.gameID = newGameID()
+	}
+	p.finished = false
+	p.seated = nil
+	p.eliminations = nil
+	p.rebuys = nil
+	p.stacks = nil
+	p.rebuyChips = nil
+	p.hand = nil
+	p.betting = nil
+	p.button = 0
+
+	p.scheduleAlerts(0, true)
+	return nil
+}
+
+// scheduleAlerts schedules the alerts of the levels that haven't started
+// once the blind clock has run for elapsed, and the warnings ahead of them
+// if the alerter gives warnings. The level the clock is at is only
+// announced when starting, otherwise it already has been.
+func (p *TexasHoldem) scheduleAlerts(elapsed time.Duration, starting bool) {
+	ctx, cancel := context.WithCancel(p.ctx)
+	p.cancelAlerts = cancel
+	warner, warns := p.alerter.(BlindWarner)
 
-	blinds := []int{100, 200, 300, 400, 500, 600, 800, 1000, 2000, 4000, 8000}
 	blindTime := 0 * time.Second
-	for _, blind := range blinds {
-		p.alerter.ScheduleAlertAt(blindTime, blind, alertsDestination)
-		blindTime = blindTime + blindIncrement
+	for i, level := range p.structure.Levels {
+		if warns && i > 0 {
+			previous := p.structure.LevelDuration(i-1, p.numberOfPlayers)
+			for _, warning := range p.structure.LevelWarnings() {
+				// a warning can't go off before the level it is given in.
+				if at := blindTime - warning; warning < previous && at > elapsed {
+					warner.ScheduleWarningAt(ctx, at-elapsed, level.SmallBlind, warning, p.alertsTo)
+				}
+			}
+		}
+		// the first level starts with the game, later alerts that are due
+		// have already gone off.
+		if blindTime > elapsed || blindTime == 0 && starting {
+			p.alerter.ScheduleAlertAt(ctx, blindTime-elapsed, level.SmallBlind, p.alertsTo)
+		}
+		blindTime = blindTime + p.structure.LevelDuration(i, p.numberOfPlayers)
+	}
+}
+
+// Pause stops the blind clock for a break, cancelling the pending alerts.
+func (p *TexasHoldem) Pause() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case p.ctx == nil || p.ctx.Err() != nil:
+		return ErrGameNotRunning
+	case !p.pausedAt.IsZero():
+		return ErrGamePaused
+	}
+
+	p.cancelAlerts()
+	p.pausedAt = p.clock.Now()
+	p.announce("Game paused, the blind clock is stopped\n")
+	return nil
+}
+
+// Resume restarts the blind clock, the remaining alerts are moved back by
+// as long as the game was paused.
+func (p *TexasHoldem) Resume() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	switch {
+	case p.ctx == nil || p.ctx.Err() != nil:
+		return ErrGameNotRunning
+	case p.pausedAt.IsZero():
+		return ErrGameNotPaused
 	}
+
+	p.pausedFor += p.clock.Now().Sub(p.pausedAt)
+	p.pausedAt = time.Time{}
+	p.scheduleAlerts(p.elapsed(), false)
+
+	level, untilNext := p.level()
+	if level+1 < len(p.structure.Levels) {
+		p.announce(fmt.Sprintf("Game resumed, blinds go up to %d in %s\n",
+			p.structure.Levels[level+1].SmallBlind, formatCountdown(untilNext)))
+	} else {
+		p.announce("Game resumed\n")
+	}
+	return nil
 }
 
-// Finish ends the game recording the winner
-func (p *TexasHoldem) Finish(winner string) {
-	p.store.RecordWin(winner)
+func (p *TexasHoldem) announce(message string) {
+	if _, err := io.WriteString(p.alertsTo, message); err != nil {
+		log.Printf("problem announcing %q, %v\n", message, err)
+	}
+}
+
+// elapsed is how long the blind clock has run, leaving out any breaks.
+func (p *TexasHoldem) elapsed() time.Duration {
+	now := p.clock.Now()
+	if !p.pausedAt.IsZero() {
+		now = p.pausedAt
+	}
+	return now.Sub(p.startedAt) - p.pausedFor
+}
+
+// level is the index of the level the blind clock is at, and how long is left of it.
+func (p *TexasHoldem) level() (level int, remaining time.Duration) {
+	elapsed := p.elapsed()
+	for level < len(p.structure.Levels)-1 {
+		d := p.structure.LevelDuration(level, p.numberOfPlayers)
+		if elapsed < d {
+			return level, d - elapsed
+		}
+		elapsed -= d
+		level++
+	}
+	return level, 0
+}
+
+// Register adds name to the league so they can play.
+func (p *TexasHoldem) Register(name string) error {
+	return p.store.RegisterPlayer(name, Profile{})
+}
+
+// Seat seats registered players at the started game, before any hand is
+// dealt. They are all recorded as playing in it and each given the starting
+// stack, players seated again keep theirs. Once players are seated only they
+// can be knocked out or buy back in. Seating more players than the game was
+// started for lengthens the levels, the blind clock is rescheduled to match.
+func (p *TexasHoldem) Seat(players ...string) error {
+	for i, player := range players {
+		if _, err := p.store.GetPlayer(player); err != nil {
+			return err
+		}
+		if contains(players[:i], player) {
+			return fmt.Errorf("%s is already seated", player)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.hand != nil || p.button > 0 {
+		return ErrHandsDealt
+	}
+	p.seated = append([]string{}, players...)
+	stacks := map[string]int{}
+	for _, player := range players {
+		stack, ok := p.stacks[player]
+		if !ok {
+			stack = p.stack
+		}
+		stacks[player] = stack
+	}
+	p.stacks = stacks
+
+	if p.numberOfPlayers < len(players) {
+		p.numberOfPlayers = len(players)
+		// a paused clock is rescheduled when it resumes.
+		if p.ctx != nil && p.ctx.Err() == nil && p.pausedAt.IsZero() {
+			p.cancelAlerts()
+			p.scheduleAlerts(p.elapsed(), false)
+		}
+	}
+	return nil
+}
+
+// checkSeated errors unless player can play in the game, p.mu must be held.
+func (p *TexasHoldem) checkSeated(player string) error {
+	if len(p.seated) > 0 && !contains(p.seated, player) {
+		return fmt.Errorf("%s isn't seated at this game", player)
+	}
+	return nil
+}
+
+// Eliminate records player going out of the tournament now.
+func (p *TexasHoldem) Eliminate(player string) error {
+	if _, err := p.store.GetPlayer(player); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.checkSeated(player); err != nil {
+		return err
+	}
+	if p.eliminated(player) >= 0 {
+		return fmt.Errorf("%s is already out", player)
+	}
+	p.eliminations = append(p.eliminations, Elimination{Player: player, At: p.clock.Now()})
+	return nil
+}
+
+// Rebuy records player buying back in, bringing them back into the tournament
+// if they were out, and the starting stack they buy in the chip ledger. A
+// seated player gets the chips, once the hand is over if they are playing in it.
+func (p *TexasHoldem) Rebuy(player string) error {
+	if _, err := p.store.GetPlayer(player); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.checkSeated(player); err != nil {
+		return err
+	}
+	if err := p.store.RecordChips(ChipTransaction{
+		Player: player,
+		Game:   p.gameID,
+		Type:   ChipRebuy,
+		Amount: p.stack,
+		At:     p.clock.Now(),
+	}); err != nil {
+		return err
+	}
+
+	if i := p.eliminated(player); i >= 0 {
+		p.eliminations = append(p.eliminations[:i], p.eliminations[i+1:]...)
+	}
+	if p.rebuys == nil {
+		p.rebuys = map[string]int{}
+	}
+	p.rebuys[player]++
+
+	switch {
+	case p.stacks == nil:
+	case p.hand != nil && contains(p.hand.Players, player):
+		// the betting has the player's stack until the hand is shown down.
+		if p.rebuyChips == nil {
+			p.rebuyChips = map[string]int{}
+		}
+		p.rebuyChips[player] += p.stack
+	default:
+		p.stacks[player] += p.stack
+	}
+	return nil
+}
+
+func (p *TexasHoldem) eliminated(player string) int {
+	for i, e := range p.eliminations {
+		if e.Player == player {
+			return i
+		}
+	}
+	return -1
+}
+
+// Deal deals a new hand to the seated players still in the tournament,
+// posting the blinds of the level the game is at, or the next street of the
+// hand being played once the betting on this one is over. The cards and the
+// betting are announced.
+func (p *TexasHoldem) Deal() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ctx == nil || p.ctx.Err() != nil {
+		return ErrGameNotRunning
+	}
+	if p.hand != nil {
+		if err := p.dealNext(); err != nil {
+			return err
+		}
+	} else if err := p.dealHand(); err != nil {
+		return err
+	}
+
+	if err := announceHand(p.alertsTo, *p.hand); err != nil {
+		log.Printf("problem announcing the hand, %v\n", err)
+	}
+	p.announceBetting()
+	return nil
+}
+
+// dealHand deals a new hand to the players with chips, the button moving
+// on a seat every hand. p.mu must be held.
+func (p *TexasHoldem) dealHand() error {
+	var seats []Seat
+	var players []string
+	for _, player := range p.seated {
+		if p.eliminated(player) < 0 && p.stacks[player] > 0 {
+			seats = append(seats, Seat{Player: player, Stack: p.stacks[player]})
+			players = append(players, player)
+		}
+	}
+	if len(seats) == 0 {
+		return ErrNobodySeated
+	}
+
+	var blinds BlindLevel
+	if len(p.structure.Levels) > 0 {
+		level, _ := p.level()
+		blinds = p.structure.Levels[level]
+	}
+	betting, err := NewBetting(seats, blinds, p.button%len(seats))
+	if err != nil {
+		return err
+	}
+	hand, err := DealHand(p.newDeck(), players)
+	if err != nil {
+		return err
+	}
+	p.hand, p.betting = hand, betting
+	return nil
+}
+
+// dealNext deals the next street once the betting on this one is over, and
+// runs the board out when nobody can bet any more. p.mu must be held.
+func (p *TexasHoldem) dealNext() error {
+	switch {
+	case p.betting.Street() == p.hand.Street():
+		return ErrStreetNotOver
+	case len(p.inHand()) == 1:
+		return ErrHandOver
+	}
+	return p.hand.DealNext()
+}
+
+// inHand are the players of the hand who haven't folded. p.mu must be held.
+func (p *TexasHoldem) inHand() []string {
+	var players []string
+	for _, seat := range p.betting.Seats() {
+		if !seat.Folded {
+			players = append(players, seat.Player)
+		}
+	}
+	return players
+}
+
+// Act takes the betting action of the player whose turn it is in the hand
+// being played, an illegal action returns an *IllegalActionError. How the
+// betting stands after it is announced.
+func (p *TexasHoldem) Act(action Action) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ctx == nil || p.ctx.Err() != nil {
+		return ErrGameNotRunning
+	}
+	if p.hand == nil {
+		return ErrNoHand
+	}
+	if !p.betting.Over() && p.betting.Street() != p.hand.Street() {
+		return &IllegalActionError{action, ErrStreetNotDealt}
+	}
+	if err := p.betting.Act(action); err != nil {
+		return err
+	}
+
+	p.announceBetting()
+	return nil
+}
+
+func (p *TexasHoldem) announceBetting() {
+	if err := announceBetting(p.alertsTo, p.betting.State()); err != nil {
+		log.Printf("problem announcing the betting, %v\n", err)
+	}
+}
+
+// Showdown ends the hand once the betting is over, showing the hands still
+// in if there is more than one, which needs the river dealt. The pots are
+// paid out and the winners announced, players who bought back in during the
+// hand get their chips and players left without chips are knocked out.
+func (p *TexasHoldem) Showdown() ([]HandResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.ctx == nil || p.ctx.Err() != nil {
+		return nil, ErrGameNotRunning
+	}
+	if p.hand == nil {
+		return nil, ErrNoHand
+	}
+	if !p.betting.Over() {
+		return nil, ErrBettingNotOver
+	}
+
+	var results []HandResult
+	if players := p.inHand(); len(players) > 1 {
+		var err error
+		if results, err = p.hand.Showdown(players...); err != nil {
+			return nil, err
+		}
+	}
+	won, err := p.betting.Settle(results)
+	if err != nil {
+		return nil, err
+	}
+
+	var winners []string
+	for _, seat := range p.betting.Seats() {
+		p.stacks[seat.Player] = seat.Stack + p.rebuyChips[seat.Player]
+		if p.stacks[seat.Player] == 0 && p.eliminated(seat.Player) < 0 {
+			p.eliminations = append(p.eliminations, Elimination{Player: seat.Player, At: p.clock.Now()})
+		}
+		if won[seat.Player] > 0 {
+			winners = append(winners, seat.Player)
+		}
+	}
+	p.hand, p.betting, p.rebuyChips = nil, nil, nil
+	p.button++
+
+	if err := announceShowdown(p.alertsTo, results, winners); err != nil {
+		log.Printf("problem announcing the showdown, %v\n", err)
+	}
+	return results, nil
+}
+
+// Finish ends the game recording the winner and stops any pending alerts.
+// A game can only be finished once, finishing it again is ErrGameNotRunning.
+func (p *TexasHoldem) Finish(winner string) error {
+	p.mu.Lock()
+	if p.finished {
+		p.mu.Unlock()
+		return ErrGameNotRunning
+	}
+	p.finished = true
+	p.mu.Unlock()
+
+	record := p.record(winner)
+	recorder, recordsGameWins := p.store.(GameWinRecorder)
+	var err error
+	if recordsGameWins {
+		err = recorder.RecordGameWin(record)
+	} else {
+		err = p.store.RecordWin(winner)
+	}
+	if err != nil {
+		p.mu.Lock()
+		p.finished = false
+		p.mu.Unlock()
+		return err
+	}
+
+	p.mu.Lock()
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+	}
+	p.mu.Unlock()
+
+	if recordsGameWins {
+		return nil
+	}
+	if err := p.store.RecordGame(record); err != nil {
+		// the win is in and the game is over, only its record is lost.
+		log.Printf("problem recording game, %v\n", err)
+	}
+	return nil
+}
+
+// record is the result of the game, the winner finishes first and everyone
+// else in the reverse of the order they went out. The first player out
+// finishes last of all the players the game started with. Everyone seated
+// took part, including the players still at the table.
+func (p *TexasHoldem) record(winner string) GameRecord {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var eliminations []Elimination
+	for _, e := range p.eliminations {
+		if e.Player != winner {
+			eliminations = append(eliminations, e)
+		}
+	}
+	places := len(eliminations) + 1
+	if p.numberOfPlayers > places {
+		places = p.numberOfPlayers
+	}
+
+	finishingOrder := []string{winner}
+	for i := len(eliminations) - 1; i >= 0; i-- {
+		eliminations[i].Position = places - i
+		finishingOrder = append(finishingOrder, eliminations[i].Player)
+	}
+
+	// players who bought back in and were never out are still at the table.
+	var stillIn []string
+	for player := range p.rebuys {
+		if !contains(finishingOrder, player) {
+			stillIn = append(stillIn, player)
+		}
+	}
+	sort.Strings(stillIn)
+	participants := append(append([]string{}, finishingOrder...), stillIn...)
+	for _, player := range p.seated {
+		if !contains(participants, player) {
+			participants = append(participants, player)
+		}
+	}
+
+	return GameRecord{
+		ID:             p.gameID,
+		StartedAt:      p.startedAt,
+		EndedAt:        p.clock.Now(),
+		Participants:   participants,
+		FinishingOrder: finishingOrder,
+		BlindStructure: p.structure.Name,
+		Winner:         winner,
+		Eliminations:   eliminations,
+		Rebuys:         p.rebuys,
+	}
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Status reports the blind level the game has reached and how long until the next.
+func (p *TexasHoldem) Status() GameStatus {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	status := GameStatus{
+		Players:        p.numberOfPlayers,
+		BlindStructure: p.structure.Name,
+		StartedAt:      p.startedAt,
+		Paused:         !p.pausedAt.IsZero(),
+	}
+	if len(p.structure.Levels) == 0 {
+		return status
+	}
+
+	level, untilNext := p.level()
+	current := p.structure.Levels[level]
+	status.Level = level + 1
+	status.SmallBlind = current.SmallBlind
+	status.BigBlind = current.BigBlind
+	status.Ante = current.Ante
+	if level+1 < len(p.structure.Levels) {
+		status.NextSmallBlind = p.structure.Levels[level+1].SmallBlind
+		status.NextLevelSeconds = int((untilNext + time.Second - 1) / time.Second)
+	}
+	return status
 }
@@ -2,11 +2,18 @@ package poker
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"reflect"
 	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"learn-go-with-tests/clock"
 )
 
 func TestGame_Start(t *testing.T) {
@@ -14,7 +21,7 @@ func TestGame_Start(t *testing.T) {
 
 		blindAlerter := &SpyBlindAlerter{}
 		game := NewTexasHoldem(blindAlerter, dummyPlayerStore)
-		game.Start(5, ioutil.Discard)
+		game.Start(context.Background(), 5, "", ioutil.Discard)
 
 		cases := []ScheduledAlert{
 			{0 * time.Second, 100},
@@ -36,7 +43,7 @@ func TestGame_Start(t *testing.T) {
 		blindAlerter := &SpyBlindAlerter{}
 		game := NewTexasHoldem(blindAlerter, dummyPlayerStore)
 
-		game.Start(7, ioutil.Discard)
+		game.Start(context.Background(), 7, "", ioutil.Discard)
 
 		cases := []ScheduledAlert{
 			{0 * time.Second, 100},
@@ -48,6 +55,44 @@ func TestGame_Start(t *testing.T) {
 		checkSchedulingCase(t, cases, blindAlerter)
 	})
 
+	t.Run("schedules alerts from a chosen blind structure", func(t *testing.T) {
+		turbo := BlindStructure{
+			Name: "turbo",
+			Levels: []BlindLevel{
+				{SmallBlind: 25, BigBlind: 50, Duration: 3 * time.Minute},
+				{SmallBlind: 50, BigBlind: 100, Duration: 3 * time.Minute},
+				{SmallBlind: 100, BigBlind: 200, Ante: 25, Duration: 3 * time.Minute},
+			},
+		}
+		blindAlerter := &SpyBlindAlerter{}
+		game := NewTexasHoldem(blindAlerter, dummyPlayerStore, WithBlindStructures(turbo))
+
+		err := game.Start(context.Background(), 7, "turbo", ioutil.Discard)
+		assertNoError(t, err)
+
+		cases := []ScheduledAlert{
+			{0 * time.Second, 25},
+			{3 * time.Minute, 50},
+			{6 * time.Minute, 100},
+		}
+
+		checkSchedulingCase(t, cases, blindAlerter)
+	})
+
+	t.Run("it returns an error for an unknown blind structure and schedules nothing", func(t *testing.T) {
+		blindAlerter := &SpyBlindAlerter{}
+		game := NewTexasHoldem(blindAlerter, dummyPlayerStore)
+
+		err := game.Start(context.Background(), 5, "hyper", ioutil.Discard)
+
+		if err == nil {
+			t.Fatal("expected an error but didn't get one")
+		}
+		if len(blindAlerter.alerts) != 0 {
+			t.Errorf("expected no alerts but got %v", blindAlerter.alerts)
+		}
+	})
+
 	t.Run("it prints an error when a non numeric value is entered and does not start the game", func(t *testing.T) {
 		stdout := &bytes.Buffer{}
 		in := strings.NewReader("Pies\n")
@@ -72,6 +117,680 @@ func TestGame_Finish(t *testing.T) {
 	AssertPlayerWin(t, store, winner)
 }
 
+func TestGame_FinishRecordsGame(t *testing.T) {
+	store := newRegisteredPlayerStore(t, "Manu")
+	game := NewTexasHoldem(dummyBlindAlerter, store)
+
+	err := game.Start(context.Background(), 5, "", ioutil.Discard)
+	assertNoError(t, err)
+	assertNoError(t, game.Finish("Manu"))
+
+	games := getGames(t, store)
+	if len(games) != 1 {
+		t.Fatalf("got %d games recorded want 1", len(games))
+	}
+	got := games[0]
+	if got.ID == "" || got.Winner != "Manu" || got.BlindStructure != DefaultBlindStructure {
+		t.Errorf("got %+v, want a game won by Manu on the %s structure", got, DefaultBlindStructure)
+	}
+	if got.EndedAt.Before(got.StartedAt) {
+		t.Errorf("game ended at %v before it started at %v", got.EndedAt, got.StartedAt)
+	}
+}
+
+func TestGame_FinishOnce(t *testing.T) {
+	store := newRegisteredPlayerStore(t, "Manu", "Cleo")
+	game := NewTexasHoldem(dummyBlindAlerter, store)
+	assertNoError(t, game.Start(context.Background(), 5, "", ioutil.Discard))
+
+	assertNoError(t, game.Finish("Manu"))
+	if err := game.Finish("Cleo"); err != ErrGameNotRunning {
+		t.Errorf("got %v finishing twice want %v", err, ErrGameNotRunning)
+	}
+
+	assertScoreEquals(t, getScore(t, store, "Manu"), 1)
+	assertScoreEquals(t, getScore(t, store, "Cleo"), 0)
+	if games := getGames(t, store); len(games) != 1 {
+		t.Errorf("got %d games recorded want 1", len(games))
+	}
+}
+
+func TestGame_Tournament(t *testing.T) {
+	t.Run("records the finishing order and when each player went out", func(t *testing.T) {
+		store := newRegisteredPlayerStore(t, "Alice", "Bob", "Carol", "Dave", "Erin")
+		game := NewTexasHoldem(dummyBlindAlerter, store)
+		assertNoError(t, game.Start(context.Background(), 5, "", ioutil.Discard))
+
+		assertNoError(t, game.Eliminate("Alice"))
+		assertNoError(t, game.Eliminate("Carol"))
+		assertNoError(t, game.Rebuy("Carol"))
+		assertNoError(t, game.Eliminate("Bob"))
+		assertNoError(t, game.Eliminate("Carol"))
+		assertNoError(t, game.Eliminate("Erin"))
+		assertNoError(t, game.Finish("Dave"))
+
+		got := getGames(t, store)[0]
+		wantOrder := []string{"Dave", "Erin", "Carol", "Bob", "Alice"}
+		if !reflect.DeepEqual(got.FinishingOrder, wantOrder) {
+			t.Errorf("got finishing order %v want %v", got.FinishingOrder, wantOrder)
+		}
+		if !reflect.DeepEqual(got.Participants, wantOrder) {
+			t.Errorf("got participants %v want %v", got.Participants, wantOrder)
+		}
+		if !reflect.DeepEqual(got.Rebuys, map[string]int{"Carol": 1}) {
+			t.Errorf("got rebuys %v want Carol's one rebuy", got.Rebuys)
+		}
+
+		var positions []string
+		for i, e := range got.Eliminations {
+			positions = append(positions, fmt.Sprintf("%s %d", e.Player, e.Position))
+			if e.At.Before(got.StartedAt) || e.At.After(got.EndedAt) || (i > 0 && e.At.Before(got.Eliminations[i-1].At)) {
+				t.Errorf("%s went out at %v, outside the game or before the player out before them", e.Player, e.At)
+			}
+		}
+		wantPositions := []string{"Alice 5", "Bob 4", "Carol 3", "Erin 2"}
+		if !reflect.DeepEqual(positions, wantPositions) {
+			t.Errorf("got positions %v want %v", positions, wantPositions)
+		}
+	})
+
+	t.Run("the first player out finishes last of everyone who started", func(t *testing.T) {
+		store := newRegisteredPlayerStore(t, "Alice", "Dave")
+		game := NewTexasHoldem(dummyBlindAlerter, store)
+		assertNoError(t, game.Start(context.Background(), 9, "", ioutil.Discard))
+
+		assertNoError(t, game.Eliminate("Alice"))
+		assertNoError(t, game.Finish("Dave"))
+
+		got := getGames(t, store)[0]
+		if len(got.Eliminations) != 1 || got.Eliminations[0].Position != 9 {
+			t.Errorf("got eliminations %+v want Alice in 9th place", got.Eliminations)
+		}
+	})
+
+	t.Run("a player can only be knocked out once", func(t *testing.T) {
+		game := NewTexasHoldem(dummyBlindAlerter, newRegisteredPlayerStore(t, "Alice"))
+		assertNoError(t, game.Start(context.Background(), 5, "", ioutil.Discard))
+
+		assertNoError(t, game.Eliminate("Alice"))
+		if err := game.Eliminate("Alice"); err == nil {
+			t.Error("expected an error knocking Alice out twice")
+		}
+	})
+
+	t.Run("everyone seated is recorded as playing", func(t *testing.T) {
+		store := newRegisteredPlayerStore(t, "Alice", "Bob", "Carol")
+		game := NewTexasHoldem(dummyBlindAlerter, store)
+		assertNoError(t, game.Start(context.Background(), 2, "", ioutil.Discard))
+		assertNoError(t, game.Seat("Alice", "Bob", "Carol"))
+
+		assertNoError(t, game.Eliminate("Bob"))
+		assertNoError(t, game.Finish("Alice"))
+
+		got := getGames(t, store)[0]
+		if want := []string{"Alice", "Bob", "Carol"}; !reflect.DeepEqual(got.Participants, want) {
+			t.Errorf("got participants %v want %v", got.Participants, want)
+		}
+		if len(got.Eliminations) != 1 || got.Eliminations[0].Position != 3 {
+			t.Errorf("got eliminations %+v want Bob in 3rd place", got.Eliminations)
+		}
+	})
+
+	t.Run("only the players seated can be knocked out or buy back in", func(t *testing.T) {
+		game := NewTexasHoldem(dummyBlindAlerter, newRegisteredPlayerStore(t, "Alice", "Bob"))
+		assertNoError(t, game.Start(context.Background(), 2, "", ioutil.Discard))
+		assertNoError(t, game.Seat("Alice"))
+
+		if err := game.Eliminate("Bob"); err == nil {
+			t.Error("expected an error knocking out Bob, who isn't seated")
+		}
+		if err := game.Rebuy("Bob"); err == nil {
+			t.Error("expected an error buying Bob back in, who isn't seated")
+		}
+	})
+
+	t.Run("seating more players than the game started for reschedules the blind clock", func(t *testing.T) {
+		blindAlerter := &SpyBlindAlerter{}
+		fake := clock.NewFake(time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC))
+		game := NewTexasHoldem(blindAlerter, newRegisteredPlayerStore(t, "Alice", "Bob", "Carol", "Dave", "Erin"), WithClock(fake))
+		assertNoError(t, game.Start(context.Background(), 3, "", ioutil.Discard))
+		firstAlerts := blindAlerter.ctx
+
+		fake.Advance(7 * time.Minute)
+		blindAlerter.alerts = nil
+		assertNoError(t, game.Seat("Alice", "Bob", "Carol", "Dave", "Erin"))
+		assertDone(t, firstAlerts)
+
+		// levels last 10 minutes for 5 players rather than 8 for 3.
+		checkSchedulingCase(t, []ScheduledAlert{
+			{3 * time.Minute, 200},
+			{13 * time.Minute, 300},
+		}, blindAlerter)
+		if status := game.Status(); status.Level != 1 || status.NextLevelSeconds != 180 {
+			t.Errorf("got %+v want level 1 with 3 minutes to go", status)
+		}
+	})
+
+	t.Run("players can't be seated once hands have been dealt", func(t *testing.T) {
+		game := NewTexasHoldem(dummyBlindAlerter, newRegisteredPlayerStore(t, "Alice", "Bob", "Carol"), WithStartingStack(1000))
+		assertNoError(t, game.Start(context.Background(), 3, "", ioutil.Discard))
+		assertNoError(t, game.Seat("Alice", "Bob"))
+		assertNoError(t, game.Rebuy("Alice"))
+
+		assertNoError(t, game.Seat("Alice", "Bob", "Carol"))
+		if game.stacks["Alice"] != 2000 || game.stacks["Carol"] != 1000 {
+			t.Errorf("got stacks %v want Alice to keep her rebuy and Carol to get the starting stack", game.stacks)
+		}
+
+		assertNoError(t, game.Deal())
+		if err := game.Seat("Alice", "Bob"); !errors.Is(err, ErrHandsDealt) {
+			t.Errorf("got error %v seating players mid-game want %v", err, ErrHandsDealt)
+		}
+	})
+
+	t.Run("only registered players can be seated, once", func(t *testing.T) {
+		game := NewTexasHoldem(dummyBlindAlerter, newRegisteredPlayerStore(t, "Alice"))
+		assertNoError(t, game.Start(context.Background(), 2, "", ioutil.Discard))
+
+		if err := game.Seat("Alice", "Nobody"); !errors.Is(err, ErrPlayerNotFound) {
+			t.Errorf("got error %v seating Nobody want %v", err, ErrPlayerNotFound)
+		}
+		if err := game.Seat("Alice", "Alice"); err == nil {
+			t.Error("expected an error seating Alice twice")
+		}
+	})
+
+	t.Run("only registered players can be knocked out or buy back in", func(t *testing.T) {
+		store := newRegisteredPlayerStore(t, "Alice")
+		game := NewTexasHoldem(dummyBlindAlerter, store)
+		assertNoError(t, game.Start(context.Background(), 5, "", ioutil.Discard))
+
+		if err := game.Eliminate("Nobody"); !errors.Is(err, ErrPlayerNotFound) {
+			t.Errorf("got error %v knocking out Nobody want %v", err, ErrPlayerNotFound)
+		}
+		if err := game.Rebuy("Nobody"); !errors.Is(err, ErrPlayerNotFound) {
+			t.Errorf("got error %v buying Nobody back in want %v", err, ErrPlayerNotFound)
+		}
+
+		assertNoError(t, game.Register("Nobody"))
+		assertNoError(t, game.Eliminate("Nobody"))
+		assertNoError(t, game.Rebuy("Nobody"))
+	})
+}
+
+func TestGame_Dealing(t *testing.T) {
+	newGame := func(t *testing.T, out io.Writer) *TexasHoldem {
+		t.Helper()
+		game := NewTexasHoldem(dummyBlindAlerter, newRegisteredPlayerStore(t, "Alice", "Bob", "Carol"), WithDeck(NewDeck))
+		assertNoError(t, game.Start(context.Background(), 3, "", out))
+		return game
+	}
+
+	act := func(t *testing.T, game *TexasHoldem, actions ...Action) {
+		t.Helper()
+		for _, action := range actions {
+			assertNoError(t, game.Act(action))
+		}
+	}
+
+	t.Run("deals the players still in a hand, then each street, then shows it down", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		game := newGame(t, out)
+		assertNoError(t, game.Seat("Alice", "Bob", "Carol"))
+		assertNoError(t, game.Eliminate("Carol"))
+
+		assertNoError(t, game.Deal())
+		act(t, game, Action{Player: "Alice", Type: ActionCall}, Action{Player: "Bob", Type: ActionCheck})
+		for i := 0; i < 3; i++ {
+			assertNoError(t, game.Deal())
+			act(t, game, Action{Player: "Bob", Type: ActionCheck}, Action{Player: "Alice", Type: ActionCheck})
+		}
+		results, err := game.Showdown()
+		assertNoError(t, err)
+
+		// the deck isn't shuffled, so the board makes a flush both players share.
+		want := "Hole cards dealt to Alice, Bob\n" +
+			"Pot 300, Alice to act on the preflop, 100 to call\n" +
+			"Pot 400, Bob to act on the preflop\n" +
+			"Pot 400, Bob to act on the flop\n" +
+			"Flop: 7c 8c 9c\n" +
+			"Pot 400, Bob to act on the flop\n" +
+			"Pot 400, Alice to act on the flop\n" +
+			"Pot 400, Bob to act on the turn\n" +
+			"Turn: 7c 8c 9c Jc\n" +
+			"Pot 400, Bob to act on the turn\n" +
+			"Pot 400, Alice to act on the turn\n" +
+			"Pot 400, Bob to act on the river\n" +
+			"River: 7c 8c 9c Jc Kc\n" +
+			"Pot 400, Bob to act on the river\n" +
+			"Pot 400, Alice to act on the river\n" +
+			"Pot 400, betting is over for the hand\n" +
+			"Alice shows 2c 4c, flush (Kc Jc 9c 8c 7c)\n" +
+			"Bob shows 3c 5c, flush (Kc Jc 9c 8c 7c)\n" +
+			"Alice and Bob split the pot\n"
+		if out.String() != want {
+			t.Errorf("got announcements %q want %q", out.String(), want)
+		}
+		if winners := Winners(results); !reflect.DeepEqual(winners, []string{"Alice", "Bob"}) {
+			t.Errorf("got winners %v want Alice and Bob", winners)
+		}
+	})
+
+	t.Run("a new hand is dealt once the last is shown down", func(t *testing.T) {
+		game := newGame(t, ioutil.Discard)
+		assertNoError(t, game.Seat("Alice", "Bob"))
+
+		if _, err := game.Showdown(); !errors.Is(err, ErrNoHand) {
+			t.Errorf("got error %v showing down before dealing want %v", err, ErrNoHand)
+		}
+		assertNoError(t, game.Deal())
+		if _, err := game.Showdown(); !errors.Is(err, ErrBettingNotOver) {
+			t.Errorf("got error %v showing down while betting want %v", err, ErrBettingNotOver)
+		}
+		if err := game.Deal(); !errors.Is(err, ErrStreetNotOver) {
+			t.Errorf("got error %v dealing while betting want %v", err, ErrStreetNotOver)
+		}
+		act(t, game, Action{Player: "Alice", Type: ActionAllIn}, Action{Player: "Bob", Type: ActionCall})
+		if _, err := game.Showdown(); err == nil {
+			t.Error("expected an error showing down before the river")
+		}
+		for i := 0; i < 3; i++ {
+			assertNoError(t, game.Deal())
+		}
+		if err := game.Deal(); !errors.Is(err, ErrHandComplete) {
+			t.Errorf("got error %v dealing past the river want %v", err, ErrHandComplete)
+		}
+		_, err := game.Showdown()
+		assertNoError(t, err)
+		assertNoError(t, game.Deal())
+	})
+
+	t.Run("illegal actions are refused", func(t *testing.T) {
+		game := newGame(t, ioutil.Discard)
+		assertNoError(t, game.Seat("Alice", "Bob"))
+
+		if err := game.Act(Action{Player: "Alice", Type: ActionCall}); !errors.Is(err, ErrNoHand) {
+			t.Errorf("got error %v acting before dealing want %v", err, ErrNoHand)
+		}
+		assertNoError(t, game.Deal())
+
+		var illegal *IllegalActionError
+		if err := game.Act(Action{Player: "Bob", Type: ActionCheck}); !errors.As(err, &illegal) || !errors.Is(err, ErrNotYourTurn) {
+			t.Errorf("got error %v acting out of turn want %v", err, ErrNotYourTurn)
+		}
+		act(t, game, Action{Player: "Alice", Type: ActionCall}, Action{Player: "Bob", Type: ActionCheck})
+		if err := game.Act(Action{Player: "Bob", Type: ActionCheck}); !errors.As(err, &illegal) || !errors.Is(err, ErrStreetNotDealt) {
+			t.Errorf("got error %v acting before the flop is dealt want %v", err, ErrStreetNotDealt)
+		}
+	})
+
+	t.Run("a player left alone in the hand wins it without a showdown", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		game := newGame(t, out)
+		assertNoError(t, game.Seat("Alice", "Bob"))
+
+		assertNoError(t, game.Deal())
+		act(t, game, Action{Player: "Alice", Type: ActionFold})
+		if err := game.Deal(); !errors.Is(err, ErrHandOver) {
+			t.Errorf("got error %v dealing after everyone else folded want %v", err, ErrHandOver)
+		}
+		out.Reset()
+		results, err := game.Showdown()
+		assertNoError(t, err)
+
+		if len(results) != 0 || out.String() != "Bob wins the hand\n" {
+			t.Errorf("got results %v and announcements %q want Bob to win the hand", results, out.String())
+		}
+		if game.stacks["Alice"] != 9900 || game.stacks["Bob"] != 10100 {
+			t.Errorf("got stacks %v want Bob to have won Alice's small blind", game.stacks)
+		}
+	})
+
+	t.Run("a player who buys back in during a hand gets the chips once it is over", func(t *testing.T) {
+		store := newRegisteredPlayerStore(t, "Alice", "Bob")
+		game := NewTexasHoldem(dummyBlindAlerter, store, WithDeck(NewDeck), WithStartingStack(1000))
+		assertNoError(t, game.Start(context.Background(), 2, "", ioutil.Discard))
+		assertNoError(t, game.Seat("Alice", "Bob"))
+
+		assertNoError(t, game.Deal())
+		act(t, game, Action{Player: "Alice", Type: ActionAllIn}, Action{Player: "Bob", Type: ActionCall})
+		assertNoError(t, game.Rebuy("Bob"))
+		for i := 0; i < 3; i++ {
+			assertNoError(t, game.Deal())
+		}
+		_, err := game.Showdown()
+		assertNoError(t, err)
+
+		if game.stacks["Alice"] != 1000 || game.stacks["Bob"] != 2000 {
+			t.Errorf("got stacks %v want Bob to have his share of the pot and his rebuy", game.stacks)
+		}
+
+		assertNoError(t, game.Finish("Alice"))
+		transactions, err := store.GetChipTransactions("Bob")
+		assertNoError(t, err)
+		if len(transactions) != 1 || transactions[0].Type != ChipRebuy || transactions[0].Amount != 1000 ||
+			transactions[0].Game != getGames(t, store)[0].ID {
+			t.Errorf("got transactions %+v want Bob's rebuy in the game played", transactions)
+		}
+	})
+
+	t.Run("players who lose all their chips are knocked out", func(t *testing.T) {
+		game := NewTexasHoldem(dummyBlindAlerter, newRegisteredPlayerStore(t, "Alice", "Bob", "Carol"), WithDeck(NewDeck), WithStartingStack(1000))
+		assertNoError(t, game.Start(context.Background(), 3, "", ioutil.Discard))
+		assertNoError(t, game.Seat("Alice", "Bob", "Carol"))
+
+		assertNoError(t, game.Deal())
+		act(t, game,
+			Action{Player: "Alice", Type: ActionAllIn},
+			Action{Player: "Bob", Type: ActionCall},
+			Action{Player: "Carol", Type: ActionCall},
+		)
+		for i := 0; i < 3; i++ {
+			assertNoError(t, game.Deal())
+		}
+		_, err := game.Showdown()
+		assertNoError(t, err)
+
+		// Carol's 7c makes the best flush of the unshuffled deck.
+		if game.stacks["Carol"] != 3000 {
+			t.Errorf("got stacks %v want Carol to have won every chip", game.stacks)
+		}
+		if game.eliminated("Alice") < 0 || game.eliminated("Bob") < 0 || game.eliminated("Carol") >= 0 {
+			t.Errorf("got eliminations %v want Alice and Bob knocked out", game.eliminations)
+		}
+
+		assertNoError(t, game.Rebuy("Bob"))
+		if game.stacks["Bob"] != 1000 {
+			t.Errorf("got Bob's stack %d after buying back in want 1000", game.stacks["Bob"])
+		}
+	})
+
+	t.Run("only deals to seated players while the game is running", func(t *testing.T) {
+		game := newGame(t, ioutil.Discard)
+		if err := game.Deal(); !errors.Is(err, ErrNobodySeated) {
+			t.Errorf("got error %v want %v", err, ErrNobodySeated)
+		}
+
+		assertNoError(t, game.Seat("Alice", "Bob"))
+		assertNoError(t, game.Finish("Alice"))
+		if err := game.Deal(); !errors.Is(err, ErrGameNotRunning) {
+			t.Errorf("got error %v dealing after the game finished want %v", err, ErrGameNotRunning)
+		}
+	})
+}
+
+func TestGame_BlindClock(t *testing.T) {
+	t.Run("announces every level of the standard structure as the clock reaches it", func(t *testing.T) {
+		fake := clock.NewFake(time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC))
+		game := NewTexasHoldem(NewAlerter(fake), dummyPlayerStore, WithClock(fake))
+		out := &bytes.Buffer{}
+		assertNoError(t, game.Start(context.Background(), 5, "", out))
+
+		// with 5 players every level lasts 10 minutes, with warnings a
+		// minute and 10 seconds before it ends.
+		var want strings.Builder
+		for i, level := range StandardBlindStructure.Levels {
+			if i == 0 {
+				fake.Advance(0)
+			} else {
+				fake.Advance(9 * time.Minute)
+				fmt.Fprintf(&want, "Blinds go up to %d in 1 minute\n", level.SmallBlind)
+				fake.Advance(50 * time.Second)
+				fmt.Fprintf(&want, "Blinds go up to %d in 10 seconds\n", level.SmallBlind)
+				fake.Advance(9 * time.Second)
+				if out.String() != want.String() {
+					t.Fatalf("got %q want %q before level %d", out.String(), want.String(), i+1)
+				}
+				if status := game.Status(); status.NextSmallBlind != level.SmallBlind || status.NextLevelSeconds != 1 {
+					t.Errorf("got %+v want %d in 1 second", status, level.SmallBlind)
+				}
+				fake.Advance(time.Second)
+			}
+
+			fmt.Fprintf(&want, "Blind is now %d\n", level.SmallBlind)
+			if out.String() != want.String() {
+				t.Fatalf("got %q want %q", out.String(), want.String())
+			}
+			if status := game.Status(); status.Level != i+1 || status.SmallBlind != level.SmallBlind {
+				t.Errorf("got %+v want level %d at %d", status, i+1, level.SmallBlind)
+			}
+		}
+	})
+
+	t.Run("finishing the game stops the alerts", func(t *testing.T) {
+		fake := clock.NewFake(time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC))
+		game := NewTexasHoldem(NewAlerter(fake), &StubPlayerStore{mu: &sync.RWMutex{}}, WithClock(fake))
+		out := &bytes.Buffer{}
+		assertNoError(t, game.Start(context.Background(), 5, "", out))
+		fake.Advance(0)
+
+		game.Finish("Manu")
+		fake.Advance(24 * time.Hour)
+
+		if out.String() != "Blind is now 100\n" {
+			t.Errorf("got %q want only the first level announced", out.String())
+		}
+	})
+}
+
+func TestGame_Warnings(t *testing.T) {
+	t.Run("schedules warnings ahead of every level after the first", func(t *testing.T) {
+		blindAlerter := &SpyBlindAlerter{}
+		game := NewTexasHoldem(blindAlerter, dummyPlayerStore)
+		assertNoError(t, game.Start(context.Background(), 5, "", ioutil.Discard))
+
+		var want []ScheduledWarning
+		for i, level := range StandardBlindStructure.Levels[1:] {
+			levelStart := time.Duration(i+1) * 10 * time.Minute
+			want = append(want,
+				ScheduledWarning{levelStart - time.Minute, level.SmallBlind, time.Minute},
+				ScheduledWarning{levelStart - 10*time.Second, level.SmallBlind, 10 * time.Second},
+			)
+		}
+		assertWarnings(t, blindAlerter.warnings, want)
+	})
+
+	t.Run("skips warnings that would go off before the level they are given in", func(t *testing.T) {
+		blindAlerter := &SpyBlindAlerter{}
+		game := NewTexasHoldem(blindAlerter, dummyPlayerStore, WithBlindStructures(BlindStructure{
+			Name:     "hyper",
+			Warnings: Durations{time.Minute, 10 * time.Second},
+			Levels: []BlindLevel{
+				{SmallBlind: 10, BigBlind: 20, Duration: 30 * time.Second},
+				{SmallBlind: 20, BigBlind: 40, Duration: 2 * time.Minute},
+				{SmallBlind: 40, BigBlind: 80, Duration: 2 * time.Minute},
+			},
+		}))
+		assertNoError(t, game.Start(context.Background(), 5, "hyper", ioutil.Discard))
+
+		assertWarnings(t, blindAlerter.warnings, []ScheduledWarning{
+			{20 * time.Second, 20, 10 * time.Second},
+			{90 * time.Second, 40, time.Minute},
+			{140 * time.Second, 40, 10 * time.Second},
+		})
+	})
+
+	t.Run("resuming only schedules the warnings still to come", func(t *testing.T) {
+		blindAlerter := &SpyBlindAlerter{}
+		fake := clock.NewFake(time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC))
+		game := NewTexasHoldem(blindAlerter, dummyPlayerStore, WithClock(fake))
+		assertNoError(t, game.Start(context.Background(), 5, "", ioutil.Discard))
+
+		fake.Advance(9*time.Minute + 30*time.Second)
+		assertNoError(t, game.Pause())
+		blindAlerter.warnings = nil
+		fake.Advance(time.Hour)
+		assertNoError(t, game.Resume())
+
+		if len(blindAlerter.warnings) < 2 {
+			t.Fatalf("got %v want the warnings left rescheduled", blindAlerter.warnings)
+		}
+		assertWarnings(t, blindAlerter.warnings[:2], []ScheduledWarning{
+			{20 * time.Second, 200, 10 * time.Second},
+			{9*time.Minute + 30*time.Second, 300, time.Minute},
+		})
+	})
+}
+
+func assertWarnings(t testing.TB, got, want []ScheduledWarning) {
+	t.Helper()
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got warnings %v want %v", got, want)
+	}
+}
+
+func TestGame_PauseResume(t *testing.T) {
+	start := func(t *testing.T) (*TexasHoldem, *SpyBlindAlerter, *bytes.Buffer, func(time.Duration)) {
+		blindAlerter := &SpyBlindAlerter{}
+		fake := clock.NewFake(time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC))
+		game := NewTexasHoldem(blindAlerter, dummyPlayerStore, WithClock(fake))
+
+		out := &bytes.Buffer{}
+		assertNoError(t, game.Start(context.Background(), 5, "", out))
+		return game, blindAlerter, out, fake.Advance
+	}
+
+	t.Run("pausing freezes the blind clock and resuming moves the remaining alerts back", func(t *testing.T) {
+		game, blindAlerter, out, advance := start(t)
+		firstAlerts := blindAlerter.ctx
+
+		advance(15 * time.Minute)
+		assertNoError(t, game.Pause())
+		assertDone(t, firstAlerts)
+
+		advance(45 * time.Minute)
+		if status := game.Status(); !status.Paused || status.Level != 2 {
+			t.Errorf("got %+v want level 2 paused", status)
+		}
+
+		blindAlerter.alerts = nil
+		assertNoError(t, game.Resume())
+		assertNotDone(t, blindAlerter.ctx)
+
+		checkSchedulingCase(t, []ScheduledAlert{
+			{5 * time.Minute, 300},
+			{15 * time.Minute, 400},
+			{25 * time.Minute, 500},
+			{35 * time.Minute, 600},
+			{45 * time.Minute, 800},
+			{55 * time.Minute, 1000},
+			{65 * time.Minute, 2000},
+			{75 * time.Minute, 4000},
+			{85 * time.Minute, 8000},
+		}, blindAlerter)
+		if len(blindAlerter.alerts) != 9 {
+			t.Errorf("got %d alerts rescheduled want 9, %v", len(blindAlerter.alerts), blindAlerter.alerts)
+		}
+
+		advance(5 * time.Minute)
+		if status := game.Status(); status.Paused || status.Level != 3 {
+			t.Errorf("got %+v want level 3 running", status)
+		}
+
+		want := "Game paused, the blind clock is stopped\nGame resumed, blinds go up to 300 in 5 minutes\n"
+		if out.String() != want {
+			t.Errorf("got %q announced want %q", out.String(), want)
+		}
+	})
+
+	t.Run("only a running game can be paused and only a paused game resumed", func(t *testing.T) {
+		game, _, _, _ := start(t)
+
+		if err := game.Resume(); err != ErrGameNotPaused {
+			t.Errorf("got %v want %v", err, ErrGameNotPaused)
+		}
+		assertNoError(t, game.Pause())
+		if err := game.Pause(); err != ErrGamePaused {
+			t.Errorf("got %v want %v", err, ErrGamePaused)
+		}
+
+		game.Finish("Manu")
+		if err := game.Resume(); err != ErrGameNotRunning {
+			t.Errorf("got %v want %v", err, ErrGameNotRunning)
+		}
+
+		notStarted := NewTexasHoldem(dummyBlindAlerter, dummyPlayerStore)
+		if err := notStarted.Pause(); err != ErrGameNotRunning {
+			t.Errorf("got %v want %v", err, ErrGameNotRunning)
+		}
+	})
+}
+
+func TestGame_Status(t *testing.T) {
+	game := NewTexasHoldem(dummyBlindAlerter, dummyPlayerStore)
+
+	err := game.Start(context.Background(), 5, "", ioutil.Discard)
+	assertNoError(t, err)
+
+	got := game.Status()
+	if got.Players != 5 || got.BlindStructure != DefaultBlindStructure || got.Level != 1 || got.SmallBlind != 100 {
+		t.Errorf("got %+v, want level 1 of the %s structure for 5 players", got, DefaultBlindStructure)
+	}
+	if got.NextSmallBlind != 200 || got.NextLevelSeconds != 600 {
+		t.Errorf("got %+v, want blinds up to 200 in 600 seconds", got)
+	}
+	if want := "blinds go up to 200 in 10 minutes"; got.Countdown() != want {
+		t.Errorf("got countdown %q want %q", got.Countdown(), want)
+	}
+}
+
+func TestGame_Cancel(t *testing.T) {
+	t.Run("finishing the game cancels pending alerts", func(t *testing.T) {
+		blindAlerter := &SpyBlindAlerter{}
+		game := NewTexasHoldem(blindAlerter, newRegisteredPlayerStore(t, "Manu"))
+
+		err := game.Start(context.Background(), 5, "", ioutil.Discard)
+		assertNoError(t, err)
+		assertNotDone(t, blindAlerter.ctx)
+
+		assertNoError(t, game.Finish("Manu"))
+		assertDone(t, blindAlerter.ctx)
+	})
+
+	t.Run("the game carries on when the winner isn't registered", func(t *testing.T) {
+		blindAlerter := &SpyBlindAlerter{}
+		store := newRegisteredPlayerStore(t, "Manu")
+		game := NewTexasHoldem(blindAlerter, store)
+		assertNoError(t, game.Start(context.Background(), 5, "", ioutil.Discard))
+
+		if err := game.Finish("Nobody"); !errors.Is(err, ErrPlayerNotFound) {
+			t.Errorf("got error %v want %v", err, ErrPlayerNotFound)
+		}
+		assertNotDone(t, blindAlerter.ctx)
+		if games := getGames(t, store); len(games) != 0 {
+			t.Errorf("got games %+v recorded want none", games)
+		}
+	})
+
+	t.Run("cancelling the start context cancels pending alerts", func(t *testing.T) {
+		blindAlerter := &SpyBlindAlerter{}
+		game := NewTexasHoldem(blindAlerter, NewInMemoryPlayerStore())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		err := game.Start(ctx, 5, "", ioutil.Discard)
+		assertNoError(t, err)
+
+		cancel()
+		assertDone(t, blindAlerter.ctx)
+	})
+}
+
+func assertDone(t testing.TB, ctx context.Context) {
+	t.Helper()
+	select {
+	case <-ctx.Done():
+	case <-time.After(500 * time.Millisecond):
+		t.Error("expected context to be done")
+	}
+}
+
+func assertNotDone(t testing.TB, ctx context.Context) {
+	t.Helper()
+	if ctx.Err() != nil {
+		t.Errorf("expected context not to be done, got %v", ctx.Err())
+	}
+}
+
 func checkSchedulingCase(t *testing.T, cases []ScheduledAlert, blindAlerter *SpyBlindAlerter) {
 	t.Helper()
 	for i, want := range cases {
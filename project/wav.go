@@ -0,0 +1,88 @@
+package poker
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// audioSampleRate is the number of samples a second of the audio alerts.
+const audioSampleRate = 8000
+
+// tone is a sine wave of frequency hertz lasting for duration, silence when frequency is 0.
+type tone struct {
+	frequency float64
+	duration  time.Duration
+}
+
+// renderTones turns tones into 16 bit samples at audioSampleRate. Each tone
+// fades in and out over a few milliseconds so it doesn't click.
+func renderTones(tones []tone) []int16 {
+	const amplitude = 0.5 * math.MaxInt16
+	fade := audioSampleRate * 5 / 1000
+
+	var samples []int16
+	for _, t := range tones {
+		n := int(t.duration * audioSampleRate / time.Second)
+		for i := 0; i < n; i++ {
+			if t.frequency == 0 {
+				samples = append(samples, 0)
+				continue
+			}
+			gain := math.Min(1, float64(min(i, n-1-i))/float64(fade))
+			phase := 2 * math.Pi * t.frequency * float64(i) / audioSampleRate
+			samples = append(samples, int16(amplitude*gain*math.Sin(phase)))
+		}
+	}
+	return samples
+}
+
+// writeWAV writes samples as a mono 16 bit PCM WAV file.
+func writeWAV(w io.Writer, sampleRate int, samples []int16) error {
+	const (
+		channels      = 1
+		bitsPerSample = 16
+		blockAlign    = channels * bitsPerSample / 8
+	)
+	dataSize := len(samples) * blockAlign
+
+	header := struct {
+		RIFF          [4]byte
+		Size          uint32
+		WAVE          [4]byte
+		Fmt           [4]byte
+		FmtSize       uint32
+		Format        uint16
+		Channels      uint16
+		SampleRate    uint32
+		ByteRate      uint32
+		BlockAlign    uint16
+		BitsPerSample uint16
+		Data          [4]byte
+		DataSize      uint32
+	}{
+		RIFF:          [4]byte{'R', 'I', 'F', 'F'},
+		Size:          uint32(36 + dataSize),
+		WAVE:          [4]byte{'W', 'A', 'V', 'E'},
+		Fmt:           [4]byte{'f', 'm', 't', ' '},
+		FmtSize:       16,
+		Format:        1, // PCM
+		Channels:      channels,
+		SampleRate:    uint32(sampleRate),
+		ByteRate:      uint32(sampleRate * blockAlign),
+		BlockAlign:    blockAlign,
+		BitsPerSample: bitsPerSample,
+		Data:          [4]byte{'d', 'a', 't', 'a'},
+		DataSize:      uint32(dataSize),
+	}
+
+	if err := binary.Write(w, binary.LittleEndian, header); err != nil {
+		return fmt.Errorf("problem writing WAV header, %v", err)
+	}
+	if err := binary.Write(w, binary.LittleEndian, samples); err != nil {
+		return fmt.Errorf("problem writing WAV samples, %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package poker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Types of message sent over the /ws websocket.
+const (
+	// WSStart is sent by a client to start a game, with the number of
+	// players and optionally the names of the players seated at it.
+	WSStart = "start"
+	// WSFinish is sent by a client to finish a game with a winner.
+	WSFinish = "finish"
+	// WSPause is sent by a client to stop the blind clock for a break.
+	WSPause = "pause"
+	// WSResume is sent by a client to restart the blind clock.
+	WSResume = "resume"
+	// WSDeal is sent by a client to deal a hand, then each street of it.
+	WSDeal = "deal"
+	// WSShowdown is sent by a client to show down the hand once the river is dealt.
+	WSShowdown = "showdown"
+	// WSAction is sent by a client with the betting action of the player it
+	// joined as, e.g. {"type":"action","action":"raise","amount":400}. A
+	// player named in it must be that player.
+	WSAction = "action"
+	// WSStarted is sent by the server with the ID of the game it started.
+	WSStarted = "started"
+	// WSBlind is sent by the server when the blind goes up.
+	WSBlind = "blind"
+	// WSWarning is sent by the server ahead of the blind going up to amount in seconds.
+	WSWarning = "warning"
+	// WSHand is sent by the server with the board as it is dealt, how many
+	// hole cards everyone holds and the client's own hole cards.
+	WSHand = "hand"
+	// WSResults is sent by the server with the hole cards and best hand of
+	// everyone shown down, and the winners.
+	WSResults = "results"
+	// WSBetting is sent by the server with how the betting stands after each deal and action.
+	WSBetting = "betting"
+	// WSText is sent by the server for any other announcement.
+	WSText = "text"
+	// WSError is sent by the server when a client's message can't be acted on.
+	WSError = "error"
+)
+
+// WSMessage is a message sent over the /ws websocket, e.g.
+// {"type":"start","players":5}, {"type":"start","seats":["Chris","Cleo"]},
+// {"type":"blind","amount":200} or {"type":"warning","amount":400,"seconds":60}.
+type WSMessage struct {
+	Type           string        `json:"type"`
+	Players        int           `json:"players,omitempty"`
+	Seats          []string      `json:"seats,omitempty"`
+	BlindStructure string        `json:"blind_structure,omitempty"`
+	Winner         string        `json:"winner,omitempty"`
+	Player         string        `json:"player,omitempty"`
+	Action         string        `json:"action,omitempty"`
+	Game           string        `json:"game,omitempty"`
+	Amount         int           `json:"amount,omitempty"`
+	Seconds        int           `json:"seconds,omitempty"`
+	Hand           *HandView     `json:"hand,omitempty"`
+	Results        []HandResult  `json:"results,omitempty"`
+	Winners        []string      `json:"winners,omitempty"`
+	Betting        *BettingState `json:"betting,omitempty"`
+	Text           string        `json:"text,omitempty"`
+	Error          string        `json:"error,omitempty"`
+}
+
+// parseWSMessage decodes and validates a message sent by a client.
+func parseWSMessage(data []byte) (WSMessage, error) {
+	var msg WSMessage
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return WSMessage{}, fmt.Errorf("invalid message %q, %v", data, err)
+	}
+
+	switch msg.Type {
+	case WSStart:
+		if msg.Players == 0 {
+			msg.Players = len(msg.Seats)
+		}
+		if msg.Players < 1 {
+			return WSMessage{}, errors.New(BadPlayerInputErrMsg)
+		}
+		if msg.Players < len(msg.Seats) {
+			return WSMessage{}, fmt.Errorf("%d players seated at a game of %d", len(msg.Seats), msg.Players)
+		}
+	case WSFinish:
+		if msg.Winner == "" {
+			return WSMessage{}, errors.New("finish message needs a winner")
+		}
+	case WSAction:
+		if msg.Action == "" {
+			return WSMessage{}, errors.New("action message needs an action")
+		}
+	case WSPause, WSResume, WSDeal, WSShowdown:
+	default:
+		return WSMessage{}, fmt.Errorf("unknown message type %q", msg.Type)
+	}
+
+	return msg, nil
+}
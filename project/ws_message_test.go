@@ -0,0 +1,50 @@
+package poker
+
+import "testing"
+
+func TestParseWSMessage(t *testing.T) {
+	valid := []struct {
+		data string
+		want WSMessage
+	}{
+		{`{"type":"start","players":5}`, WSMessage{Type: WSStart, Players: 5}},
+		{`{"type":"start","players":5,"blind_structure":"turbo"}`, WSMessage{Type: WSStart, Players: 5, BlindStructure: "turbo"}},
+		{`{"type":"start","seats":["Chris","Cleo"]}`, WSMessage{Type: WSStart, Players: 2, Seats: []string{"Chris", "Cleo"}}},
+		{`{"type":"start","players":5,"seats":["Chris"]}`, WSMessage{Type: WSStart, Players: 5, Seats: []string{"Chris"}}},
+		{`{"type":"finish","winner":"Chris"}`, WSMessage{Type: WSFinish, Winner: "Chris"}},
+		{`{"type":"pause"}`, WSMessage{Type: WSPause}},
+		{`{"type":"resume"}`, WSMessage{Type: WSResume}},
+		{`{"type":"deal"}`, WSMessage{Type: WSDeal}},
+		{`{"type":"showdown"}`, WSMessage{Type: WSShowdown}},
+		{`{"type":"action","action":"raise","amount":400}`, WSMessage{Type: WSAction, Action: ActionRaise, Amount: 400}},
+		{`{"type":"action","player":"Chris","action":"call"}`, WSMessage{Type: WSAction, Player: "Chris", Action: ActionCall}},
+	}
+
+	for _, tt := range valid {
+		t.Run(tt.data, func(t *testing.T) {
+			got, err := parseWSMessage([]byte(tt.data))
+			assertNoError(t, err)
+			assertWSMessage(t, got, tt.want)
+		})
+	}
+
+	invalid := []string{
+		`5`,
+		`not json`,
+		`{"type":"start"}`,
+		`{"type":"start","players":-1}`,
+		`{"type":"start","players":1,"seats":["Chris","Cleo"]}`,
+		`{"type":"finish"}`,
+		`{"type":"action","player":"Chris"}`,
+		`{"type":"blind","amount":100}`,
+		`{"type":"hand"}`,
+	}
+
+	for _, data := range invalid {
+		t.Run(data, func(t *testing.T) {
+			if _, err := parseWSMessage([]byte(data)); err == nil {
+				t.Errorf("expected an error for %s but didn't get one", data)
+			}
+		})
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+
+	"learn-go-with-tests/clock"
 )
 
 const tenSecondTimeout = 10 * time.Second
@@ -13,12 +15,17 @@ func Racer(a, b string) (winner string, err error) {
 }
 
 func ConfigurableRacer(a, b string, timeout time.Duration) (winner string, err error) {
+	return ClockedRacer(a, b, timeout, clock.Real{})
+}
+
+// ClockedRacer is ConfigurableRacer timing out on c.
+func ClockedRacer(a, b string, timeout time.Duration, c clock.Clock) (winner string, err error) {
 	select {
 	case <-ping(a):
 		return a, nil
 	case <-ping(b):
 		return b, nil
-	case <-time.After(timeout):
+	case <-c.After(timeout):
 		return "", fmt.Errorf("timed out waiting for %s and %s", a, b)
 	}
 }
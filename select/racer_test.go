@@ -5,6 +5,8 @@ import (
 	"net/http/httptest"
 	"testing"
 	"time"
+
+	"learn-go-with-tests/clock"
 )
 
 func TestRacer(t *testing.T) {
@@ -41,6 +43,34 @@ func TestRacer(t *testing.T) {
 	})
 }
 
+func TestClockedRacer(t *testing.T) {
+	hang := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-hang
+	}))
+	defer server.Close()
+	defer close(hang)
+
+	fake := clock.NewFake(time.Date(2026, 10, 1, 20, 0, 0, 0, time.UTC))
+	errs := make(chan error)
+	go func() {
+		_, err := ClockedRacer(server.URL, server.URL, tenSecondTimeout, fake)
+		errs <- err
+	}()
+
+	fake.BlockUntil(1)
+	fake.Advance(tenSecondTimeout)
+
+	select {
+	case err := <-errs:
+		if err == nil {
+			t.Error("expected an error but didn't get one")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("racer didn't time out once the clock was advanced")
+	}
+}
+
 func makeDelayedServer(delay time.Duration) *httptest.Server {
 	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		time.Sleep(delay)